@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/diagnostics"
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// adminRouter routes administrative API protected by the bearer token.
+// Limits apply to all routes but diagnostics, profiles and traces last for the requested seconds.
+func adminRouter(
+	cfg config.AdminConfig, limits func(http.Handler) http.Handler, l *slog.Logger, levels *logging.Levels,
+	maint *maintenance.Switch, flags *featureflag.Registry, started time.Time,
+) http.Handler {
+	router := chi.NewRouter()
+	router.Use(mw.BearerToken(cfg.Token))
+
+	router.Group(func(r chi.Router) {
+		r.Use(limits)
+		r.Handle("/log/levels", logging.LevelsHandler(levels))
+		r.Handle("/maintenance", maintenance.Handler(maint, l))
+		r.Mount("/flags", featureflag.Router(flags, adminActor))
+	})
+	if cfg.Diagnostics {
+		router.Mount("/debug", diagnostics.Router(l, started, cfg.SnapshotDir))
+	}
+
+	return router
+}
+
+// adminActor identifies administrator in audit logs by the client address,
+// all administrators share the same bearer token.
+func adminActor(r *http.Request) string {
+	if id := mw.UserIDFrom(r.Context()); id != "" {
+		return id
+	}
+	key, _ := mw.KeyByIP(r)
+	return key
+}
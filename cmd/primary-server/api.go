@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/apiversion"
+	"github.com/asmazovec/team-agile/internal/backlog"
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/asmazovec/team-agile/internal/metrics"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const apiPrefix = "/api"
+
+// apiRouter mounts versions of the API with deprecations of the config applied.
+func apiRouter(r chi.Router, cfg config.APIConfig, m *metrics.Deprecations) error {
+	wf := workflow.Default()
+	if cfg.WorkflowFile != "" {
+		var err error
+		if wf, err = workflow.LoadFile(cfg.WorkflowFile); err != nil {
+			return fmt.Errorf("default workflow: %w", err)
+		}
+	}
+	tasks := task.NewMemoryRepository()
+	boardRepo := board.NewMemoryRepository()
+	boards := board.NewService(boardRepo, tasks,
+		board.WithWorkflow(wf), board.WithNotifier(logTransition), board.WithOverloadNotifier(logOverload))
+	sprintRepo := sprint.NewMemoryRepository()
+	sprints := sprint.NewService(sprintRepo, boardRepo, tasks)
+	backlogs := backlog.NewService(tasks, sprintRepo)
+	versions := []apiversion.Version{
+		{Name: "v1", Routes: v1Routes(cfg, tasks, boards, sprints, backlogs)},
+	}
+	index := make(map[string]int, len(versions))
+	for i, v := range versions {
+		index[v.Name] = i
+	}
+
+	for name, since := range cfg.Deprecated {
+		i, ok := index[name]
+		if !ok {
+			return fmt.Errorf("deprecated API version %q is unknown", name)
+		}
+		info := &mw.DeprecationInfo{Link: cfg.DeprecationLink}
+		var err error
+		if info.Since, err = time.Parse(time.DateOnly, since); err != nil {
+			return fmt.Errorf("deprecation date of API version %q: %w", name, err)
+		}
+		if sunset, ok := cfg.Sunset[name]; ok {
+			if info.Sunset, err = time.Parse(time.DateOnly, sunset); err != nil {
+				return fmt.Errorf("sunset date of API version %q: %w", name, err)
+			}
+		}
+		if i+1 < len(versions) {
+			info.Successor = apiPrefix + "/" + versions[i+1].Name
+		}
+		versions[i].Deprecation = info
+	}
+	for name := range cfg.Sunset {
+		if _, ok := cfg.Deprecated[name]; !ok {
+			return fmt.Errorf("sunset API version %q is not deprecated", name)
+		}
+	}
+	if _, ok := index[cfg.DefaultVersion]; !ok {
+		return fmt.Errorf("default API version %q is unknown", cfg.DefaultVersion)
+	}
+
+	apiversion.Mount(r, apiPrefix, apiversion.Options{
+		Vendor:   cfg.Vendor,
+		Default:  cfg.DefaultVersion,
+		Versions: versions,
+		Metrics:  m,
+	})
+	return nil
+}
+
+func v1Routes(
+	cfg config.APIConfig, tasks task.Repository, boards *board.Service, sprints *sprint.Service, backlogs *backlog.Service,
+) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			render.JSON(w, r, map[string]string{"version": apiversion.From(r.Context())})
+		})
+		r.Mount("/tasks", task.Router(tasks, task.Options{
+			NewID:          idgen.UUIDv7,
+			RequireIfMatch: cfg.RequireIfMatch,
+			Save:           boards.Save,
+		}))
+		r.Mount("/boards", board.Router(boards, board.Options{
+			NewID:          idgen.UUIDv7,
+			RequireIfMatch: cfg.RequireIfMatch,
+		}))
+		r.Mount("/sprints", sprint.Router(sprints, sprint.Options{
+			NewID:          idgen.UUIDv7,
+			RequireIfMatch: cfg.RequireIfMatch,
+		}))
+		r.Mount("/backlogs", backlog.Router(backlogs))
+	}
+}
+
+// logTransition notifies of tasks switched by transitions with the notify action in the request log.
+func logTransition(ctx context.Context, t task.Task, from task.Status) {
+	if lg := mw.LoggerFrom(ctx); lg != nil {
+		lg.InfoContext(ctx, "Task transitioned", "task-id", t.ID, "from", from, "to", t.Status)
+	}
+}
+
+// logOverload reports soft WIP limits exceeded by tasks in the request log.
+func logOverload(ctx context.Context, o board.Overload) {
+	if lg := mw.LoggerFrom(ctx); lg != nil {
+		lg.WarnContext(ctx, "WIP limit exceeded", "board-id", o.BoardID, "column-id", o.ColumnID,
+			"swimlane", o.Swimlane, "lane", o.Lane, "task-id", o.TaskID, "limit", o.Limit.Max, "count", o.Count)
+	}
+}
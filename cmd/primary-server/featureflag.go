@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/asmazovec/team-agile/internal/logging"
+)
+
+// newFeatureFlags creates registry of flags from the file with toggles of the config applied.
+func newFeatureFlags(cfg config.FeatureFlagsConfig, l *slog.Logger) (*featureflag.Registry, error) {
+	var flags []featureflag.Flag
+	if cfg.File != "" {
+		var err error
+		flags, err = featureflag.LoadFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return featureflag.NewRegistry(featureflag.Merge(flags, cfg.Toggles), logging.ForPackage(l, "featureflag"))
+}
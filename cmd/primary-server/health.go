@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/go-chi/chi/v5"
+)
+
+// healthRouter routes liveness and readiness probes.
+// Readiness fails once the drain begins and while any route group sheds load.
+func healthRouter(r chi.Router, cfg config.HealthConfig, drain *health.Drain, shedder *loadShedder) {
+	live := health.NewRegistry()
+
+	ready := health.NewRegistry()
+	ready.Register("shutdown", drain.Check)
+	ready.Register("load-shed", health.Saturated(shedder.Saturated))
+	ready.Register("disk", health.DiskSpace(cfg.DiskPath, cfg.DiskMinFree),
+		health.Timeout(cfg.CheckTimeout), health.CacheFor(cfg.CacheTTL))
+
+	r.Method(http.MethodGet, "/livez", health.Handler(live))
+	r.Method(http.MethodGet, "/readyz", health.Handler(ready))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// groupLimits creates body size and handler timeout middlewares of the route group.
+// Groups without configured overrides use limits of the server.
+func groupLimits(cfg config.ServerConfig, group string) func(http.Handler) http.Handler {
+	size, ok := cfg.GroupMaxBodySizes[group]
+	if !ok {
+		size = cfg.MaxBodySize
+	}
+	timeout, ok := cfg.GroupHandlerTimeouts[group]
+	if !ok {
+		timeout = cfg.HandlerTimeout
+	}
+	return chi.Chain(mw.BodyLimit(size), mw.Timeout(timeout)).Handler
+}
+
+// apiLimits applies limits of the import group to workflow imports and limits of the default group to other requests.
+// Limits of groups are alternatives, nested limits could only narrow outer ones.
+func apiLimits(cfg config.ServerConfig) func(http.Handler) http.Handler {
+	defaults, imports := groupLimits(cfg, "default"), groupLimits(cfg, "import")
+	return func(next http.Handler) http.Handler {
+		d, i := defaults(next), imports(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isImport(r) {
+				i.ServeHTTP(w, r)
+				return
+			}
+			d.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isImport reports whether the request imports a board workflow.
+func isImport(r *http.Request) bool {
+	return r.Method == http.MethodPut && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/workflow")
+}
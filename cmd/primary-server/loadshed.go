@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/asmazovec/team-agile/internal/metrics"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// loadShedder creates concurrency limiting middlewares of route groups.
+type loadShedder struct {
+	cfg       config.LoadShedConfig
+	reg       prometheus.Registerer
+	namespace string
+	limiters  []*loadshed.Limiter
+}
+
+func newLoadShedder(cfg config.LoadShedConfig, reg prometheus.Registerer, namespace string) *loadShedder {
+	return &loadShedder{cfg: cfg, reg: reg, namespace: namespace}
+}
+
+// Group returns concurrency limiting middleware of the route group.
+// Groups without configured limit are not shed.
+func (ls *loadShedder) Group(name string) func(http.Handler) http.Handler {
+	limit, ok := ls.cfg.Groups[name]
+	if !ok {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	l := loadshed.New(loadshed.Config{
+		MinLimit: ls.cfg.MinLimit,
+		MaxLimit: limit,
+		Target:   ls.cfg.LatencyTarget,
+		MaxWait:  ls.cfg.MaxWait,
+		MaxQueue: ls.cfg.MaxQueue,
+	})
+	ls.limiters = append(ls.limiters, l)
+	metrics.RegisterLoadShed(ls.reg, ls.namespace, name, l.Stats)
+	return mw.LoadShed(l, name, ls.cfg.RetryAfter)
+}
+
+// Saturated reports whether any route group sheds requests.
+func (ls *loadShedder) Saturated() bool {
+	for _, l := range ls.limiters {
+		if l.Saturated() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/asmazovec/team-agile/internal/metrics"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newLogger creates application logger controlled by runtime levels.
+func newLogger(cfg config.LogConfig) (*slog.Logger, *logging.Levels, error) {
+	packages, err := logging.ParseLevels(cfg.PackageLevels)
+	if err != nil {
+		return nil, nil, err
+	}
+	routes, err := logging.ParseLevels(cfg.RouteLevels)
+	if err != nil {
+		return nil, nil, err
+	}
+	levels := logging.NewLevels(cfg.Level)
+	levels.SetPackages(packages)
+	levels.SetRoutes(routes)
+
+	h, err := logging.NewHandler(os.Stderr, cfg.Format, levels)
+	if err != nil {
+		return nil, nil, err
+	}
+	h = logging.NewContextHandler(logging.NewLevelHandler(h, levels),
+		logging.RequestID, logging.TraceID, logging.SpanID, logging.UserID, logging.TenantID,
+	)
+	return slog.New(h), levels, nil
+}
+
+// requestLogger creates sampled request logger middleware with redacted headers and query.
+func requestLogger(
+	l *slog.Logger, cfg config.LogConfig, reg prometheus.Registerer, namespace string,
+) func(http.Handler) http.Handler {
+	sampler := mw.NewSampler(cfg.SampleRate, cfg.SampleRouteRates)
+	metrics.RegisterLogSampling(reg, namespace, sampler.Logged, sampler.Dropped)
+
+	extensions := []mw.LogExtension{
+		mw.RequestIDLog, mw.OriginalRequestIDLog, mw.TraceIDLog, mw.SpanIDLog, mw.MethodLog, mw.PathLog,
+	}
+	rd := mw.NewRedactor(cfg.RedactKeys, cfg.RedactEmails)
+	if cfg.Headers {
+		extensions = append(extensions, mw.HeadersLog(rd))
+	}
+	if cfg.Query {
+		extensions = append(extensions, mw.QueryLog(rd))
+	}
+	return mw.SampledLogger(l, sampler, extensions...)
+}
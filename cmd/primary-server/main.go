@@ -9,10 +9,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/asmazovec/team-agile/internal/closer"
 	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/asmazovec/team-agile/internal/idempotency"
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	"github.com/asmazovec/team-agile/internal/metrics"
 	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+	"github.com/asmazovec/team-agile/internal/tracing"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
@@ -24,19 +33,34 @@ func main() {
 	flag.Parse()
 
 	cfg := config.MustRead(config.FromEnv(cfgPath))
-	l := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	l, levels, err := newLogger(cfg.Log)
+	if err != nil {
+		panic(err)
+	}
+	state, err := maintenanceState(cfg.Maintenance)
+	if err != nil {
+		panic(err)
+	}
+	maint := maintenance.NewSwitch(state)
 	c := &closer.Closer{}
+	drain := &health.Drain{}
 
-	err := run(c, l, cfg)
+	err = run(c, l, levels, maint, drain, cfg)
 	if err != nil {
 		l.Error(err.Error())
 		panic(err)
 	}
+	watchLevelToggle(l, levels)
+	watchReload(reloadConfig(l, cfgPath, maint))
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
+	l.Info("Draining traffic")
+	drain.Begin()
+	time.Sleep(cfg.Health.DrainDelay)
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.AppShutdownTimeout)
 	defer shutdownCancel()
 
@@ -49,23 +73,110 @@ func main() {
 	}
 }
 
-//nolint:unparam // currently not needed
-func run(c *closer.Closer, l *slog.Logger, cfg config.AppConfig) error {
+func run(
+	c *closer.Closer, l *slog.Logger, levels *logging.Levels, maint *maintenance.Switch, drain *health.Drain,
+	cfg config.AppConfig,
+) error {
+	started := time.Now()
 	srv := &http.Server{
 		Addr:              cfg.HTTPPrimaryServer.Address,
 		ReadTimeout:       cfg.HTTPPrimaryServer.ReadTimeout,
 		ReadHeaderTimeout: cfg.HTTPPrimaryServer.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPPrimaryServer.WriteTimeout,
+	}
+
+	reqIDCfg := cfg.HTTPPrimaryServer.RequestID
+	reqIDGen, err := idgen.ByName(reqIDCfg.Generator, reqIDCfg.Node)
+	if err != nil {
+		return err
+	}
+	reqIDPolicy, err := mw.RequestIDPolicyByName(reqIDCfg.Policy, reqIDCfg.MaxLength)
+	if err != nil {
+		return err
+	}
+
+	exp, err := tracing.NewExporter(context.Background(), tracing.ExporterConfig{
+		Name:     cfg.Tracing.Exporter,
+		Endpoint: cfg.Tracing.Endpoint,
+		Insecure: cfg.Tracing.Insecure,
+	})
+	if err != nil {
+		return err
+	}
+	tp := tracing.NewProvider(exp, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	tpDep, _ := c.Add(closer.ReleaserWithLog(l, "Flushing traces", tp.Shutdown))
+
+	limiter, err := newRateLimiter(cfg.RateLimit, ratelimit.NewMemoryStore())
+	if err != nil {
+		return err
+	}
+
+	flags, err := newFeatureFlags(cfg.FeatureFlags, l)
+	if err != nil {
+		return err
 	}
 
 	router := chi.NewRouter()
 	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Use(mw.RequestID)
-	router.Use(mw.Logger(l, mw.RequestIDLog, mw.MethodLog))
+	router.Use(mw.RequestIDWith(
+		mw.RequestIDHeader(reqIDCfg.Header),
+		mw.RequestIDGenerate(reqIDGen),
+		mw.RequestIDAccept(reqIDPolicy),
+	))
+	router.Use(mw.Tracing(tp, tracing.NewPropagator()))
+	reg := metrics.NewRegistry()
+	var deprecations *metrics.Deprecations
+	if cfg.Metrics.Enabled {
+		router.Use(mw.Metrics(metrics.NewHTTP(reg, cfg.Metrics.Namespace)))
+		deprecations = metrics.NewDeprecations(reg, cfg.Metrics.Namespace)
+	}
+	if cfg.Identity.Headers {
+		router.Use(mw.IdentityHeaders(cfg.Identity.UserHeader, cfg.Identity.TeamHeader, cfg.Identity.TenantHeader))
+	} else {
+		l.Warn("Identity headers are disabled, feature flags and logs have no user, team and tenant ids")
+	}
+	router.Use(requestLogger(l, cfg.Log, reg, cfg.Metrics.Namespace))
+	router.Use(browserSecurity(cfg.Security, cfg.CORS)...)
+	if cfg.Compression.Enabled {
+		router.Use(mw.Compress(mw.CompressOptions{
+			Encodings:    cfg.Compression.Encodings,
+			MinSize:      cfg.Compression.MinSize,
+			ContentTypes: cfg.Compression.ContentTypes,
+		}))
+		router.Use(mw.Decompress(cfg.Compression.RequestMaxSize))
+	}
+	shedder := newLoadShedder(cfg.LoadShed, reg, cfg.Metrics.Namespace)
 
-	router.Get("/", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("Hello world!"))
+	router.Group(func(r chi.Router) {
+		r.Use(mw.Maintenance(maint, cfg.Maintenance.RetryAfter))
+		r.Use(limiter.Group("default"))
+		r.Use(shedder.Group("default"))
+		r.Use(csrf(cfg.CSRF))
+		r.Use(mw.FeatureFlags(flags))
+		r.Use(apiLimits(cfg.HTTPPrimaryServer))
+		if cfg.Idempotency.Enabled {
+			r.Use(mw.Idempotency(idempotency.NewMemoryStore(), cfg.Idempotency.TTL))
+		}
+		r.Get("/", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Hello world!"))
+		})
+		err = apiRouter(r, cfg.API, deprecations)
 	})
+	if err != nil {
+		return err
+	}
+	healthRouter(router, cfg.Health, drain, shedder)
+	if cfg.Metrics.Enabled {
+		router.Method(http.MethodGet, cfg.Metrics.Path, metrics.Handler(reg))
+	}
+	if cfg.Admin.Token != "" {
+		router.With(limiter.Group("admin")).Mount("/admin", adminRouter(
+			cfg.Admin, groupLimits(cfg.HTTPPrimaryServer, "admin"), l, levels, maint, flags, started,
+		))
+	} else {
+		l.Warn("Admin API is disabled, ADMIN_TOKEN is empty")
+	}
 
 	srv.Handler = router
 
@@ -74,7 +185,7 @@ func run(c *closer.Closer, l *slog.Logger, cfg config.AppConfig) error {
 		l.Info("Starting server on " + cfg.HTTPPrimaryServer.Address)
 		_ = srv.ListenAndServe()
 	}()
-	_, _ = c.Add(closer.ReleaserWithLog(l, "Closing HTTP Primary server", srv.Shutdown))
+	_, _ = c.Add(closer.ReleaserWithLog(l, "Closing HTTP Primary server", srv.Shutdown), tpDep)
 
 	return nil
 }
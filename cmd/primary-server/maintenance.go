@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	"github.com/asmazovec/team-agile/internal/maintenance"
+)
+
+func maintenanceState(cfg config.MaintenanceConfig) (maintenance.State, error) {
+	mode, err := maintenance.ParseMode(cfg.Mode)
+	if err != nil {
+		return maintenance.State{}, err
+	}
+	return maintenance.State{Mode: mode, Message: cfg.Message}, nil
+}
+
+// reloadConfig re-reads config from the env file and applies the maintenance state.
+// Invalid config is logged and ignored.
+func reloadConfig(l *slog.Logger, cfgPath string, maint *maintenance.Switch) func() {
+	return func() {
+		cfg, err := config.Read(config.ReloadFromEnv(cfgPath))
+		if err != nil {
+			l.Error("Config reload failed: " + err.Error())
+			return
+		}
+		state, err := maintenanceState(cfg.Maintenance)
+		if err != nil {
+			l.Error("Config reload failed: " + err.Error())
+			return
+		}
+		maint.Set(state)
+		l.Info("Config reloaded, maintenance mode is " + string(state.Mode))
+	}
+}
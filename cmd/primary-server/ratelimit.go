@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+)
+
+// rateLimiter creates rate limiting middlewares of route groups sharing the store.
+type rateLimiter struct {
+	store ratelimit.Store
+	alg   ratelimit.Algorithm
+	key   mw.RateLimitKey
+	rules map[string]ratelimit.Rule
+}
+
+func newRateLimiter(cfg config.RateLimitConfig, store ratelimit.Store) (*rateLimiter, error) {
+	alg, err := ratelimit.AlgorithmByName(cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key, err := mw.RateLimitKeyByName(cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	rules := make(map[string]ratelimit.Rule, len(cfg.Groups))
+	for group, s := range cfg.Groups {
+		rules[group], err = ratelimit.ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &rateLimiter{store: store, alg: alg, key: key, rules: rules}, nil
+}
+
+// Group returns rate limiting middleware of the route group.
+// Groups without configured rule are not limited.
+func (rl *rateLimiter) Group(name string) func(http.Handler) http.Handler {
+	rule, ok := rl.rules[name]
+	if !ok {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return mw.RateLimit(ratelimit.NewLimiter(rl.store, rl.alg, rule), name, rl.key)
+}
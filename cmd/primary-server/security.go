@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/config"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+)
+
+// browserSecurity returns middlewares protecting browser clients:
+// security headers and CORS if any origin is allowed.
+func browserSecurity(sec config.SecurityConfig, cors config.CORSConfig) []func(http.Handler) http.Handler {
+	mws := []func(http.Handler) http.Handler{
+		mw.SecurityHeaders(mw.SecurityHeadersOptions{
+			HSTSMaxAge:            sec.HSTSMaxAge,
+			HSTSIncludeSubdomains: sec.HSTSIncludeSubdomains,
+			HSTSPreload:           sec.HSTSPreload,
+			ContentSecurityPolicy: sec.ContentSecurityPolicy,
+			ReferrerPolicy:        sec.ReferrerPolicy,
+			FrameOptions:          sec.FrameOptions,
+		}),
+	}
+	if len(cors.AllowedOrigins) > 0 {
+		mws = append(mws, mw.CORS(mw.CORSOptions{
+			AllowedOrigins:   cors.AllowedOrigins,
+			AllowedMethods:   cors.AllowedMethods,
+			AllowedHeaders:   cors.AllowedHeaders,
+			ExposedHeaders:   cors.ExposedHeaders,
+			AllowCredentials: cors.AllowCredentials,
+			MaxAge:           cors.MaxAge,
+		}))
+	}
+	return mws
+}
+
+// csrf returns CSRF protection middleware, passthrough if disabled.
+func csrf(cfg config.CSRFConfig) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return mw.CSRF(mw.CSRFOptions{
+		CookieName:    cfg.CookieName,
+		HeaderName:    cfg.HeaderName,
+		SessionCookie: cfg.SessionCookie,
+		Secure:        cfg.Secure,
+	})
+}
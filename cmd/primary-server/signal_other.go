@@ -0,0 +1,15 @@
+//go:build !unix
+
+package main
+
+import (
+	"log/slog"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+)
+
+// watchLevelToggle does nothing, SIGUSR1 is not supported on the platform.
+func watchLevelToggle(*slog.Logger, *logging.Levels) {}
+
+// watchReload does nothing, SIGHUP is not supported on the platform.
+func watchReload(func()) {}
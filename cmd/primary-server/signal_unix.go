@@ -0,0 +1,35 @@
+//go:build unix
+
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+)
+
+// watchLevelToggle switches debug log level on each SIGUSR1.
+func watchLevelToggle(l *slog.Logger, levels *logging.Levels) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	go func() {
+		for range sigCh {
+			level := levels.ToggleDebug()
+			l.Info("Log level switched to " + level.String())
+		}
+	}()
+}
+
+// watchReload calls reload on each SIGHUP.
+func watchReload(reload func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			reload()
+		}
+	}()
+}
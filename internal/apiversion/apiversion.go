@@ -0,0 +1,124 @@
+// Package apiversion routes API requests to versioned route trees.
+//
+// A version is selected by the path prefix, e.g. /api/v2/tasks,
+// or for unprefixed paths, e.g. /api/tasks, by the vendor media type of the Accept header,
+// e.g. application/vnd.team-agile.v2+json. Unprefixed requests without a vendor media type
+// are served by the default version.
+package apiversion
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// Version is an API version with own middleware stack and routes.
+type Version struct {
+	// Name of the version used in the path prefix and media type, e.g. "v1".
+	Name string
+	// Middlewares applied to all routes of the version.
+	Middlewares []func(http.Handler) http.Handler
+	// Routes registers routes of the version.
+	Routes func(r chi.Router)
+	// Deprecation marks all routes of the version deprecated, optional.
+	Deprecation *mw.DeprecationInfo
+}
+
+// Options of versioned API routing.
+type Options struct {
+	// Vendor of media types, application/vnd.<Vendor>.<version>+json.
+	Vendor string
+	// Default version serving requests without a version.
+	Default string
+	// Versions of the API.
+	Versions []Version
+	// Metrics of deprecated routes usage, optional.
+	Metrics *metrics.Deprecations
+}
+
+type versionKey struct{}
+
+// From extracts the API version serving the request from context.
+// Returns empty string outside versioned routes.
+func From(ctx context.Context) string {
+	v, _ := ctx.Value(versionKey{}).(string)
+	return v
+}
+
+// Mount mounts versions under the prefix of the router.
+// Panics if the default version is not presented in versions.
+func Mount(r chi.Router, prefix string, opts Options) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	handlers := make(map[string]http.Handler, len(opts.Versions))
+	names := make([]string, 0, len(opts.Versions))
+	for _, v := range opts.Versions {
+		h := versionRouter(v, opts.Metrics)
+		handlers[v.Name] = h
+		names = append(names, v.Name)
+		r.Mount(prefix+"/"+v.Name, h)
+	}
+	fallback, ok := handlers[opts.Default]
+	if !ok {
+		panic("apiversion: default version " + opts.Default + " is not mounted")
+	}
+
+	media := "application/vnd." + strings.ToLower(opts.Vendor) + "."
+	r.Mount(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		name, ok := Negotiate(r.Header.Get("Accept"), media)
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		h, ok := handlers[name]
+		if !ok {
+			problem.Write(w, r, problem.New(http.StatusNotAcceptable, "unsupported API version "+name).
+				With("versions", names))
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+}
+
+// Negotiate extracts the version of the first vendor media type with the prefix
+// in the Accept header, e.g. "v2" of "application/vnd.team-agile.v2+json" for the
+// "application/vnd.team-agile." prefix.
+func Negotiate(accept, prefix string) (string, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		name, ok := strings.CutPrefix(mediaType, prefix)
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "+")
+		if name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func versionRouter(v Version, m *metrics.Deprecations) chi.Router {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), versionKey{}, v.Name)))
+		})
+	})
+	if v.Deprecation != nil {
+		info := *v.Deprecation
+		info.Version = v.Name
+		r.Use(mw.Deprecated(info, m))
+	}
+	r.Use(v.Middlewares...)
+	if v.Routes != nil {
+		v.Routes(r)
+	}
+	return r
+}
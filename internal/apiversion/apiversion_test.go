@@ -0,0 +1,117 @@
+package apiversion_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/apiversion"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVersioned() http.Handler {
+	routes := func(r chi.Router) {
+		r.Get("/tasks", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(apiversion.From(r.Context())))
+		})
+	}
+	r := chi.NewRouter()
+	apiversion.Mount(r, "/api", apiversion.Options{
+		Vendor:  "team-agile",
+		Default: "v2",
+		Versions: []apiversion.Version{
+			{
+				Name:        "v1",
+				Routes:      routes,
+				Deprecation: &mw.DeprecationInfo{Since: time.Unix(1704067200, 0)},
+			},
+			{
+				Name: "v2",
+				Middlewares: []func(http.Handler) http.Handler{func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Set("X-Stack", "v2")
+						next.ServeHTTP(w, r)
+					})
+				}},
+				Routes: routes,
+			},
+		},
+	})
+	return r
+}
+
+func serveVersioned(path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	newVersioned().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMount_PathVersion_ShouldRouteToVersion(t *testing.T) {
+	v1 := serveVersioned("/api/v1/tasks", "")
+	v2 := serveVersioned("/api/v2/tasks", "")
+
+	assert.Equal(t, "v1", v1.Body.String())
+	assert.Equal(t, "@1704067200", v1.Header().Get("Deprecation"))
+	assert.Empty(t, v1.Header().Get("X-Stack"))
+	assert.Equal(t, "v2", v2.Body.String())
+	assert.Empty(t, v2.Header().Get("Deprecation"))
+	assert.Equal(t, "v2", v2.Header().Get("X-Stack"))
+}
+
+func TestMount_AcceptVersion_ShouldRouteToVersion(t *testing.T) {
+	rec := serveVersioned("/api/tasks", "text/html;q=0.5, application/vnd.team-agile.v1+json")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v1", rec.Body.String())
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+}
+
+func TestMount_NoVersion_ShouldRouteToDefault(t *testing.T) {
+	rec := serveVersioned("/api/tasks", "application/json")
+
+	assert.Equal(t, "v2", rec.Body.String())
+}
+
+func TestMount_UnknownAcceptVersion_ShouldNotAcceptable(t *testing.T) {
+	rec := serveVersioned("/api/tasks", "application/vnd.team-agile.v9+json")
+
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"versions":["v1","v2"]`)
+}
+
+func TestMount_UnknownDefault_ShouldPanic(t *testing.T) {
+	assert.Panics(t, func() {
+		apiversion.Mount(chi.NewRouter(), "/api", apiversion.Options{Default: "v1"})
+	})
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept  string
+		version string
+		ok      bool
+	}{
+		{"application/vnd.team-agile.v2+json", "v2", true},
+		{"application/vnd.team-agile.v3", "v3", true},
+		{"Application/Vnd.Team-Agile.V1+JSON; charset=utf-8", "v1", true},
+		{"application/json, application/vnd.team-agile.v1+json", "v1", true},
+		{"application/json", "", false},
+		{"application/vnd.other.v1+json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		version, ok := apiversion.Negotiate(tt.accept, "application/vnd.team-agile.")
+
+		assert.Equal(t, tt.version, version, tt.accept)
+		assert.Equal(t, tt.ok, ok, tt.accept)
+	}
+}
@@ -0,0 +1,247 @@
+// Package backlog provides ranked team backlogs of tasks not in active sprints and their REST API.
+package backlog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/rank"
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/asmazovec/team-agile/internal/task"
+)
+
+// MaxRerankTasks is the greatest number of tasks reranked at once.
+const MaxRerankTasks = 1000
+
+var (
+	// ErrTaskNotInBacklog means task is not in the backlog of the team.
+	ErrTaskNotInBacklog = errors.New("task is not in the backlog")
+	// ErrAnchorNotFound means task to place reranked tasks next to is not in the backlog.
+	ErrAnchorNotFound = errors.New("anchor task is not in the backlog")
+)
+
+// Position is an end of the backlog.
+type Position string
+
+// Backlog ends.
+const (
+	PositionTop    Position = "top"
+	PositionBottom Position = "bottom"
+)
+
+// Query selects a page of the team backlog, zero epic and label match any task.
+type Query struct {
+	TeamID string
+	EpicID string
+	Label  string
+	// Offset skips the first tasks of the backlog.
+	Offset int
+	// Limit is the maximum number of tasks in the page, zero means no limit.
+	Limit int
+}
+
+// Rerank moves tasks of the backlog keeping their order right before or right after the anchor task,
+// or to the top or to the bottom of the backlog.
+type Rerank struct {
+	Tasks    []string `json:"tasks"`
+	Before   string   `json:"before,omitempty"`
+	After    string   `json:"after,omitempty"`
+	Position Position `json:"position,omitempty"`
+}
+
+// Validate implements decode.Validator.
+func (m Rerank) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, decode.FieldError{Field: field, Message: message})
+	}
+
+	switch n := len(m.Tasks); {
+	case n == 0:
+		invalid("tasks", "must not be empty")
+	case n > MaxRerankTasks:
+		invalid("tasks", "must contain at most "+strconv.Itoa(MaxRerankTasks)+" tasks")
+	}
+	for i, id := range m.Tasks {
+		field := "tasks." + strconv.Itoa(i)
+		switch {
+		case id == "":
+			invalid(field, "must not be empty")
+		case slices.Contains(m.Tasks[:i], id):
+			invalid(field, "must be unique")
+		}
+	}
+
+	targets := 0
+	for _, set := range []bool{m.Before != "", m.After != "", m.Position != ""} {
+		if set {
+			targets++
+		}
+	}
+	switch {
+	case targets != 1:
+		invalid("position", "exactly one of before, after and position must be set")
+	case m.Position != "" && m.Position != PositionTop && m.Position != PositionBottom:
+		invalid("position", "must be one of top, bottom")
+	case m.Before != "" && slices.Contains(m.Tasks, m.Before):
+		invalid("before", "must not be a reranked task")
+	case m.After != "" && slices.Contains(m.Tasks, m.After):
+		invalid("after", "must not be a reranked task")
+	}
+	return fields
+}
+
+// Service lists and ranks team backlogs.
+// Backlog of a team is its unresolved tasks not in active sprints ordered by backlog rank of the tasks,
+// tasks without a backlog rank follow the ranked ones in the creation order.
+// Backlog rank is independent of the board rank, so moves on boards keep the backlog order.
+type Service struct {
+	tasks   task.Repository
+	sprints sprint.Repository
+	now     func() time.Time
+}
+
+// NewService creates service of the backlogs of tasks planned in the sprints.
+func NewService(tasks task.Repository, sprints sprint.Repository) *Service {
+	return &Service{tasks: tasks, sprints: sprints, now: time.Now}
+}
+
+// List returns the page of the team backlog selected by the query.
+func (s *Service) List(ctx context.Context, q Query) (task.Page, error) {
+	items, err := s.backlog(ctx, task.Filter{TeamID: q.TeamID, EpicID: q.EpicID, Label: q.Label})
+	if err != nil {
+		return task.Page{}, err
+	}
+	page := task.Page{Items: []task.Task{}, Total: len(items)}
+	if q.Offset < len(items) {
+		items = items[q.Offset:]
+		if q.Limit > 0 && q.Limit < len(items) {
+			items = items[:q.Limit]
+		}
+		page.Items = items
+	}
+	return page, nil
+}
+
+// Rerank moves the tasks of the team backlog and returns them with new ranks in the requested order.
+// Only the moved tasks are ranked, except tasks without a rank above the new place which are ranked
+// in their current order first. All tasks are updated atomically.
+func (s *Service) Rerank(ctx context.Context, teamID string, m Rerank) ([]task.Task, error) {
+	items, err := s.backlog(ctx, task.Filter{TeamID: teamID})
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(items))
+	for i, t := range items {
+		index[t.ID] = i
+	}
+	moved := make([]task.Task, 0, len(m.Tasks))
+	isMoved := make(map[string]bool, len(m.Tasks))
+	for _, id := range m.Tasks {
+		i, ok := index[id]
+		if !ok {
+			return nil, ErrTaskNotInBacklog
+		}
+		moved = append(moved, items[i])
+		isMoved[id] = true
+	}
+	rest := slices.DeleteFunc(items, func(t task.Task) bool { return isMoved[t.ID] })
+
+	pos := 0
+	switch {
+	case m.Position == PositionBottom:
+		pos = len(rest)
+	case m.Before != "" || m.After != "":
+		anchor := cmp.Or(m.Before, m.After)
+		pos = slices.IndexFunc(rest, func(t task.Task) bool { return t.ID == anchor })
+		if pos < 0 {
+			return nil, ErrAnchorNotFound
+		}
+		if m.After != "" {
+			pos++
+		}
+	}
+
+	var changed []task.Task
+	// Tasks without a rank above the new place are ranked first to keep them above the moved tasks.
+	if first := slices.IndexFunc(rest, func(t task.Task) bool { return t.BacklogRank == "" }); first >= 0 && first < pos {
+		prev := ""
+		if first > 0 {
+			prev = rest[first-1].BacklogRank
+		}
+		ranks, err := rank.Spread(prev, "", pos-first)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range ranks {
+			rest[first+i].BacklogRank = r
+			changed = append(changed, rest[first+i])
+		}
+	}
+
+	var prev, next string
+	if pos > 0 {
+		prev = rest[pos-1].BacklogRank
+	}
+	for _, t := range rest[pos:] {
+		if t.BacklogRank > prev {
+			next = t.BacklogRank
+			break
+		}
+	}
+	ranks, err := rank.Spread(prev, next, len(moved))
+	if err != nil {
+		return nil, err
+	}
+	for i := range moved {
+		moved[i].BacklogRank = ranks[i]
+	}
+
+	now := s.now()
+	changed = append(changed, moved...)
+	for i := range changed {
+		changed[i].UpdatedAt = now
+	}
+	updated, err := s.tasks.UpdateMany(ctx, changed)
+	if err != nil {
+		return nil, err
+	}
+	return updated[len(updated)-len(moved):], nil
+}
+
+// backlog returns unresolved tasks of the filter not in active sprints in the backlog order.
+func (s *Service) backlog(ctx context.Context, f task.Filter) ([]task.Task, error) {
+	active, err := s.sprints.List(ctx, sprint.Filter{State: sprint.StateActive})
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.tasks.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	inActive := make(map[string]bool, len(active))
+	for _, sp := range active {
+		inActive[sp.ID] = true
+	}
+	items := slices.DeleteFunc(page.Items, func(t task.Task) bool {
+		return t.ResolvedAt != nil || inActive[t.SprintID]
+	})
+	slices.SortStableFunc(items, func(a, b task.Task) int {
+		// Tasks without a backlog rank go last keeping the creation order.
+		switch {
+		case a.BacklogRank == "" && b.BacklogRank == "":
+			return 0
+		case a.BacklogRank == "":
+			return 1
+		case b.BacklogRank == "":
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.BacklogRank, b.BacklogRank), cmp.Compare(a.ID, b.ID))
+	})
+	return items, nil
+}
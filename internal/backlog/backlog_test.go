@@ -0,0 +1,211 @@
+package backlog_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/backlog"
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	s       *backlog.Service
+	tasks   *task.MemoryRepository
+	sprints *sprint.MemoryRepository
+}
+
+// newFixture creates unranked tasks of team core with the ids in the creation order.
+func newFixture(t *testing.T, ids ...string) *fixture {
+	t.Helper()
+	tasks := task.NewMemoryRepository()
+	for _, id := range ids {
+		_, err := tasks.Create(context.Background(), task.Task{ID: id, Title: id, Status: task.StatusTodo, TeamID: "core"})
+		require.NoError(t, err)
+	}
+	sprints := sprint.NewMemoryRepository()
+	s := backlog.NewService(tasks, sprints)
+	s.SetClock(func() time.Time { return testNow })
+	return &fixture{s: s, tasks: tasks, sprints: sprints}
+}
+
+func (f *fixture) ids(t *testing.T, q backlog.Query) []string {
+	t.Helper()
+	q.TeamID = "core"
+	page, err := f.s.List(context.Background(), q)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, tk := range page.Items {
+		ids = append(ids, tk.ID)
+	}
+	return ids
+}
+
+func (f *fixture) rerank(t *testing.T, m backlog.Rerank) []task.Task {
+	t.Helper()
+	tasks, err := f.s.Rerank(context.Background(), "core", m)
+	require.NoError(t, err)
+	return tasks
+}
+
+func TestService_List_ShouldExcludeActiveSprintsAndResolved(t *testing.T) {
+	f := newFixture(t, "a", "b", "c", "d")
+	ctx := context.Background()
+	_, _ = f.sprints.Create(ctx, sprint.Sprint{ID: "s1", State: sprint.StateActive})
+	_, _ = f.sprints.Create(ctx, sprint.Sprint{ID: "s2", State: sprint.StatePlanned})
+	resolved := testNow
+	for id, change := range map[string]func(*task.Task){
+		"a": func(tk *task.Task) { tk.SprintID = "s1" },
+		"b": func(tk *task.Task) { tk.SprintID = "s2" },
+		"c": func(tk *task.Task) { tk.ResolvedAt = &resolved },
+	} {
+		tk, _ := f.tasks.Get(ctx, id)
+		change(&tk)
+		_, err := f.tasks.Update(ctx, tk)
+		require.NoError(t, err)
+	}
+	_, _ = f.tasks.Create(ctx, task.Task{ID: "e", TeamID: "platform"})
+
+	assert.Equal(t, []string{"b", "d"}, f.ids(t, backlog.Query{}))
+}
+
+func TestService_List_ShouldFilterAndPaginate(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	ctx := context.Background()
+	for _, id := range []string{"a", "c"} {
+		tk, _ := f.tasks.Get(ctx, id)
+		tk.EpicID = "e1"
+		tk.Labels = []string{"api"}
+		_, _ = f.tasks.Update(ctx, tk)
+	}
+
+	page, err := f.s.List(ctx, backlog.Query{TeamID: "core", EpicID: "e1", Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	beyond, _ := f.s.List(ctx, backlog.Query{TeamID: "core", Offset: 5})
+
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "c", page.Items[0].ID)
+	assert.Equal(t, []string{"a", "c"}, f.ids(t, backlog.Query{Label: "api"}))
+	assert.Empty(t, beyond.Items)
+	assert.Equal(t, 3, beyond.Total)
+}
+
+func TestService_Rerank_ShouldMoveKeepingRequestedOrder(t *testing.T) {
+	f := newFixture(t, "a", "b", "c", "d", "e")
+
+	f.rerank(t, backlog.Rerank{Tasks: []string{"e", "d"}, Position: backlog.PositionTop})
+	assert.Equal(t, []string{"e", "d", "a", "b", "c"}, f.ids(t, backlog.Query{}))
+
+	f.rerank(t, backlog.Rerank{Tasks: []string{"e"}, Position: backlog.PositionBottom})
+	assert.Equal(t, []string{"d", "a", "b", "c", "e"}, f.ids(t, backlog.Query{}))
+
+	f.rerank(t, backlog.Rerank{Tasks: []string{"c", "a"}, Before: "d"})
+	assert.Equal(t, []string{"c", "a", "d", "b", "e"}, f.ids(t, backlog.Query{}))
+
+	moved := f.rerank(t, backlog.Rerank{Tasks: []string{"d"}, After: "e"})
+	assert.Equal(t, []string{"c", "a", "b", "e", "d"}, f.ids(t, backlog.Query{}))
+	require.Len(t, moved, 1)
+	assert.Equal(t, testNow, moved[0].UpdatedAt)
+}
+
+func TestService_Rerank_Top_ShouldChangeOnlyMovedTasks(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	f.rerank(t, backlog.Rerank{Tasks: []string{"a", "b", "c"}, Position: backlog.PositionTop})
+	before, _ := f.tasks.Get(context.Background(), "a")
+
+	f.rerank(t, backlog.Rerank{Tasks: []string{"c"}, Position: backlog.PositionTop})
+	after, _ := f.tasks.Get(context.Background(), "a")
+
+	assert.Equal(t, before.Version, after.Version)
+	assert.Equal(t, []string{"c", "a", "b"}, f.ids(t, backlog.Query{}))
+}
+
+func TestService_Rerank_BoardRank_ShouldNotChangeBacklogOrder(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	ctx := context.Background()
+	f.rerank(t, backlog.Rerank{Tasks: []string{"c", "b", "a"}, Position: backlog.PositionTop})
+	// Board rank is changed by board moves and cleared by removal from the board.
+	for id, r := range map[string]string{"a": "0", "b": "1", "c": ""} {
+		tk, _ := f.tasks.Get(ctx, id)
+		tk.Rank = r
+		_, err := f.tasks.Update(ctx, tk)
+		require.NoError(t, err)
+	}
+
+	f.rerank(t, backlog.Rerank{Tasks: []string{"a"}, After: "c"})
+	board, _ := f.tasks.Get(ctx, "a")
+
+	assert.Equal(t, []string{"c", "a", "b"}, f.ids(t, backlog.Query{}))
+	assert.Equal(t, "0", board.Rank)
+}
+
+func TestService_Rerank_Bulk_ShouldKeepRanksShort(t *testing.T) {
+	ids := make([]string, 0, 2000)
+	for i := range 2000 {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	f := newFixture(t, ids...)
+
+	moved := f.rerank(t, backlog.Rerank{Tasks: ids[1000:], Position: backlog.PositionTop})
+
+	require.Len(t, moved, 1000)
+	for i := 1; i < len(moved); i++ {
+		require.Less(t, moved[i-1].BacklogRank, moved[i].BacklogRank)
+	}
+	assert.LessOrEqual(t, len(moved[len(moved)-1].BacklogRank), 6)
+	got := f.ids(t, backlog.Query{Limit: 2})
+	assert.Equal(t, []string{"1000", "1001"}, got)
+}
+
+func TestService_Rerank_Unknown_ShouldError(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	_, _ = f.tasks.Create(context.Background(), task.Task{ID: "x", TeamID: "platform"})
+
+	_, other := f.s.Rerank(context.Background(), "core", backlog.Rerank{Tasks: []string{"x"}, Position: backlog.PositionTop})
+	_, anchor := f.s.Rerank(context.Background(), "core", backlog.Rerank{Tasks: []string{"a"}, After: "x"})
+
+	assert.ErrorIs(t, other, backlog.ErrTaskNotInBacklog)
+	assert.ErrorIs(t, anchor, backlog.ErrAnchorNotFound)
+}
+
+func TestRerank_Validate_Invalid_ShouldReportFields(t *testing.T) {
+	tests := map[string]struct {
+		m      backlog.Rerank
+		fields []decode.FieldError
+	}{
+		"empty": {
+			m: backlog.Rerank{Position: backlog.PositionTop},
+			fields: []decode.FieldError{
+				{Field: "tasks", Message: "must not be empty"},
+			},
+		},
+		"duplicate and no target": {
+			m: backlog.Rerank{Tasks: []string{"a", "a"}},
+			fields: []decode.FieldError{
+				{Field: "tasks.1", Message: "must be unique"},
+				{Field: "position", Message: "exactly one of before, after and position must be set"},
+			},
+		},
+		"unknown position": {
+			m:      backlog.Rerank{Tasks: []string{"a"}, Position: "middle"},
+			fields: []decode.FieldError{{Field: "position", Message: "must be one of top, bottom"}},
+		},
+		"self anchor": {
+			m:      backlog.Rerank{Tasks: []string{"a"}, Before: "a"},
+			fields: []decode.FieldError{{Field: "before", Message: "must not be a reranked task"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.fields, tt.m.Validate())
+		})
+	}
+}
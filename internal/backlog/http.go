@@ -0,0 +1,106 @@
+package backlog
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Router routes REST API of the team backlogs:
+// GET /{team} lists the backlog filtered by epic_id and label query parameters
+// and paginated by limit and offset, POST /{team}/rank reranks tasks of the backlog atomically.
+func Router(s *Service) http.Handler {
+	h := &handler{s: s}
+	r := chi.NewRouter()
+	r.Get("/{team}", h.list)
+	r.Post("/{team}/rank", h.rerank)
+	return r
+}
+
+type handler struct {
+	s *Service
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := Query{
+		TeamID: chi.URLParam(r, "team"),
+		EpicID: q.Get("epic_id"),
+		Label:  q.Get("label"),
+		Limit:  task.DefaultPageSize,
+	}
+	var fields []decode.FieldError
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > task.MaxPageSize {
+			fields = append(fields, decode.FieldError{
+				Field:   "limit",
+				Message: "must be between 1 and " + strconv.Itoa(task.MaxPageSize),
+			})
+		}
+		query.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			fields = append(fields, decode.FieldError{Field: "offset", Message: "must be a non-negative integer"})
+		}
+		query.Offset = offset
+	}
+	if len(fields) > 0 {
+		problem.Write(w, r, decode.Problem(&decode.Error{
+			Status: http.StatusBadRequest,
+			Detail: "query parameters are invalid",
+			Fields: fields,
+		}))
+		return
+	}
+
+	page, err := h.s.List(r.Context(), query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	render.JSON(w, r, page)
+}
+
+func (h *handler) rerank(w http.ResponseWriter, r *http.Request) {
+	var m Rerank
+	if err := decode.JSON(r, &m); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	team := chi.URLParam(r, "team")
+	tasks, err := h.s.Rerank(r.Context(), team, m)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Backlog reranked", "team-id", team, "tasks", len(tasks))
+	}
+	render.JSON(w, r, tasks)
+}
+
+// writeError writes service error as a problem.
+// Concurrent modification of reranked tasks is reported as a conflict.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrTaskNotInBacklog), errors.Is(err, ErrAnchorNotFound):
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, err.Error()))
+	case errors.Is(err, task.ErrNotFound), errors.Is(err, task.ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusConflict, "tasks were modified concurrently, retry the request"))
+	default:
+		if lg := mw.LoggerFrom(r.Context()); lg != nil {
+			lg.ErrorContext(r.Context(), "Backlog storage failed: "+err.Error())
+		}
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "backlog storage failed"))
+	}
+}
@@ -0,0 +1,76 @@
+package backlog_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/backlog"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBacklogAPI(t *testing.T, ids ...string) http.Handler {
+	f := newFixture(t, ids...)
+	r := chi.NewRouter()
+	r.Mount("/backlogs", backlog.Router(f.s))
+	return r
+}
+
+func serveBacklogs(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouter_Rerank_ShouldReorderBacklog(t *testing.T) {
+	h := newBacklogAPI(t, "a", "b", "c")
+
+	rec := serveBacklogs(h, http.MethodPost, "/backlogs/core/rank", `{"tasks":["c","b"],"before":"a"}`)
+	list := serveBacklogs(h, http.MethodGet, "/backlogs/core?limit=2&offset=1", "")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var moved []task.Task
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &moved))
+	require.Len(t, moved, 2)
+	assert.Equal(t, "c", moved[0].ID)
+	require.Equal(t, http.StatusOK, list.Code)
+	var page task.Page
+	require.NoError(t, json.Unmarshal(list.Body.Bytes(), &page))
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "b", page.Items[0].ID)
+	assert.Equal(t, "a", page.Items[1].ID)
+}
+
+func TestRouter_Rerank_Invalid_ShouldUnprocessable(t *testing.T) {
+	h := newBacklogAPI(t, "a")
+
+	invalid := serveBacklogs(h, http.MethodPost, "/backlogs/core/rank", `{"tasks":["a"],"position":"middle"}`)
+	unknown := serveBacklogs(h, http.MethodPost, "/backlogs/core/rank", `{"tasks":["x"],"position":"top"}`)
+	otherTeam := serveBacklogs(h, http.MethodPost, "/backlogs/platform/rank", `{"tasks":["a"],"position":"top"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, invalid.Code)
+	assert.Contains(t, invalid.Body.String(), `{"field":"position","message":"must be one of top, bottom"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, unknown.Code)
+	assert.Contains(t, unknown.Body.String(), `"detail":"task is not in the backlog"`)
+	assert.Equal(t, http.StatusUnprocessableEntity, otherTeam.Code)
+}
+
+func TestRouter_List_InvalidPagination_ShouldBadRequest(t *testing.T) {
+	h := newBacklogAPI(t)
+
+	rec := serveBacklogs(h, http.MethodGet, "/backlogs/core?limit=0&offset=-1", "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"limit"`)
+	assert.Contains(t, rec.Body.String(), `"field":"offset"`)
+}
@@ -0,0 +1,157 @@
+// Package board provides boards of ordered columns showing tasks by status, their storage and REST API.
+package board
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+)
+
+// Limits of board fields.
+const (
+	MaxNameLength       = 100
+	MaxColumns          = 20
+	MaxColumnNameLength = 50
+)
+
+var (
+	// ErrNotFound means board does not exist.
+	ErrNotFound = errors.New("board not found")
+	// ErrExists means board with the same id already exists.
+	ErrExists = errors.New("board already exists")
+	// ErrVersionConflict means board was modified since the expected version.
+	ErrVersionConflict = errors.New("board version conflict")
+	// ErrNotEmpty means board has tasks.
+	ErrNotEmpty = errors.New("board has tasks")
+	// ErrColumnNotFound means column does not exist on the board.
+	ErrColumnNotFound = errors.New("column not found")
+	// ErrColumnNotEmpty means column has tasks.
+	ErrColumnNotEmpty = errors.New("column has tasks")
+	// ErrStatusInUse means board tasks are in a status removed from the workflow.
+	ErrStatusInUse = errors.New("board tasks are in statuses removed from the workflow")
+)
+
+// Column of a board shows tasks of the status.
+// Column without a WIP limit takes any number of tasks.
+type Column struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Status   task.Status `json:"status"`
+	WIPLimit *WIPLimit   `json:"wip_limit,omitempty"`
+}
+
+// Board is an ordered set of columns of the workflow states.
+// Version is incremented on each change of the board and identifies its state for optimistic locking.
+type Board struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Columns     []Column          `json:"columns"`
+	Workflow    workflow.Workflow `json:"workflow"`
+	Swimlanes   *Swimlanes        `json:"swimlanes,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Version     uint64            `json:"version"`
+}
+
+// DefaultColumns returns a column of each state of the workflow with ids of the generator.
+// Columns of known task statuses are named for humans, other columns are named by their state.
+func DefaultColumns(w workflow.Workflow, newID func() string) []Column {
+	names := map[task.Status]string{
+		task.StatusTodo:       "To do",
+		task.StatusInProgress: "In progress",
+		task.StatusReview:     "Review",
+		task.StatusDone:       "Done",
+	}
+	columns := make([]Column, 0, len(w.States))
+	for _, s := range w.States {
+		name, ok := names[s]
+		if !ok {
+			name = string(s)
+		}
+		columns = append(columns, Column{ID: newID(), Name: name, Status: s})
+	}
+	return columns
+}
+
+// ColumnOf returns the column of the status.
+func (b Board) ColumnOf(s task.Status) (Column, bool) {
+	i := slices.IndexFunc(b.Columns, func(c Column) bool { return c.Status == s })
+	if i < 0 {
+		return Column{}, false
+	}
+	return b.Columns[i], true
+}
+
+// Column returns the column and its position on the board.
+func (b Board) Column(id string) (Column, int, bool) {
+	i := slices.IndexFunc(b.Columns, func(c Column) bool { return c.ID == id })
+	if i < 0 {
+		return Column{}, -1, false
+	}
+	return b.Columns[i], i, true
+}
+
+// MoveColumn moves the column to the position, positions out of range are clamped.
+func (b *Board) MoveColumn(id string, position int) error {
+	c, i, ok := b.Column(id)
+	if !ok {
+		return ErrColumnNotFound
+	}
+	b.Columns = slices.Delete(b.Columns, i, i+1)
+	position = min(max(position, 0), len(b.Columns))
+	b.Columns = slices.Insert(b.Columns, position, c)
+	return nil
+}
+
+// Validate reports invalid user editable fields of the board.
+func (b Board) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, decode.FieldError{Field: field, Message: message})
+	}
+
+	switch n := utf8.RuneCountInString(b.Name); {
+	case n == 0:
+		invalid("name", "must not be empty")
+	case n > MaxNameLength:
+		invalid("name", "must be at most "+strconv.Itoa(MaxNameLength)+" characters")
+	}
+	switch n := len(b.Columns); {
+	case n == 0:
+		invalid("columns", "must not be empty")
+	case n > MaxColumns:
+		invalid("columns", "must contain at most "+strconv.Itoa(MaxColumns)+" columns")
+	}
+	for i, c := range b.Columns {
+		field := "columns." + strconv.Itoa(i)
+		switch n := utf8.RuneCountInString(c.Name); {
+		case n == 0:
+			invalid(field+".name", "must not be empty")
+		case n > MaxColumnNameLength:
+			invalid(field+".name", "must be at most "+strconv.Itoa(MaxColumnNameLength)+" characters")
+		}
+		switch {
+		case !b.Workflow.Has(c.Status):
+			invalid(field+".status", "must be a state of the workflow")
+		case slices.ContainsFunc(b.Columns[:i], func(prev Column) bool { return prev.Status == c.Status }):
+			invalid(field+".status", "must be unique")
+		}
+		if c.WIPLimit != nil {
+			fields = append(fields, c.WIPLimit.validate(field+".wip_limit")...)
+		}
+	}
+	if b.Swimlanes != nil {
+		fields = append(fields, b.Swimlanes.validate()...)
+	}
+	for _, f := range b.Workflow.Validate() {
+		invalid("workflow."+f.Field, f.Message)
+	}
+	return fields
+}
@@ -0,0 +1,76 @@
+package board_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sequence() func() string {
+	n := 0
+	return func() string {
+		n++
+		return strconv.Itoa(n)
+	}
+}
+
+func TestDefaultColumns_CustomWorkflow_ShouldNameByState(t *testing.T) {
+	columns := board.DefaultColumns(workflow.Workflow{States: []task.Status{"todo", "blocked"}}, sequence())
+
+	assert.Equal(t, []board.Column{
+		{ID: "1", Name: "To do", Status: "todo"},
+		{ID: "2", Name: "blocked", Status: "blocked"},
+	}, columns)
+}
+
+func TestDefaultColumns_ShouldMapEachStatus(t *testing.T) {
+	columns := board.DefaultColumns(workflow.Default(), sequence())
+
+	require.Len(t, columns, len(task.Statuses()))
+	assert.Equal(t, board.Column{ID: "1", Name: "To do", Status: task.StatusTodo}, columns[0])
+	assert.Equal(t, board.Column{ID: "4", Name: "Done", Status: task.StatusDone}, columns[3])
+}
+
+func TestBoard_Validate_Invalid_ShouldReportFields(t *testing.T) {
+	b := board.Board{Workflow: workflow.Default(), Columns: []board.Column{
+		{ID: "1", Name: "To do", Status: task.StatusTodo},
+		{ID: "2", Name: "", Status: task.StatusTodo},
+		{ID: "3", Name: "Blocked", Status: "blocked"},
+	}}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "name", Message: "must not be empty"},
+		{Field: "columns.1.name", Message: "must not be empty"},
+		{Field: "columns.1.status", Message: "must be unique"},
+		{Field: "columns.2.status", Message: "must be a state of the workflow"},
+	}, b.Validate())
+}
+
+func TestBoard_Validate_NoColumns_ShouldReport(t *testing.T) {
+	b := board.Board{Name: "Team", Workflow: workflow.Workflow{}}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "columns", Message: "must not be empty"},
+		{Field: "workflow.states", Message: "must not be empty"},
+	}, b.Validate())
+}
+
+func TestBoard_MoveColumn_ShouldReorderAndClamp(t *testing.T) {
+	b := board.Board{Name: "Team", Columns: board.DefaultColumns(workflow.Default(), sequence())}
+
+	require.NoError(t, b.MoveColumn("4", 0))
+	require.NoError(t, b.MoveColumn("1", 100))
+
+	ids := make([]string, 0, len(b.Columns))
+	for _, c := range b.Columns {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"4", "2", "3", "1"}, ids)
+	assert.ErrorIs(t, b.MoveColumn("9", 0), board.ErrColumnNotFound)
+}
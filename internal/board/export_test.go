@@ -0,0 +1,8 @@
+package board
+
+import "time"
+
+// SetClock replaces clock of the service for testing purposes.
+func (s *Service) SetClock(now func() time.Time) {
+	s.now = now
+}
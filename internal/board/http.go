@@ -0,0 +1,497 @@
+package board
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/etag"
+	"github.com/asmazovec/team-agile/internal/idgen"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Options of the board REST API.
+type Options struct {
+	// NewID generates ids of created boards and columns.
+	NewID idgen.Generator
+	// RequireIfMatch rejects changes of boards and moves of tasks without If-Match header
+	// with 428 Precondition Required.
+	RequireIfMatch bool
+}
+
+// columnInput is user editable fields of a created column.
+type columnInput struct {
+	Name     string      `json:"name"`
+	Status   task.Status `json:"status"`
+	WIPLimit *WIPLimit   `json:"wip_limit"`
+}
+
+func (in columnInput) column(id string) Column {
+	return Column{ID: id, Name: in.Name, Status: in.Status, WIPLimit: in.WIPLimit}
+}
+
+// boardInput is user editable fields of a created board.
+// Board without a workflow gets the service workflow,
+// board without columns gets a column of each state of the workflow.
+type boardInput struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Columns     []columnInput      `json:"columns"`
+	Workflow    *workflow.Workflow `json:"workflow"`
+	Swimlanes   *Swimlanes         `json:"swimlanes"`
+}
+
+func (in boardInput) board(w workflow.Workflow, newID func() string) Board {
+	if in.Workflow != nil {
+		w = *in.Workflow
+	}
+	b := Board{Name: in.Name, Description: in.Description, Workflow: w, Swimlanes: in.Swimlanes}
+	if len(in.Columns) == 0 {
+		b.Columns = DefaultColumns(w, newID)
+		return b
+	}
+	for _, c := range in.Columns {
+		b.Columns = append(b.Columns, c.column(newID()))
+	}
+	return b
+}
+
+// boardPatch is a partial update of a board.
+// Nil fields are kept unchanged, swimlanes of empty field are removed.
+type boardPatch struct {
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	Swimlanes   *Swimlanes `json:"swimlanes"`
+}
+
+// columnCreate is a column added at the position, the column is appended without a position.
+type columnCreate struct {
+	columnInput
+	Position *int `json:"position"`
+}
+
+// Validate implements decode.Validator.
+func (in columnCreate) Validate() []decode.FieldError {
+	if in.Position != nil && *in.Position < 0 {
+		return []decode.FieldError{{Field: "position", Message: "must be a non-negative integer"}}
+	}
+	return nil
+}
+
+// columnPatch is a partial update of a column.
+// Nil fields are kept unchanged, WIP limit of zero max is removed.
+type columnPatch struct {
+	Name     *string   `json:"name"`
+	Position *int      `json:"position"`
+	WIPLimit *WIPLimit `json:"wip_limit"`
+}
+
+// Validate implements decode.Validator.
+func (p columnPatch) Validate() []decode.FieldError {
+	if p.Position != nil && *p.Position < 0 {
+		return []decode.FieldError{{Field: "position", Message: "must be a non-negative integer"}}
+	}
+	return nil
+}
+
+// Router routes REST API of the boards:
+// POST / creates a board, GET / lists boards, GET /{id} responds with the board view of tasks in columns
+// tagged by a weak ETag of the board and its tasks,
+// PATCH /{id} partially updates and DELETE /{id} deletes the empty board.
+// POST /{id}/columns adds, PATCH /{id}/columns/{column} renames or reorders
+// and DELETE /{id}/columns/{column} deletes the empty column.
+// GET /{id}/workflow responds with and PUT /{id}/workflow replaces the board workflow
+// defined in JSON or imported in YAML of application/yaml content type,
+// workflow without statuses of board tasks is rejected with 409 Conflict.
+// PUT /{id}/tasks/{task} moves the task to a column and a position, DELETE /{id}/tasks/{task} takes it off the board.
+// Moves over hard WIP limits of columns and swimlanes are rejected with 409 Conflict.
+// Changes of boards are conditional on If-Match header of the board version,
+// moves and removals of tasks on If-Match header of the task version.
+func Router(s *Service, opts Options) http.Handler {
+	h := &handler{s: s, opts: opts}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Patch("/", h.patch)
+		r.Delete("/", h.delete)
+		r.Post("/columns", h.createColumn)
+		r.Patch("/columns/{column}", h.patchColumn)
+		r.Delete("/columns/{column}", h.deleteColumn)
+		r.Get("/workflow", h.getWorkflow)
+		r.Put("/workflow", h.putWorkflow)
+		r.Put("/tasks/{task}", h.moveTask)
+		r.Delete("/tasks/{task}", h.removeTask)
+	})
+	return r
+}
+
+type handler struct {
+	s    *Service
+	opts Options
+}
+
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	var in boardInput
+	if err := decode.JSON(r, &in); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	b := in.board(h.s.Workflow(), h.opts.NewID)
+	if fields := b.Validate(); len(fields) > 0 {
+		writeFields(w, r, fields)
+		return
+	}
+	b.ID = h.opts.NewID()
+	b.CreatedAt = h.s.now()
+	b.UpdatedAt = b.CreatedAt
+	b, err := h.s.boards.Create(r.Context(), b)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Board created", "board-id", b.ID)
+	}
+	w.Header().Set("Location", r.URL.JoinPath(b.ID).Path)
+	w.Header().Set("ETag", etag.FromVersion(b.Version))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, b)
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	boards, err := h.s.boards.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	render.JSON(w, r, boards)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	v, err := h.s.View(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if etag.NotModified(w, r, viewTag(v)) {
+		return
+	}
+	render.JSON(w, r, v)
+}
+
+// viewTag returns weak entity tag of the board view changed by any change of the board or of its placed tasks.
+// The tag is weak, so it never satisfies If-Match of board changes requiring the board version.
+func viewTag(v View) string {
+	h := fnv.New64a()
+	for _, c := range v.Columns {
+		for _, t := range c.Tasks {
+			_, _ = io.WriteString(h, t.ID+":"+strconv.FormatUint(t.Version, 10)+";")
+		}
+		_, _ = io.WriteString(h, "|")
+	}
+	return `W/"` + strconv.FormatUint(v.Version, 10) + "-" + strconv.FormatUint(h.Sum64(), 16) + `"`
+}
+
+func (h *handler) patch(w http.ResponseWriter, r *http.Request) {
+	var p boardPatch
+	if err := decode.JSON(r, &p); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.update(w, r, func(b Board) (Board, error) {
+		if p.Name != nil {
+			b.Name = *p.Name
+		}
+		if p.Description != nil {
+			b.Description = *p.Description
+		}
+		switch {
+		case p.Swimlanes == nil:
+		case p.Swimlanes.By == "":
+			b.Swimlanes = nil
+		default:
+			b.Swimlanes = p.Swimlanes
+		}
+		return b, nil
+	})
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	b, err := h.s.boards.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(b.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	n, err := h.s.countTasks(r.Context(), b.ID, "")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if n > 0 {
+		writeError(w, r, ErrNotEmpty)
+		return
+	}
+	if err := h.s.boards.Delete(r.Context(), b.ID, b.Version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Board deleted", "board-id", b.ID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createColumn(w http.ResponseWriter, r *http.Request) {
+	var in columnCreate
+	if err := decode.JSON(r, &in); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.update(w, r, func(b Board) (Board, error) {
+		c := in.column(h.opts.NewID())
+		b.Columns = append(b.Columns, c)
+		if in.Position != nil {
+			return b, b.MoveColumn(c.ID, *in.Position)
+		}
+		return b, nil
+	})
+}
+
+func (h *handler) patchColumn(w http.ResponseWriter, r *http.Request) {
+	var p columnPatch
+	if err := decode.JSON(r, &p); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	id := chi.URLParam(r, "column")
+	h.update(w, r, func(b Board) (Board, error) {
+		_, i, ok := b.Column(id)
+		if !ok {
+			return b, ErrColumnNotFound
+		}
+		if p.Name != nil {
+			b.Columns[i].Name = *p.Name
+		}
+		switch {
+		case p.WIPLimit == nil:
+		case p.WIPLimit.Max == 0:
+			b.Columns[i].WIPLimit = nil
+		default:
+			b.Columns[i].WIPLimit = p.WIPLimit
+		}
+		if p.Position != nil {
+			return b, b.MoveColumn(id, *p.Position)
+		}
+		return b, nil
+	})
+}
+
+func (h *handler) deleteColumn(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "column")
+	h.update(w, r, func(b Board) (Board, error) {
+		c, i, ok := b.Column(id)
+		if !ok {
+			return b, ErrColumnNotFound
+		}
+		n, err := h.s.countTasks(r.Context(), b.ID, c.Status)
+		if err != nil {
+			return b, err
+		}
+		if n > 0 {
+			return b, ErrColumnNotEmpty
+		}
+		b.Columns = append(b.Columns[:i], b.Columns[i+1:]...)
+		return b, nil
+	})
+}
+
+// update changes the current board conditionally on If-Match header.
+func (h *handler) update(w http.ResponseWriter, r *http.Request, change func(Board) (Board, error)) {
+	b, err := h.s.boards.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(b.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	b, err = change(b)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if fields := b.Validate(); len(fields) > 0 {
+		writeFields(w, r, fields)
+		return
+	}
+	b.UpdatedAt = h.s.now()
+	b, err = h.s.boards.Update(r.Context(), b)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Board updated", "board-id", b.ID, "version", b.Version)
+	}
+	w.Header().Set("ETag", etag.FromVersion(b.Version))
+	render.JSON(w, r, b)
+}
+
+func (h *handler) getWorkflow(w http.ResponseWriter, r *http.Request) {
+	b, err := h.s.boards.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag.FromVersion(b.Version))
+	render.JSON(w, r, b.Workflow)
+}
+
+func (h *handler) putWorkflow(w http.ResponseWriter, r *http.Request) {
+	wf, err := decodeWorkflow(r)
+	if err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.update(w, r, func(b Board) (Board, error) {
+		for _, st := range b.Workflow.States {
+			if wf.Has(st) {
+				continue
+			}
+			n, err := h.s.countTasks(r.Context(), b.ID, st)
+			if err != nil {
+				return b, err
+			}
+			if n > 0 {
+				return b, ErrStatusInUse
+			}
+		}
+		b.Workflow = wf
+		return b, nil
+	})
+}
+
+// decodeWorkflow decodes JSON or YAML workflow of the request body.
+func decodeWorkflow(r *http.Request) (workflow.Workflow, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if format, err := workflow.FormatOf(mediaType); err != nil || format != workflow.FormatYAML {
+		var wf workflow.Workflow
+		err := decode.JSON(r, &wf)
+		return wf, err
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return workflow.Workflow{}, &decode.Error{Status: http.StatusRequestEntityTooLarge, Detail: err.Error(), Err: err}
+		}
+		return workflow.Workflow{}, &decode.Error{Status: http.StatusBadRequest, Detail: err.Error(), Err: err}
+	}
+	wf, err := workflow.Parse(data, workflow.FormatYAML)
+	var decErr *decode.Error
+	if err != nil && !errors.As(err, &decErr) {
+		return workflow.Workflow{}, &decode.Error{Status: http.StatusBadRequest, Detail: err.Error(), Err: err}
+	}
+	return wf, err
+}
+
+func (h *handler) moveTask(w http.ResponseWriter, r *http.Request) {
+	var m Move
+	if err := decode.JSON(r, &m); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	t, ok := h.task(w, r)
+	if !ok {
+		return
+	}
+	t, err := h.s.move(r.Context(), chi.URLParam(r, "id"), t, m)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Task moved", "board-id", t.BoardID, "task-id", t.ID, "column", m.Column)
+	}
+	w.Header().Set("ETag", etag.FromVersion(t.Version))
+	render.JSON(w, r, t)
+}
+
+func (h *handler) removeTask(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.task(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.s.remove(r.Context(), chi.URLParam(r, "id"), t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// task reads the task of the request conditionally on If-Match header,
+// the problem is written if the task is not read or the precondition fails.
+func (h *handler) task(w http.ResponseWriter, r *http.Request) (task.Task, bool) {
+	t, err := h.s.tasks.Get(r.Context(), chi.URLParam(r, "task"))
+	if err != nil {
+		writeError(w, r, err)
+		return task.Task{}, false
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(t.Version), h.opts.RequireIfMatch) {
+		return task.Task{}, false
+	}
+	return t, true
+}
+
+func writeFields(w http.ResponseWriter, r *http.Request, fields []decode.FieldError) {
+	problem.Write(w, r, decode.Problem(&decode.Error{
+		Status: http.StatusUnprocessableEntity,
+		Detail: "request body is invalid",
+		Fields: fields,
+	}))
+}
+
+// writeError writes service error as a problem.
+// Concurrent modification of a board is reported as a failed precondition,
+// concurrent modification of a moved task as a conflict.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var transErr *task.TransitionError
+	var limitErr *LimitError
+	switch {
+	case errors.As(err, &transErr):
+		problem.Write(w, r, task.TransitionProblem(transErr))
+	case errors.As(err, &limitErr):
+		problem.Write(w, r, limitErr.Problem())
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrColumnNotFound),
+		errors.Is(err, ErrTaskNotOnBoard), errors.Is(err, task.ErrNotFound):
+		problem.Write(w, r, problem.New(http.StatusNotFound, err.Error()))
+	case errors.Is(err, ErrExists), errors.Is(err, ErrNotEmpty), errors.Is(err, ErrColumnNotEmpty),
+		errors.Is(err, ErrStatusInUse):
+		problem.Write(w, r, problem.New(http.StatusConflict, err.Error()))
+	case errors.Is(err, ErrAnchorNotFound):
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, err.Error()))
+	case errors.Is(err, ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusPreconditionFailed, "entity was modified"))
+	case errors.Is(err, task.ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusConflict, "task was modified concurrently, retry the request"))
+	default:
+		if lg := mw.LoggerFrom(r.Context()); lg != nil {
+			lg.ErrorContext(r.Context(), "Board storage failed: "+err.Error())
+		}
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "board storage failed"))
+	}
+}
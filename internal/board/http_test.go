@@ -0,0 +1,303 @@
+package board_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBoardAPI(t *testing.T, taskIDs ...string) (http.Handler, *fixture) {
+	f := newFixture(t, taskIDs...)
+	r := chi.NewRouter()
+	r.Mount("/boards", board.Router(f.s, board.Options{NewID: sequence()}))
+	return r, f
+}
+
+func serveBoards(h http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouter_Create_ShouldCreateWithColumns(t *testing.T) {
+	h, _ := newBoardAPI(t)
+
+	rec := serveBoards(h, http.MethodPost, "/boards",
+		`{"name":"Kanban","columns":[{"name":"Open","status":"todo"},{"name":"Closed","status":"done"}]}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var b board.Board
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &b))
+	assert.Equal(t, "/boards/"+b.ID, rec.Header().Get("Location"))
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	assert.Equal(t, "Kanban", b.Name)
+	require.Len(t, b.Columns, 2)
+	assert.Equal(t, "Closed", b.Columns[1].Name)
+}
+
+func TestRouter_Create_DuplicateStatus_ShouldUnprocessable(t *testing.T) {
+	h, _ := newBoardAPI(t)
+
+	rec := serveBoards(h, http.MethodPost, "/boards",
+		`{"name":"Kanban","columns":[{"name":"Open","status":"todo"},{"name":"New","status":"todo"}]}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"columns.1.status"`)
+}
+
+func TestRouter_Columns_ShouldAddRenameReorderAndDelete(t *testing.T) {
+	h, _ := newBoardAPI(t)
+	serveBoards(h, http.MethodPost, "/boards", `{"name":"Kanban","columns":[{"name":"Open","status":"todo"}]}`)
+
+	added := serveBoards(h, http.MethodPost, "/boards/2/columns", `{"name":"Done","status":"done","position":0}`,
+		"If-Match", `"1"`)
+	renamed := serveBoards(h, http.MethodPatch, "/boards/2/columns/1", `{"name":"To do","position":5}`)
+	stale := serveBoards(h, http.MethodDelete, "/boards/2/columns/3", "", "If-Match", `"1"`)
+	deleted := serveBoards(h, http.MethodDelete, "/boards/2/columns/3", "")
+
+	require.Equal(t, http.StatusOK, added.Code)
+	assert.Equal(t, `"2"`, added.Header().Get("ETag"))
+	require.Equal(t, http.StatusOK, renamed.Code)
+	var b board.Board
+	require.NoError(t, json.Unmarshal(renamed.Body.Bytes(), &b))
+	assert.Equal(t, []board.Column{
+		{ID: "3", Name: "Done", Status: "done"},
+		{ID: "1", Name: "To do", Status: "todo"},
+	}, b.Columns)
+	assert.Equal(t, http.StatusPreconditionFailed, stale.Code)
+	assert.Equal(t, http.StatusOK, deleted.Code)
+}
+
+func TestRouter_MoveTask_ShouldPlaceTaskAndShowInView(t *testing.T) {
+	h, f := newBoardAPI(t, "a", "b")
+
+	first := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"2"}`)
+	second := serveBoards(h, http.MethodPut, "/boards/b1/tasks/b", `{"column":"2","before":"a"}`)
+	view := serveBoards(h, http.MethodGet, "/boards/b1", "")
+
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, `"2"`, second.Header().Get("ETag"))
+	require.Equal(t, http.StatusOK, view.Code)
+	assert.Equal(t, []string{"b", "a"}, f.column(t, 1))
+	var v struct {
+		Columns []struct {
+			ID    string `json:"id"`
+			Tasks []struct {
+				ID string `json:"id"`
+			} `json:"tasks"`
+		} `json:"columns"`
+	}
+	require.NoError(t, json.Unmarshal(view.Body.Bytes(), &v))
+	require.Len(t, v.Columns, 4)
+	assert.Empty(t, v.Columns[0].Tasks)
+	require.Len(t, v.Columns[1].Tasks, 2)
+	assert.Equal(t, "b", v.Columns[1].Tasks[0].ID)
+}
+
+func TestRouter_MoveTask_Invalid(t *testing.T) {
+	h, _ := newBoardAPI(t, "a", "b")
+
+	both := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1","before":"b","after":"b"}`)
+	anchor := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1","after":"b"}`)
+	column := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"9"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, both.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, anchor.Code)
+	assert.Equal(t, http.StatusNotFound, column.Code)
+}
+
+func TestRouter_MoveTask_RequireIfMatch_ShouldCheckTaskVersion(t *testing.T) {
+	f := newFixture(t, "a")
+	h := chi.NewRouter()
+	h.Mount("/boards", board.Router(f.s, board.Options{NewID: sequence(), RequireIfMatch: true}))
+
+	missing := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+	stale := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`, "If-Match", `"2"`)
+	moved := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`, "If-Match", `"1"`)
+	staleRemove := serveBoards(h, http.MethodDelete, "/boards/b1/tasks/a", "", "If-Match", `"1"`)
+	removed := serveBoards(h, http.MethodDelete, "/boards/b1/tasks/a", "", "If-Match", moved.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusPreconditionRequired, missing.Code)
+	assert.Equal(t, http.StatusPreconditionFailed, stale.Code)
+	assert.Equal(t, http.StatusOK, moved.Code)
+	assert.Equal(t, `"2"`, moved.Header().Get("ETag"))
+	assert.Equal(t, http.StatusPreconditionFailed, staleRemove.Code)
+	assert.Equal(t, http.StatusNoContent, removed.Code)
+}
+
+func TestRouter_Delete_NotEmpty_ShouldConflict(t *testing.T) {
+	h, _ := newBoardAPI(t, "a")
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+
+	column := serveBoards(h, http.MethodDelete, "/boards/b1/columns/1", "")
+	notEmpty := serveBoards(h, http.MethodDelete, "/boards/b1", "")
+	removed := serveBoards(h, http.MethodDelete, "/boards/b1/tasks/a", "")
+	deleted := serveBoards(h, http.MethodDelete, "/boards/b1", "")
+
+	assert.Equal(t, http.StatusConflict, column.Code)
+	assert.Equal(t, http.StatusConflict, notEmpty.Code)
+	assert.Equal(t, http.StatusNoContent, removed.Code)
+	assert.Equal(t, http.StatusNoContent, deleted.Code)
+}
+
+func TestRouter_MoveTask_NotAllowedTransition_ShouldConflictWithAllowed(t *testing.T) {
+	h, _ := newBoardAPI(t, "a")
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+
+	rec := serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"4"}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"allowed":["in-progress"]`)
+	assert.Contains(t, rec.Body.String(), `"detail":"transition from todo to done is not allowed"`)
+}
+
+func TestRouter_PutWorkflow_YAML_ShouldImport(t *testing.T) {
+	h, _ := newBoardAPI(t)
+	created := serveBoards(h, http.MethodPost, "/boards", `{"name":"Kanban","columns":[{"name":"Open","status":"todo"}]}`)
+	var b board.Board
+	require.NoError(t, json.Unmarshal(created.Body.Bytes(), &b))
+	yaml := "states: [todo, blocked]\ntransitions:\n  - from: todo\n    to: blocked\n"
+
+	imported := serveBoards(h, http.MethodPut, "/boards/"+b.ID+"/workflow", yaml, "Content-Type", "application/yaml")
+	empty := serveBoards(h, http.MethodPut, "/boards/"+b.ID+"/workflow", "{}", "Content-Type", "application/yaml")
+	got := serveBoards(h, http.MethodGet, "/boards/"+b.ID+"/workflow", "")
+
+	require.Equal(t, http.StatusOK, imported.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, empty.Code)
+	assert.JSONEq(t, `{"states":["todo","blocked"],"transitions":[{"from":"todo","to":"blocked"}]}`, got.Body.String())
+	assert.Equal(t, `"2"`, got.Header().Get("ETag"))
+}
+
+func TestRouter_PutWorkflow_WithoutTaskStatus_ShouldConflict(t *testing.T) {
+	h, f := newBoardAPI(t, "a")
+	ctx := context.Background()
+	created := serveBoards(h, http.MethodPost, "/boards",
+		`{"name":"Kanban","columns":[{"name":"Open","status":"todo"}],"workflow":{"states":["todo","blocked"],"transitions":[]}}`)
+	var b board.Board
+	require.NoError(t, json.Unmarshal(created.Body.Bytes(), &b))
+	tk, _ := f.tasks.Get(ctx, "a")
+	tk.BoardID, tk.Status = b.ID, "blocked"
+	_, err := f.tasks.Update(ctx, tk)
+	require.NoError(t, err)
+
+	removed := serveBoards(h, http.MethodPut, "/boards/"+b.ID+"/workflow", `{"states":["todo"],"transitions":[]}`)
+	kept := serveBoards(h, http.MethodPut, "/boards/"+b.ID+"/workflow", `{"states":["todo","blocked","done"],"transitions":[]}`)
+
+	assert.Equal(t, http.StatusConflict, removed.Code)
+	assert.Contains(t, removed.Body.String(), board.ErrStatusInUse.Error())
+	assert.Equal(t, http.StatusOK, kept.Code)
+}
+
+func TestRouter_PutWorkflow_WithoutColumnStates_ShouldUnprocessable(t *testing.T) {
+	h, _ := newBoardAPI(t)
+
+	rec := serveBoards(h, http.MethodPut, "/boards/b1/workflow", `{"states":["todo","done"],"transitions":[]}`)
+	malformed := serveBoards(h, http.MethodPut, "/boards/b1/workflow", "states: [todo",
+		"Content-Type", "application/yaml")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"field":"columns.1.status","message":"must be a state of the workflow"}`)
+	assert.Equal(t, http.StatusBadRequest, malformed.Code)
+}
+
+func TestRouter_Create_CustomWorkflow_ShouldDefaultColumns(t *testing.T) {
+	h, _ := newBoardAPI(t)
+
+	rec := serveBoards(h, http.MethodPost, "/boards",
+		`{"name":"Support","workflow":{"states":["open","closed"],"transitions":[{"from":"*","to":"closed"}]}}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var b board.Board
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &b))
+	require.Len(t, b.Columns, 2)
+	assert.Equal(t, "closed", b.Columns[1].Name)
+}
+
+func TestRouter_MoveTask_HardLimit_ShouldConflictUntilLimitRemoved(t *testing.T) {
+	h, _ := newBoardAPI(t, "a", "b")
+	limited := serveBoards(h, http.MethodPatch, "/boards/b1/columns/1", `{"wip_limit":{"max":1,"mode":"hard"}}`)
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+
+	rec := serveBoards(h, http.MethodPut, "/boards/b1/tasks/b", `{"column":"1"}`)
+	serveBoards(h, http.MethodPatch, "/boards/b1/columns/1", `{"wip_limit":{"max":0}}`)
+	moved := serveBoards(h, http.MethodPut, "/boards/b1/tasks/b", `{"column":"1"}`)
+
+	require.Equal(t, http.StatusOK, limited.Code)
+	assert.Contains(t, limited.Body.String(), `"wip_limit":{"max":1,"mode":"hard"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{
+		"type":"about:blank","title":"Conflict","status":409,
+		"detail":"column is at its WIP limit of 1",
+		"instance":"/boards/b1/tasks/b","column":"1","limit":1
+	}`, rec.Body.String())
+	assert.Equal(t, http.StatusOK, moved.Code)
+}
+
+func TestRouter_Get_ShouldShowLoad(t *testing.T) {
+	h, _ := newBoardAPI(t, "a", "b")
+	serveBoards(h, http.MethodPatch, "/boards/b1",
+		`{"swimlanes":{"by":"assignee","limits":{"":{"max":1,"mode":"soft"}}}}`)
+	serveBoards(h, http.MethodPatch, "/boards/b1/columns/1", `{"wip_limit":{"max":1,"mode":"soft"}}`)
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/b", `{"column":"1"}`)
+
+	rec := serveBoards(h, http.MethodGet, "/boards/b1", "")
+	serveBoards(h, http.MethodPatch, "/boards/b1", `{"swimlanes":{"by":""}}`)
+	unlaned := serveBoards(h, http.MethodGet, "/boards/b1", "")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var v struct {
+		Columns []struct {
+			Count      int              `json:"count"`
+			Overloaded bool             `json:"overloaded"`
+			Lanes      []board.LaneView `json:"lanes"`
+		} `json:"columns"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v))
+	assert.Equal(t, 2, v.Columns[0].Count)
+	assert.True(t, v.Columns[0].Overloaded)
+	require.Len(t, v.Columns[0].Lanes, 1)
+	assert.True(t, v.Columns[0].Lanes[0].Overloaded)
+	assert.NotContains(t, unlaned.Body.String(), `"lanes"`)
+}
+
+func TestRouter_Get_IfNoneMatch_ShouldNotModifyUntilTasksMove(t *testing.T) {
+	h, _ := newBoardAPI(t, "a")
+	first := serveBoards(h, http.MethodGet, "/boards/b1", "")
+	tag := first.Header().Get("ETag")
+
+	cached := serveBoards(h, http.MethodGet, "/boards/b1", "", "If-None-Match", tag)
+	serveBoards(h, http.MethodPut, "/boards/b1/tasks/a", `{"column":"1"}`)
+	moved := serveBoards(h, http.MethodGet, "/boards/b1", "", "If-None-Match", tag)
+
+	require.NotEmpty(t, tag)
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+	assert.Empty(t, cached.Body.String())
+	assert.Equal(t, http.StatusOK, moved.Code)
+	assert.NotEqual(t, tag, moved.Header().Get("ETag"))
+}
+
+func TestRouter_PatchColumn_InvalidLimit_ShouldUnprocessable(t *testing.T) {
+	h, _ := newBoardAPI(t)
+
+	rec := serveBoards(h, http.MethodPatch, "/boards/b1/columns/1", `{"wip_limit":{"max":5,"mode":"strict"}}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"field":"columns.0.wip_limit.mode","message":"must be one of soft, hard"}`)
+}
@@ -0,0 +1,100 @@
+package board
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryRepository is a Repository keeping boards in the process memory.
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	boards map[string]Board
+	order  []string
+}
+
+// NewMemoryRepository creates empty in-memory repository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{boards: make(map[string]Board)}
+}
+
+// Create implements Repository.
+func (m *MemoryRepository) Create(_ context.Context, b Board) (Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.boards[b.ID]; ok {
+		return Board{}, ErrExists
+	}
+	b.Version = 1
+	m.boards[b.ID] = clone(b)
+	m.order = append(m.order, b.ID)
+	return b, nil
+}
+
+// Get implements Repository.
+func (m *MemoryRepository) Get(_ context.Context, id string) (Board, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.boards[id]
+	if !ok {
+		return Board{}, ErrNotFound
+	}
+	return clone(b), nil
+}
+
+// List implements Repository.
+func (m *MemoryRepository) List(_ context.Context) ([]Board, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	boards := make([]Board, 0, len(m.order))
+	for _, id := range m.order {
+		boards = append(boards, clone(m.boards[id]))
+	}
+	return boards, nil
+}
+
+// Update implements Repository.
+func (m *MemoryRepository) Update(_ context.Context, b Board) (Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.boards[b.ID]
+	if !ok {
+		return Board{}, ErrNotFound
+	}
+	if stored.Version != b.Version {
+		return Board{}, ErrVersionConflict
+	}
+	b.Version++
+	m.boards[b.ID] = clone(b)
+	return b, nil
+}
+
+// Delete implements Repository.
+func (m *MemoryRepository) Delete(_ context.Context, id string, version uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.boards[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	delete(m.boards, id)
+	m.order = slices.DeleteFunc(m.order, func(s string) bool { return s == id })
+	return nil
+}
+
+// clone copies the board deep enough to never share mutable state with callers.
+func clone(b Board) Board {
+	b.Columns = slices.Clone(b.Columns)
+	for i, c := range b.Columns {
+		if c.WIPLimit != nil {
+			l := *c.WIPLimit
+			b.Columns[i].WIPLimit = &l
+		}
+	}
+	b.Workflow = b.Workflow.Clone()
+	b.Swimlanes = b.Swimlanes.clone()
+	return b
+}
@@ -0,0 +1,43 @@
+package board_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_ShouldStoreVersionedBoards(t *testing.T) {
+	repo := board.NewMemoryRepository()
+	ctx := context.Background()
+
+	w := workflow.Default()
+	created, err := repo.Create(ctx, board.Board{ID: "b1", Name: "Team", Columns: board.DefaultColumns(w, sequence()), Workflow: w})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, board.Board{ID: "b1"})
+	assert.ErrorIs(t, err, board.ErrExists)
+
+	got, err := repo.Get(ctx, "b1")
+	require.NoError(t, err)
+	got.Columns[0].Name = "Changed"
+	got.Name = "Core team"
+	updated, err := repo.Update(ctx, got)
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, got)
+	assert.ErrorIs(t, err, board.ErrVersionConflict)
+
+	assert.Equal(t, uint64(1), created.Version)
+	assert.Equal(t, "To do", created.Columns[0].Name)
+	assert.Equal(t, uint64(2), updated.Version)
+	list, _ := repo.List(ctx)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Core team", list[0].Name)
+
+	assert.ErrorIs(t, repo.Delete(ctx, "b1", 1), board.ErrVersionConflict)
+	require.NoError(t, repo.Delete(ctx, "b1", 2))
+	_, err = repo.Get(ctx, "b1")
+	assert.ErrorIs(t, err, board.ErrNotFound)
+}
@@ -0,0 +1,21 @@
+package board
+
+import "context"
+
+// Repository stores boards.
+// Boards are listed in the creation order.
+type Repository interface {
+	// Create stores the new board with version 1.
+	// Returns ErrExists if the board id is taken.
+	Create(ctx context.Context, b Board) (Board, error)
+	// Get returns the board or ErrNotFound.
+	Get(ctx context.Context, id string) (Board, error)
+	// List returns all boards.
+	List(ctx context.Context) ([]Board, error)
+	// Update replaces the stored board of the same version and returns it with the next version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Update(ctx context.Context, b Board) (Board, error)
+	// Delete removes the board of the version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Delete(ctx context.Context, id string, version uint64) error
+}
@@ -0,0 +1,351 @@
+package board
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/rank"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+)
+
+var (
+	// ErrTaskNotOnBoard means task is not placed on the board.
+	ErrTaskNotOnBoard = errors.New("task is not on the board")
+	// ErrAnchorNotFound means task to place the moved task next to is not in the target column.
+	ErrAnchorNotFound = errors.New("anchor task is not in the column")
+)
+
+// ColumnView is a column with its tasks ordered by rank and its load.
+// Column over its WIP limit is overloaded, lanes are shown for boards with swimlanes.
+type ColumnView struct {
+	Column
+	Count      int         `json:"count"`
+	Overloaded bool        `json:"overloaded"`
+	Lanes      []LaneView  `json:"lanes,omitempty"`
+	Tasks      []task.Task `json:"tasks"`
+}
+
+// View is a read model of the board with tasks placed in columns.
+type View struct {
+	Board
+	Columns []ColumnView `json:"columns"`
+}
+
+// Move places a task in the column of the board.
+// The task is placed right before or right after the anchor task of the column,
+// or at the bottom of the column without an anchor.
+type Move struct {
+	Column string `json:"column"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Validate implements decode.Validator.
+func (m Move) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	if m.Column == "" {
+		fields = append(fields, decode.FieldError{Field: "column", Message: "must not be empty"})
+	}
+	if m.Before != "" && m.After != "" {
+		fields = append(fields, decode.FieldError{Field: "after", Message: "must not be set together with before"})
+	}
+	return fields
+}
+
+// Service places tasks on boards and switches them by workflows of the boards.
+type Service struct {
+	boards   Repository
+	tasks    task.Repository
+	workflow workflow.Workflow
+	notify   workflow.Notifier
+	overload OverloadNotifier
+	now      func() time.Time
+}
+
+// ServiceOption configures the service.
+type ServiceOption func(*Service)
+
+// WithWorkflow sets the workflow of created boards and tasks off boards, workflow.Default by default.
+func WithWorkflow(w workflow.Workflow) ServiceOption {
+	return func(s *Service) {
+		s.workflow = w
+	}
+}
+
+// WithNotifier sets the notifier of transitions with the notify action.
+func WithNotifier(n workflow.Notifier) ServiceOption {
+	return func(s *Service) {
+		s.notify = n
+	}
+}
+
+// WithOverloadNotifier sets the notifier of soft WIP limits exceeded by entered tasks.
+func WithOverloadNotifier(n OverloadNotifier) ServiceOption {
+	return func(s *Service) {
+		s.overload = n
+	}
+}
+
+// NewService creates service of the boards placing tasks of the repository.
+func NewService(boards Repository, tasks task.Repository, opts ...ServiceOption) *Service {
+	s := &Service{boards: boards, tasks: tasks, workflow: workflow.Default(), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Workflow returns the workflow of created boards and tasks off boards.
+func (s *Service) Workflow() workflow.Workflow {
+	return s.workflow.Clone()
+}
+
+// Save checks the status change of the task by the workflow of its board, applies actions of the transition
+// and stores the task, creation of a task is a change from the zero task.
+// Tasks off boards are switched by the service workflow.
+// Tasks entering a column or a swimlane of the board are checked by their WIP limits.
+// Transitions and exceeded soft limits are notified only once the task is saved.
+// It fits task.Options.Save.
+func (s *Service) Save(ctx context.Context, before, after task.Task) (task.Task, error) {
+	w, b, err := s.workflowOf(ctx, after.BoardID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	after, n, err := s.apply(w, before, after)
+	if err != nil {
+		return task.Task{}, err
+	}
+	saved, err := s.store(ctx, b, before, after, &n)
+	if err != nil {
+		return task.Task{}, err
+	}
+	s.send(ctx, n, saved)
+	return saved, nil
+}
+
+// workflowOf returns the workflow switching tasks of the board and the board itself.
+// Tasks off boards are switched by the service workflow and have no board.
+func (s *Service) workflowOf(ctx context.Context, boardID string) (workflow.Workflow, *Board, error) {
+	if boardID == "" {
+		return s.workflow, nil, nil
+	}
+	b, err := s.boards.Get(ctx, boardID)
+	if errors.Is(err, ErrNotFound) {
+		return s.workflow, nil, nil
+	}
+	if err != nil {
+		return workflow.Workflow{}, nil, err
+	}
+	return b.Workflow, &b, nil
+}
+
+// store saves the changed task. Task in a column of the board is admitted by WIP limits of the column
+// atomically with the save, exceeded soft limits are added to the notices.
+func (s *Service) store(ctx context.Context, b *Board, before, after task.Task, n *notices) (task.Task, error) {
+	if b == nil {
+		return task.Save(ctx, s.tasks, before, after)
+	}
+	c, ok := b.ColumnOf(after.Status)
+	if !ok {
+		return task.Save(ctx, s.tasks, before, after)
+	}
+	return s.tasks.SaveChecked(ctx, after, task.Filter{BoardID: b.ID, Status: c.Status}, func(column []task.Task) error {
+		overloads, err := admit(*b, c, before, after, column)
+		n.overloads = overloads
+		return err
+	})
+}
+
+// admit checks WIP limits of the column and the swimlane entered by the task.
+// Exceeded hard limits are errors, exceeded soft limits are returned to be notified.
+func admit(b Board, c Column, before, after task.Task, column []task.Task) ([]Overload, error) {
+	overloads := b.overloads(c, before, after, column)
+	for _, o := range overloads {
+		if o.Limit.Mode == WIPHard {
+			return nil, &LimitError{Overload: o}
+		}
+	}
+	return overloads, nil
+}
+
+// notices of a checked change of a task sent once the task is saved.
+type notices struct {
+	from      task.Status
+	notify    bool
+	overloads []Overload
+}
+
+// apply switches the task by the workflow.
+func (s *Service) apply(w workflow.Workflow, before, after task.Task) (task.Task, notices, error) {
+	after, tr, err := w.Apply(before, after, s.now())
+	if err != nil {
+		return task.Task{}, notices{}, err
+	}
+	return after, notices{from: before.Status, notify: tr.Notifies()}, nil
+}
+
+// send notifies of exceeded soft limits and of the transition of the saved task.
+func (s *Service) send(ctx context.Context, n notices, saved task.Task) {
+	if s.overload != nil {
+		for _, o := range n.overloads {
+			s.overload(ctx, o)
+		}
+	}
+	if n.notify && s.notify != nil {
+		s.notify(ctx, saved, n.from)
+	}
+}
+
+// View returns the board with its tasks placed in columns of their statuses.
+// Tasks of statuses without a column are not shown.
+func (s *Service) View(ctx context.Context, id string) (View, error) {
+	b, err := s.boards.Get(ctx, id)
+	if err != nil {
+		return View{}, err
+	}
+	page, err := s.tasks.List(ctx, task.Filter{BoardID: id})
+	if err != nil {
+		return View{}, err
+	}
+	sortByRank(page.Items)
+
+	v := View{Board: b, Columns: make([]ColumnView, 0, len(b.Columns))}
+	for _, c := range b.Columns {
+		cv := ColumnView{Column: c, Tasks: []task.Task{}}
+		for _, t := range page.Items {
+			if t.Status == c.Status {
+				cv.Tasks = append(cv.Tasks, t)
+			}
+		}
+		cv.Count = len(cv.Tasks)
+		cv.Overloaded = c.WIPLimit != nil && c.WIPLimit.Exceeded(cv.Count)
+		if b.Swimlanes != nil {
+			cv.Lanes = b.Swimlanes.lanes(cv.Tasks)
+		}
+		v.Columns = append(v.Columns, cv)
+	}
+	return v, nil
+}
+
+// Move places the task in the column of the board switching the task to the column status
+// by the board workflow and checking WIP limits of the column. Only the moved task is changed, its rank is chosen between ranks of the new neighbours.
+func (s *Service) Move(ctx context.Context, boardID, taskID string, m Move) (task.Task, error) {
+	t, err := s.tasks.Get(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	return s.move(ctx, boardID, t, m)
+}
+
+// move places the task of the read version, the move fails with task.ErrVersionConflict if the task is changed since.
+func (s *Service) move(ctx context.Context, boardID string, t task.Task, m Move) (task.Task, error) {
+	b, err := s.boards.Get(ctx, boardID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	c, _, ok := b.Column(m.Column)
+	if !ok {
+		return task.Task{}, ErrColumnNotFound
+	}
+	page, err := s.tasks.List(ctx, task.Filter{BoardID: boardID, Status: c.Status})
+	if err != nil {
+		return task.Task{}, err
+	}
+	column := slices.DeleteFunc(page.Items, func(other task.Task) bool { return other.ID == t.ID })
+	sortByRank(column)
+
+	pos := len(column)
+	if anchor := cmp.Or(m.Before, m.After); anchor != "" {
+		i := slices.IndexFunc(column, func(other task.Task) bool { return other.ID == anchor })
+		if i < 0 {
+			return task.Task{}, ErrAnchorNotFound
+		}
+		pos = i
+		if m.After != "" {
+			pos++
+		}
+	}
+	r, err := rankAt(column, pos)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	moved := t
+	moved.BoardID = b.ID
+	moved.Status = c.Status
+	moved.Rank = r
+	moved.UpdatedAt = s.now()
+	// Placing a task from outside the board is not a transition, the task may take any state of the board.
+	before := t
+	if t.BoardID != b.ID {
+		before = task.Task{}
+	}
+	moved, n, err := s.apply(b.Workflow, before, moved)
+	if err != nil {
+		return task.Task{}, err
+	}
+	saved, err := s.store(ctx, &b, t, moved, &n)
+	if err != nil {
+		return task.Task{}, err
+	}
+	s.send(ctx, n, saved)
+	return saved, nil
+}
+
+// Remove takes the task off the board and out of its sprint.
+func (s *Service) Remove(ctx context.Context, boardID, taskID string) (task.Task, error) {
+	t, err := s.tasks.Get(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	return s.remove(ctx, boardID, t)
+}
+
+// remove takes off the task of the read version, the removal fails with task.ErrVersionConflict
+// if the task is changed since.
+func (s *Service) remove(ctx context.Context, boardID string, t task.Task) (task.Task, error) {
+	if t.BoardID != boardID {
+		return task.Task{}, ErrTaskNotOnBoard
+	}
+	t.BoardID = ""
+	t.Rank = ""
+	t.SprintID = ""
+	t.UpdatedAt = s.now()
+	return s.tasks.Update(ctx, t)
+}
+
+// countTasks counts tasks placed on the board, status filters tasks if not empty.
+func (s *Service) countTasks(ctx context.Context, boardID string, status task.Status) (int, error) {
+	page, err := s.tasks.List(ctx, task.Filter{BoardID: boardID, Status: status, Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// rankAt returns a rank placing a task at the position of the tasks ordered by rank.
+// Neighbours of equal ranks are skipped to always find a gap.
+func rankAt(tasks []task.Task, pos int) (string, error) {
+	var prev, next string
+	if pos > 0 {
+		prev = tasks[pos-1].Rank
+	}
+	for _, t := range tasks[pos:] {
+		if t.Rank > prev {
+			next = t.Rank
+			break
+		}
+	}
+	return rank.Between(prev, next)
+}
+
+func sortByRank(tasks []task.Task) {
+	slices.SortStableFunc(tasks, func(a, b task.Task) int {
+		return cmp.Or(cmp.Compare(a.Rank, b.Rank), cmp.Compare(a.ID, b.ID))
+	})
+}
@@ -0,0 +1,247 @@
+package board_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	s      *board.Service
+	boards *board.MemoryRepository
+	tasks  *task.MemoryRepository
+	board  board.Board
+}
+
+// newFixture creates a board with the default columns 1..4 and unplaced tasks of the ids.
+func newFixture(t *testing.T, taskIDs ...string) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	boards := board.NewMemoryRepository()
+	tasks := task.NewMemoryRepository()
+	w := workflow.Default()
+	b, err := boards.Create(ctx, board.Board{
+		ID:       "b1",
+		Name:     "Team",
+		Columns:  board.DefaultColumns(w, sequence()),
+		Workflow: w,
+	})
+	require.NoError(t, err)
+	for _, id := range taskIDs {
+		_, err := tasks.Create(ctx, task.Task{ID: id, Title: id, Status: task.StatusTodo, Priority: task.PriorityMedium})
+		require.NoError(t, err)
+	}
+	s := board.NewService(boards, tasks)
+	s.SetClock(func() time.Time { return testNow })
+	return &fixture{s: s, boards: boards, tasks: tasks, board: b}
+}
+
+func (f *fixture) column(t *testing.T, i int) []string {
+	t.Helper()
+	v, err := f.s.View(context.Background(), f.board.ID)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, tk := range v.Columns[i].Tasks {
+		ids = append(ids, tk.ID)
+	}
+	return ids
+}
+
+func TestService_Move_ShouldPlaceByAnchors(t *testing.T) {
+	f := newFixture(t, "a", "b", "c", "d")
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b"} {
+		_, err := f.s.Move(ctx, "b1", id, board.Move{Column: "1"})
+		require.NoError(t, err)
+	}
+	_, err := f.s.Move(ctx, "b1", "c", board.Move{Column: "1", Before: "a"})
+	require.NoError(t, err)
+	_, err = f.s.Move(ctx, "b1", "d", board.Move{Column: "1", After: "c"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"c", "d", "a", "b"}, f.column(t, 0))
+}
+
+func TestService_Move_ToAnotherColumn_ShouldSwitchStatus(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	ctx := context.Background()
+	_, _ = f.s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+	_, _ = f.s.Move(ctx, "b1", "b", board.Move{Column: "1"})
+
+	moved, err := f.s.Move(ctx, "b1", "a", board.Move{Column: "2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, task.StatusInProgress, moved.Status)
+	assert.Equal(t, "b1", moved.BoardID)
+	assert.Equal(t, testNow, moved.UpdatedAt)
+	assert.Equal(t, []string{"b"}, f.column(t, 0))
+	assert.Equal(t, []string{"a"}, f.column(t, 1))
+}
+
+func TestService_Move_ShouldChangeOnlyMovedTask(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		_, _ = f.s.Move(ctx, "b1", id, board.Move{Column: "1"})
+	}
+	before, _ := f.tasks.List(ctx, task.Filter{})
+
+	_, err := f.s.Move(ctx, "b1", "c", board.Move{Column: "1", After: "a"})
+	require.NoError(t, err)
+
+	after, _ := f.tasks.List(ctx, task.Filter{})
+	assert.Equal(t, before.Items[0], after.Items[0])
+	assert.Equal(t, before.Items[1], after.Items[1])
+	assert.Equal(t, []string{"a", "c", "b"}, f.column(t, 0))
+}
+
+func TestService_Move_Errors(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	ctx := context.Background()
+
+	_, err := f.s.Move(ctx, "b2", "a", board.Move{Column: "1"})
+	assert.ErrorIs(t, err, board.ErrNotFound)
+	_, err = f.s.Move(ctx, "b1", "a", board.Move{Column: "9"})
+	assert.ErrorIs(t, err, board.ErrColumnNotFound)
+	_, err = f.s.Move(ctx, "b1", "z", board.Move{Column: "1"})
+	assert.ErrorIs(t, err, task.ErrNotFound)
+	_, err = f.s.Move(ctx, "b1", "a", board.Move{Column: "1", After: "b"})
+	assert.ErrorIs(t, err, board.ErrAnchorNotFound)
+}
+
+func TestService_Remove_ShouldTakeTaskOffBoard(t *testing.T) {
+	f := newFixture(t, "a")
+	ctx := context.Background()
+	placed, _ := f.s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+	placed.SprintID = "s1"
+	_, _ = f.tasks.Update(ctx, placed)
+
+	removed, err := f.s.Remove(ctx, "b1", "a")
+	require.NoError(t, err)
+	_, err = f.s.Remove(ctx, "b1", "a")
+
+	assert.Empty(t, removed.BoardID)
+	assert.Empty(t, removed.Rank)
+	assert.Empty(t, removed.SprintID)
+	assert.ErrorIs(t, err, board.ErrTaskNotOnBoard)
+	assert.Empty(t, f.column(t, 0))
+}
+
+func TestService_Move_NotAllowedTransition_ShouldError(t *testing.T) {
+	f := newFixture(t, "a")
+	ctx := context.Background()
+	_, _ = f.s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+
+	_, err := f.s.Move(ctx, "b1", "a", board.Move{Column: "4"})
+
+	var transErr *task.TransitionError
+	require.ErrorAs(t, err, &transErr)
+	assert.Equal(t, []task.Status{task.StatusInProgress}, transErr.Allowed)
+	assert.Equal(t, []string{"a"}, f.column(t, 0))
+}
+
+func TestService_Save_ShouldApplyActionsAndNotify(t *testing.T) {
+	ctx := context.Background()
+	tasks := task.NewMemoryRepository()
+	var notified []task.Status
+	s := board.NewService(board.NewMemoryRepository(), tasks,
+		board.WithNotifier(func(_ context.Context, tk task.Task, from task.Status) {
+			notified = append(notified, from, tk.Status)
+		}))
+	s.SetClock(func() time.Time { return testNow })
+	review, _ := tasks.Create(ctx, task.Task{ID: "a", Title: "a", Status: task.StatusReview})
+	todo, _ := tasks.Create(ctx, task.Task{ID: "b", Title: "b", Status: task.StatusTodo})
+
+	changed := review
+	changed.Status = task.StatusDone
+	done, err := s.Save(ctx, review, changed)
+	require.NoError(t, err)
+	changed = todo
+	changed.Status = task.StatusDone
+	_, err = s.Save(ctx, todo, changed)
+
+	require.NotNil(t, done.ResolvedAt)
+	assert.Equal(t, testNow, *done.ResolvedAt)
+	assert.Equal(t, review.Version+1, done.Version)
+	assert.Equal(t, []task.Status{task.StatusReview, task.StatusDone}, notified)
+	assert.Error(t, err)
+}
+
+func TestService_Save_VersionConflict_ShouldNotNotify(t *testing.T) {
+	ctx := context.Background()
+	tasks := task.NewMemoryRepository()
+	notified := 0
+	s := board.NewService(board.NewMemoryRepository(), tasks,
+		board.WithNotifier(func(context.Context, task.Task, task.Status) { notified++ }))
+	review, _ := tasks.Create(ctx, task.Task{ID: "a", Title: "a", Status: task.StatusReview})
+	concurrent := review
+	concurrent.Title = "Changed"
+	_, _ = tasks.Update(ctx, concurrent)
+
+	changed := review
+	changed.Status = task.StatusDone
+	_, err := s.Save(ctx, review, changed)
+
+	assert.ErrorIs(t, err, task.ErrVersionConflict)
+	assert.Zero(t, notified)
+}
+
+func TestService_Move_OffBoardToGuardedColumn_ShouldCheckGuards(t *testing.T) {
+	ctx := context.Background()
+	boards := board.NewMemoryRepository()
+	tasks := task.NewMemoryRepository()
+	w := workflow.Workflow{
+		States:      []task.Status{"open", "closed"},
+		Transitions: []workflow.Transition{{From: "open", To: "closed", Guards: []workflow.Guard{workflow.GuardAssignee}}},
+	}
+	_, _ = boards.Create(ctx, board.Board{ID: "b1", Name: "Team", Columns: board.DefaultColumns(w, sequence()), Workflow: w})
+	_, _ = tasks.Create(ctx, task.Task{ID: "a", Title: "a", Status: "open"})
+	s := board.NewService(boards, tasks, board.WithWorkflow(w))
+	_, err := s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+	require.NoError(t, err)
+	_, err = s.Remove(ctx, "b1", "a")
+	require.NoError(t, err)
+
+	_, err = s.Move(ctx, "b1", "a", board.Move{Column: "2"})
+
+	assert.EqualError(t, err, "assignee is required")
+}
+
+func TestService_Save_CreatedDone_ShouldResolve(t *testing.T) {
+	s := board.NewService(board.NewMemoryRepository(), task.NewMemoryRepository())
+	s.SetClock(func() time.Time { return testNow })
+
+	created, err := s.Save(context.Background(), task.Task{}, task.Task{ID: "a", Title: "a", Status: task.StatusDone})
+
+	require.NoError(t, err)
+	require.NotNil(t, created.ResolvedAt)
+	assert.Equal(t, testNow, *created.ResolvedAt)
+}
+
+func TestService_Save_ShouldUseBoardWorkflow(t *testing.T) {
+	ctx := context.Background()
+	boards := board.NewMemoryRepository()
+	w := workflow.Workflow{
+		States:      []task.Status{"open", "closed"},
+		Transitions: []workflow.Transition{{From: "open", To: "closed", Guards: []workflow.Guard{workflow.GuardAssignee}}},
+	}
+	_, _ = boards.Create(ctx, board.Board{ID: "b1", Name: "Team", Columns: board.DefaultColumns(w, sequence()), Workflow: w})
+	s := board.NewService(boards, task.NewMemoryRepository(), board.WithWorkflow(w))
+
+	_, err := s.Save(ctx,
+		task.Task{BoardID: "b1", Status: "open"}, task.Task{BoardID: "b1", Status: "closed"})
+	_, offBoard := s.Save(ctx, task.Task{}, task.Task{Status: task.StatusTodo})
+
+	assert.EqualError(t, err, "assignee is required")
+	assert.EqualError(t, offBoard, "status todo is not allowed")
+	assert.Equal(t, w, s.Workflow())
+}
@@ -0,0 +1,220 @@
+package board
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/task"
+)
+
+// MaxWIPLimit is the greatest work in progress limit.
+const MaxWIPLimit = 1000
+
+// WIPMode is the enforcement mode of a work in progress limit.
+type WIPMode string
+
+// Known enforcement modes.
+const (
+	// WIPSoft allows exceeding the limit, overloads are flagged and reported.
+	WIPSoft WIPMode = "soft"
+	// WIPHard rejects tasks exceeding the limit.
+	WIPHard WIPMode = "hard"
+)
+
+// WIPLimit is the greatest number of tasks in a column or in a swimlane of a column.
+type WIPLimit struct {
+	Max  int     `json:"max"`
+	Mode WIPMode `json:"mode"`
+}
+
+// Exceeded reports whether the number of tasks is over the limit.
+func (l WIPLimit) Exceeded(count int) bool {
+	return count > l.Max
+}
+
+func (l WIPLimit) validate(field string) []decode.FieldError {
+	var fields []decode.FieldError
+	if l.Max < 1 || l.Max > MaxWIPLimit {
+		fields = append(fields, decode.FieldError{Field: field + ".max", Message: "must be between 1 and " + strconv.Itoa(MaxWIPLimit)})
+	}
+	if l.Mode != WIPSoft && l.Mode != WIPHard {
+		fields = append(fields, decode.FieldError{Field: field + ".mode", Message: "must be one of soft, hard"})
+	}
+	return fields
+}
+
+// SwimlaneField is the task field grouping tasks of the board into swimlanes.
+type SwimlaneField string
+
+// Known swimlane fields.
+const (
+	SwimlaneAssignee SwimlaneField = "assignee"
+	SwimlanePriority SwimlaneField = "priority"
+)
+
+// Swimlanes group tasks of each column of the board into lanes by the value of the task field,
+// unassigned tasks are in the lane of empty name.
+// Limits cap tasks of the lane in each column.
+type Swimlanes struct {
+	By     SwimlaneField       `json:"by"`
+	Limits map[string]WIPLimit `json:"limits,omitempty"`
+}
+
+// Lane returns the lane of the task.
+func (s Swimlanes) Lane(t task.Task) string {
+	if s.By == SwimlanePriority {
+		return string(t.Priority)
+	}
+	return t.Assignee
+}
+
+func (s Swimlanes) validate() []decode.FieldError {
+	var fields []decode.FieldError
+	if s.By != SwimlaneAssignee && s.By != SwimlanePriority {
+		fields = append(fields, decode.FieldError{Field: "swimlanes.by", Message: "must be one of assignee, priority"})
+	}
+	for _, lane := range sortedKeys(s.Limits) {
+		field := "swimlanes.limits." + lane
+		if s.By == SwimlanePriority && !task.Priority(lane).Valid() {
+			fields = append(fields, decode.FieldError{Field: field, Message: "must be a lane of a known priority"})
+		}
+		fields = append(fields, s.Limits[lane].validate(field)...)
+	}
+	return fields
+}
+
+func (s *Swimlanes) clone() *Swimlanes {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Limits = maps.Clone(s.Limits)
+	return &c
+}
+
+// Overload is a work in progress limit exceeded by a task entering a column or a swimlane of the column.
+type Overload struct {
+	BoardID  string `json:"board_id"`
+	ColumnID string `json:"column_id"`
+	// Swimlane reports the limit is of the lane in the column rather than of the whole column.
+	Swimlane bool     `json:"swimlane"`
+	Lane     string   `json:"lane,omitempty"`
+	TaskID   string   `json:"task_id"`
+	Limit    WIPLimit `json:"limit"`
+	// Count is the number of tasks with the entering one.
+	Count int `json:"count"`
+}
+
+// OverloadNotifier is notified of soft limits exceeded by entered tasks.
+type OverloadNotifier func(ctx context.Context, o Overload)
+
+// LimitError means a task can not enter a column or a swimlane over its hard limit.
+type LimitError struct {
+	Overload
+}
+
+// Error implements error interface.
+func (e *LimitError) Error() string {
+	if e.Swimlane {
+		return "swimlane " + strconv.Quote(e.Lane) + " of the column is at its WIP limit of " + strconv.Itoa(e.Limit.Max)
+	}
+	return "column is at its WIP limit of " + strconv.Itoa(e.Limit.Max)
+}
+
+// Problem describes the error as 409 Conflict problem with the column, lane and limit extensions.
+func (e *LimitError) Problem() *problem.Details {
+	d := problem.New(http.StatusConflict, e.Error()).
+		With("column", e.ColumnID).
+		With("limit", e.Limit.Max)
+	if e.Swimlane {
+		d = d.With("lane", e.Lane)
+	}
+	return d
+}
+
+// LaneView is a swimlane of a column with its load.
+type LaneView struct {
+	Lane       string    `json:"lane"`
+	Count      int       `json:"count"`
+	WIPLimit   *WIPLimit `json:"wip_limit,omitempty"`
+	Overloaded bool      `json:"overloaded"`
+}
+
+// lanes returns lanes of the column tasks and lanes with limits ordered by name.
+func (s Swimlanes) lanes(tasks []task.Task) []LaneView {
+	counts := make(map[string]int, len(s.Limits))
+	for lane := range s.Limits {
+		counts[lane] = 0
+	}
+	for _, t := range tasks {
+		counts[s.Lane(t)]++
+	}
+	views := make([]LaneView, 0, len(counts))
+	for _, lane := range sortedKeys(counts) {
+		v := LaneView{Lane: lane, Count: counts[lane]}
+		if l, ok := s.Limits[lane]; ok {
+			v.WIPLimit = &l
+			v.Overloaded = l.Exceeded(v.Count)
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// overloads returns limits of the column and its swimlane exceeded by the task entering them.
+// The task enters the column if it comes from another board or status
+// and enters the swimlane if it enters the column or changes its lane.
+// Column tasks never include the entering task.
+func (b Board) overloads(c Column, before, after task.Task, column []task.Task) []Overload {
+	enters := before.BoardID != after.BoardID || before.Status != after.Status
+	var overloads []Overload
+	if c.WIPLimit != nil && enters && c.WIPLimit.Exceeded(len(column)+1) {
+		overloads = append(overloads, Overload{
+			BoardID:  b.ID,
+			ColumnID: c.ID,
+			TaskID:   after.ID,
+			Limit:    *c.WIPLimit,
+			Count:    len(column) + 1,
+		})
+	}
+	if b.Swimlanes == nil {
+		return overloads
+	}
+	lane := b.Swimlanes.Lane(after)
+	l, ok := b.Swimlanes.Limits[lane]
+	if !ok || !enters && b.Swimlanes.Lane(before) == lane {
+		return overloads
+	}
+	count := 1
+	for _, t := range column {
+		if b.Swimlanes.Lane(t) == lane {
+			count++
+		}
+	}
+	if l.Exceeded(count) {
+		overloads = append(overloads, Overload{
+			BoardID:  b.ID,
+			ColumnID: c.ID,
+			Swimlane: true,
+			Lane:     lane,
+			TaskID:   after.ID,
+			Limit:    l,
+			Count:    count,
+		})
+	}
+	return overloads
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
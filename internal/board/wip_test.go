@@ -0,0 +1,196 @@
+package board_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limit changes the fixture board and returns the service reporting overloads to the slice.
+func (f *fixture) limit(t *testing.T, change func(b *board.Board)) (*board.Service, *[]board.Overload) {
+	t.Helper()
+	b, err := f.boards.Get(context.Background(), f.board.ID)
+	require.NoError(t, err)
+	change(&b)
+	_, err = f.boards.Update(context.Background(), b)
+	require.NoError(t, err)
+	var overloads []board.Overload
+	s := board.NewService(f.boards, f.tasks, board.WithOverloadNotifier(func(_ context.Context, o board.Overload) {
+		overloads = append(overloads, o)
+	}))
+	s.SetClock(func() time.Time { return testNow })
+	return s, &overloads
+}
+
+func TestService_Move_HardColumnLimit_ShouldReject(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	s, overloads := f.limit(t, func(b *board.Board) {
+		b.Columns[0].WIPLimit = &board.WIPLimit{Max: 2, Mode: board.WIPHard}
+	})
+	ctx := context.Background()
+	_, _ = s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+	_, _ = s.Move(ctx, "b1", "b", board.Move{Column: "1"})
+
+	_, err := s.Move(ctx, "b1", "c", board.Move{Column: "1"})
+	_, reorder := s.Move(ctx, "b1", "b", board.Move{Column: "1", Before: "a"})
+
+	var limitErr *board.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, board.Overload{
+		BoardID:  "b1",
+		ColumnID: "1",
+		TaskID:   "c",
+		Limit:    board.WIPLimit{Max: 2, Mode: board.WIPHard},
+		Count:    3,
+	}, limitErr.Overload)
+	assert.NoError(t, reorder)
+	assert.Empty(t, *overloads)
+}
+
+// racingTasks runs the race once right before the first task is saved.
+type racingTasks struct {
+	task.Repository
+	race func()
+}
+
+func (r *racingTasks) run() {
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+}
+
+func (r *racingTasks) Update(ctx context.Context, t task.Task) (task.Task, error) {
+	r.run()
+	return r.Repository.Update(ctx, t)
+}
+
+func (r *racingTasks) SaveChecked(
+	ctx context.Context, t task.Task, f task.Filter, check func([]task.Task) error,
+) (task.Task, error) {
+	r.run()
+	return r.Repository.SaveChecked(ctx, t, f, check)
+}
+
+func TestService_Move_ConcurrentHardColumnLimit_ShouldReject(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	f.limit(t, func(b *board.Board) {
+		b.Columns[0].WIPLimit = &board.WIPLimit{Max: 1, Mode: board.WIPHard}
+	})
+	ctx := context.Background()
+	tasks := &racingTasks{Repository: f.tasks}
+	s := board.NewService(f.boards, tasks)
+	tasks.race = func() {
+		_, err := s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+		require.NoError(t, err)
+	}
+
+	_, err := s.Move(ctx, "b1", "b", board.Move{Column: "1"})
+
+	var limitErr *board.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, []string{"a"}, f.column(t, 0))
+}
+
+func TestService_Move_SoftColumnLimit_ShouldAllowAndReport(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	s, overloads := f.limit(t, func(b *board.Board) {
+		b.Columns[0].WIPLimit = &board.WIPLimit{Max: 1, Mode: board.WIPSoft}
+	})
+	ctx := context.Background()
+	_, _ = s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+
+	_, err := s.Move(ctx, "b1", "b", board.Move{Column: "1"})
+	require.NoError(t, err)
+	v, err := s.View(ctx, "b1")
+	require.NoError(t, err)
+
+	require.Len(t, *overloads, 1)
+	assert.Equal(t, "b", (*overloads)[0].TaskID)
+	assert.Equal(t, 2, v.Columns[0].Count)
+	assert.True(t, v.Columns[0].Overloaded)
+	assert.False(t, v.Columns[1].Overloaded)
+}
+
+func TestService_Save_HardSwimlaneLimit_ShouldRejectLaneChange(t *testing.T) {
+	f := newFixture(t, "a", "b")
+	s, _ := f.limit(t, func(b *board.Board) {
+		b.Swimlanes = &board.Swimlanes{
+			By:     board.SwimlaneAssignee,
+			Limits: map[string]board.WIPLimit{"alice": {Max: 1, Mode: board.WIPHard}},
+		}
+	})
+	ctx := context.Background()
+	a, _ := s.Move(ctx, "b1", "a", board.Move{Column: "1"})
+	b, _ := s.Move(ctx, "b1", "b", board.Move{Column: "1"})
+	a.Assignee = "alice"
+	_, _ = f.tasks.Update(ctx, a)
+
+	assigned := b
+	assigned.Assignee = "alice"
+	_, err := s.Save(ctx, b, assigned)
+	assigned.Assignee = "bob"
+	_, other := s.Save(ctx, b, assigned)
+
+	assert.EqualError(t, err, `swimlane "alice" of the column is at its WIP limit of 1`)
+	assert.NoError(t, other)
+}
+
+func TestService_View_Swimlanes_ShouldShowLanesOfColumns(t *testing.T) {
+	f := newFixture(t, "a", "b", "c")
+	s, _ := f.limit(t, func(b *board.Board) {
+		b.Swimlanes = &board.Swimlanes{
+			By:     board.SwimlanePriority,
+			Limits: map[string]board.WIPLimit{"high": {Max: 1, Mode: board.WIPSoft}},
+		}
+	})
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		_, _ = s.Move(ctx, "b1", id, board.Move{Column: "1"})
+	}
+
+	v, err := s.View(ctx, "b1")
+	require.NoError(t, err)
+
+	limit := board.WIPLimit{Max: 1, Mode: board.WIPSoft}
+	assert.Equal(t, []board.LaneView{
+		{Lane: "high", Count: 0, WIPLimit: &limit},
+		{Lane: "medium", Count: 3},
+	}, v.Columns[0].Lanes)
+}
+
+func TestBoard_Validate_InvalidLimits_ShouldReportFields(t *testing.T) {
+	f := newFixture(t)
+	b := f.board
+	b.Columns[0].WIPLimit = &board.WIPLimit{Max: 0, Mode: "strict"}
+	b.Swimlanes = &board.Swimlanes{
+		By:     board.SwimlanePriority,
+		Limits: map[string]board.WIPLimit{"urgent": {Max: 1, Mode: board.WIPHard}},
+	}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "columns.0.wip_limit.max", Message: "must be between 1 and 1000"},
+		{Field: "columns.0.wip_limit.mode", Message: "must be one of soft, hard"},
+		{Field: "swimlanes.limits.urgent", Message: "must be a lane of a known priority"},
+	}, b.Validate())
+}
+
+func TestLimitError_Problem_ShouldDescribeLimit(t *testing.T) {
+	err := &board.LimitError{Overload: board.Overload{
+		ColumnID: "1",
+		Swimlane: true,
+		Lane:     string(task.PriorityHigh),
+		Limit:    board.WIPLimit{Max: 3, Mode: board.WIPHard},
+	}}
+
+	d := err.Problem()
+
+	assert.Equal(t, 409, d.Status)
+	assert.Equal(t, map[string]any{"column": "1", "limit": 3, "lane": "high"}, d.Extensions)
+}
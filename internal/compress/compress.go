@@ -0,0 +1,127 @@
+package compress
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings supported by encoders and decoders.
+// Deflate is the zlib format as defined for HTTP content coding.
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+	Zstd    = "zstd"
+	Brotli  = "br"
+	// Identity means no encoding.
+	Identity = "identity"
+)
+
+// MaxDecoderMemory limits memory of a zstd decoder, frames of larger windows are rejected.
+const MaxDecoderMemory = 32 << 20
+
+// Encoder compresses data written to the underlying writer.
+type Encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type resettable interface {
+	Encoder
+	Reset(w io.Writer)
+}
+
+//nolint:gochecknoglobals // encoders are reused across requests.
+var pools = map[string]*sync.Pool{
+	Gzip:    {New: func() any { return gzip.NewWriter(nil) }},
+	Deflate: {New: func() any { return zlib.NewWriter(nil) }},
+	Zstd: {New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return enc
+	}},
+	Brotli: {New: func() any { return brotli.NewWriterLevel(nil, brotli.DefaultCompression) }},
+}
+
+// Supported reports whether the content coding is supported.
+func Supported(encoding string) bool {
+	_, ok := pools[encoding]
+	return ok
+}
+
+// NewEncoder takes a pooled encoder of the content coding writing to w.
+// Release function returns encoder to the pool and must be called once encoder is closed.
+func NewEncoder(encoding string, w io.Writer) (Encoder, func(), error) {
+	pool, ok := pools[encoding]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported content coding %q", encoding)
+	}
+	enc, _ := pool.Get().(resettable)
+	enc.Reset(w)
+	return enc, func() {
+		enc.Reset(nil)
+		pool.Put(enc)
+	}, nil
+}
+
+// NewDecoder creates a decoder of the content coding reading from r.
+// Returned reader must be closed to release decoder resources.
+// Zstd decoder memory is limited by MaxDecoderMemory.
+func NewDecoder(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Deflate:
+		return zlib.NewReader(r)
+	case Zstd:
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(MaxDecoderMemory))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case Brotli:
+		return io.NopCloser(brotli.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported content coding %q", encoding)
+	}
+}
+
+// Negotiate selects content coding of the response by Accept-Encoding header value.
+// Among accepted codings the one with the highest quality wins,
+// ties are resolved by the order of preferred codings.
+// Returns Identity if none of preferred codings is acceptable.
+func Negotiate(acceptEncoding string, preferred []string) string {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[name] = q
+	}
+
+	best, bestQ := Identity, 0.0
+	for _, enc := range preferred {
+		q, ok := accepted[enc]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,85 @@
+package compress_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/compress"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder_RoundTrip_ShouldRestoreData(t *testing.T) {
+	data := strings.Repeat("task board export ", 100)
+	for _, enc := range []string{compress.Gzip, compress.Deflate, compress.Zstd, compress.Brotli} {
+		t.Run(enc, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, release, err := compress.NewEncoder(enc, &buf)
+			require.NoError(t, err)
+			_, err = io.WriteString(w, data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			release()
+
+			r, err := compress.NewDecoder(enc, &buf)
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			assert.Less(t, buf.Cap(), len(data))
+			assert.Equal(t, data, string(got))
+		})
+	}
+}
+
+func TestNewEncoder_Unsupported_ShouldError(t *testing.T) {
+	_, _, err := compress.NewEncoder("lzma", io.Discard)
+
+	assert.Error(t, err)
+	assert.False(t, compress.Supported("lzma"))
+}
+
+func TestNewDecoder_Unsupported_ShouldError(t *testing.T) {
+	_, err := compress.NewDecoder("lzma", strings.NewReader(""))
+
+	assert.Error(t, err)
+}
+
+func TestNewDecoder_ZstdWindowOverMaxMemory_ShouldError(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf, zstd.WithWindowSize(2*compress.MaxDecoderMemory), zstd.WithSingleSegment(false))
+	require.NoError(t, err)
+	_, err = w.Write([]byte(strings.Repeat("task board export ", 100)))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Close())
+
+	r, err := compress.NewDecoder(compress.Zstd, &buf)
+	require.NoError(t, err)
+	defer r.Close()
+	_, err = io.ReadAll(r)
+
+	assert.Error(t, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	preferred := []string{compress.Zstd, compress.Brotli, compress.Gzip, compress.Deflate}
+	cases := map[string]string{
+		"":                        compress.Identity,
+		"identity":                compress.Identity,
+		"gzip, deflate, br":       compress.Brotli,
+		"gzip, zstd":              compress.Zstd,
+		"gzip;q=1, br;q=0.5":      compress.Gzip,
+		"BR;q=0.9, GZIP;q=0.9":    compress.Brotli,
+		"*":                       compress.Zstd,
+		"*;q=0.1, zstd;q=0, gzip": compress.Gzip,
+		"compress, lzma":          compress.Identity,
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, compress.Negotiate(header, preferred), header)
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/caarlos0/env/v11"
@@ -9,15 +12,216 @@ import (
 
 // AppConfig application runtime configuration.
 type AppConfig struct {
-	AppShutdownTimeout time.Duration `env:"APP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
-	HTTPPrimaryServer  ServerConfig  `envPrefix:"HTTP_"`
+	AppShutdownTimeout time.Duration      `env:"APP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	HTTPPrimaryServer  ServerConfig       `envPrefix:"HTTP_"`
+	Tracing            TracingConfig      `envPrefix:"TRACING_"`
+	Metrics            MetricsConfig      `envPrefix:"METRICS_"`
+	Log                LogConfig          `envPrefix:"LOG_"`
+	Admin              AdminConfig        `envPrefix:"ADMIN_"`
+	RateLimit          RateLimitConfig    `envPrefix:"RATE_LIMIT_"`
+	LoadShed           LoadShedConfig     `envPrefix:"LOAD_SHED_"`
+	Health             HealthConfig       `envPrefix:"HEALTH_"`
+	CORS               CORSConfig         `envPrefix:"CORS_"`
+	Security           SecurityConfig     `envPrefix:"SECURITY_"`
+	CSRF               CSRFConfig         `envPrefix:"CSRF_"`
+	Compression        CompressionConfig  `envPrefix:"COMPRESSION_"`
+	Idempotency        IdempotencyConfig  `envPrefix:"IDEMPOTENCY_"`
+	Maintenance        MaintenanceConfig  `envPrefix:"MAINTENANCE_"`
+	FeatureFlags       FeatureFlagsConfig `envPrefix:"FEATURE_FLAGS_"`
+	Identity           IdentityConfig     `envPrefix:"IDENTITY_"`
+	API                APIConfig          `envPrefix:"API_"`
 }
 
 // ServerConfig HTTP server config.
+// Route groups default, admin and import override MaxBodySize and HandlerTimeout,
+// e.g. HTTP_GROUP_MAX_BODY_SIZES="import=10485760", HTTP_GROUP_HANDLER_TIMEOUTS="admin=1m,import=30s".
 type ServerConfig struct {
-	Address           string        `env:"ADDRESS" envDefault:":8080"`
-	ReadTimeout       time.Duration `env:"READ_TIMEOUT" envDefault:"10s"`
-	ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT" envDefault:"10s"`
+	Address              string                   `env:"ADDRESS" envDefault:":8080"`
+	ReadTimeout          time.Duration            `env:"READ_TIMEOUT" envDefault:"10s"`
+	ReadHeaderTimeout    time.Duration            `env:"READ_HEADER_TIMEOUT" envDefault:"10s"`
+	WriteTimeout         time.Duration            `env:"WRITE_TIMEOUT" envDefault:"30s"`
+	HandlerTimeout       time.Duration            `env:"HANDLER_TIMEOUT" envDefault:"10s"`
+	MaxBodySize          int64                    `env:"MAX_BODY_SIZE" envDefault:"1048576"`
+	GroupHandlerTimeouts map[string]time.Duration `env:"GROUP_HANDLER_TIMEOUTS" envKeyValSeparator:"="`
+	GroupMaxBodySizes    map[string]int64         `env:"GROUP_MAX_BODY_SIZES" envKeyValSeparator:"="`
+	RequestID            RequestIDConfig          `envPrefix:"REQUEST_ID_"`
+}
+
+// RequestIDConfig request id exchange config.
+type RequestIDConfig struct {
+	Header    string `env:"HEADER" envDefault:"X-Request-ID"`
+	Generator string `env:"GENERATOR" envDefault:"uuidv4"`
+	Policy    string `env:"POLICY" envDefault:"uuid"`
+	MaxLength int    `env:"MAX_LENGTH" envDefault:"64"`
+	Node      uint16 `env:"NODE" envDefault:"0"`
+}
+
+// TracingConfig distributed tracing config.
+type TracingConfig struct {
+	ServiceName string  `env:"SERVICE_NAME" envDefault:"primary-server"`
+	Exporter    string  `env:"EXPORTER" envDefault:"none"`
+	Endpoint    string  `env:"ENDPOINT" envDefault:"localhost:4318"`
+	Insecure    bool    `env:"INSECURE" envDefault:"false"`
+	SampleRatio float64 `env:"SAMPLE_RATIO" envDefault:"1"`
+}
+
+// MetricsConfig Prometheus metrics config.
+type MetricsConfig struct {
+	Enabled   bool   `env:"ENABLED" envDefault:"true"`
+	Path      string `env:"PATH" envDefault:"/metrics"`
+	Namespace string `env:"NAMESPACE" envDefault:"team_agile"`
+}
+
+// LogConfig application logging config.
+// Package and route levels override the base level, e.g. LOG_ROUTE_LEVELS="/api/v1/tasks/{id}=debug".
+// Successful requests are logged with the sample rate, route rates override it the same way.
+type LogConfig struct {
+	Level         slog.Level        `env:"LEVEL" envDefault:"info"`
+	Format        string            `env:"FORMAT" envDefault:"json"`
+	PackageLevels map[string]string `env:"PACKAGE_LEVELS" envKeyValSeparator:"="`
+	RouteLevels   map[string]string `env:"ROUTE_LEVELS" envKeyValSeparator:"="`
+
+	SampleRate       float64            `env:"SAMPLE_RATE" envDefault:"1"`
+	SampleRouteRates map[string]float64 `env:"SAMPLE_ROUTE_RATES" envKeyValSeparator:"="`
+
+	Headers      bool     `env:"HEADERS" envDefault:"false"`
+	Query        bool     `env:"QUERY" envDefault:"false"`
+	RedactKeys   []string `env:"REDACT_KEYS" envDefault:"authorization,proxy-authorization,cookie,set-cookie,*token*,*secret*,*password*,*api-key*"`
+	RedactEmails bool     `env:"REDACT_EMAILS" envDefault:"true"`
+}
+
+// AdminConfig administrative API config.
+// Administrative API is disabled while token is empty.
+// Diagnostics expose pprof, runtime stats and dumps under /admin/debug.
+// Heap snapshots are written into SnapshotDir, empty means the temporary directory.
+type AdminConfig struct {
+	Token       string `env:"TOKEN"`
+	Diagnostics bool   `env:"DIAGNOSTICS" envDefault:"true"`
+	SnapshotDir string `env:"SNAPSHOT_DIR"`
+}
+
+// RateLimitConfig per client rate limiting config.
+// Groups map route group names to "<limit>/<window>" rules, e.g. RATE_LIMIT_GROUPS="default=100/1m,admin=10/1s".
+// Route groups without a rule are not limited.
+// Key is ip by default, so unauthenticated requests could not spread over buckets of made-up credentials.
+type RateLimitConfig struct {
+	Algorithm string            `env:"ALGORITHM" envDefault:"token-bucket"`
+	Key       string            `env:"KEY" envDefault:"ip"`
+	Groups    map[string]string `env:"GROUPS" envKeyValSeparator:"="`
+}
+
+// LoadShedConfig adaptive concurrency limiting config.
+// Groups map route group names to maximal number of in-flight requests, e.g. LOAD_SHED_GROUPS="default=256".
+// Route groups without a limit are not shed.
+type LoadShedConfig struct {
+	Groups        map[string]int `env:"GROUPS" envKeyValSeparator:"="`
+	MinLimit      int            `env:"MIN_LIMIT" envDefault:"4"`
+	LatencyTarget time.Duration  `env:"LATENCY_TARGET" envDefault:"250ms"`
+	MaxWait       time.Duration  `env:"MAX_WAIT" envDefault:"100ms"`
+	MaxQueue      int            `env:"MAX_QUEUE" envDefault:"100"`
+	RetryAfter    time.Duration  `env:"RETRY_AFTER" envDefault:"1s"`
+}
+
+// HealthConfig liveness and readiness checks config.
+// DrainDelay is a time between readiness starts failing and resources are released on shutdown,
+// it lets load balancers notice failing readiness and stop routing new requests first.
+type HealthConfig struct {
+	CheckTimeout time.Duration `env:"CHECK_TIMEOUT" envDefault:"1s"`
+	CacheTTL     time.Duration `env:"CACHE_TTL" envDefault:"5s"`
+	DrainDelay   time.Duration `env:"DRAIN_DELAY" envDefault:"5s"`
+	DiskPath     string        `env:"DISK_PATH" envDefault:"."`
+	DiskMinFree  uint64        `env:"DISK_MIN_FREE" envDefault:"104857600"`
+}
+
+// CORSConfig cross-origin resource sharing config.
+// CORS is disabled while allowed origins are empty.
+// Credentials could not be allowed for "*" origins.
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"ALLOWED_ORIGINS"`
+	AllowedMethods   []string      `env:"ALLOWED_METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   []string      `env:"ALLOWED_HEADERS" envDefault:"Authorization,Content-Type,If-Match,If-None-Match,Idempotency-Key,X-Request-ID,X-CSRF-Token"`
+	ExposedHeaders   []string      `env:"EXPOSED_HEADERS" envDefault:"ETag,Location,Retry-After,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,X-Request-ID"`
+	AllowCredentials bool          `env:"ALLOW_CREDENTIALS" envDefault:"false"`
+	MaxAge           time.Duration `env:"MAX_AGE" envDefault:"10m"`
+}
+
+// SecurityConfig security response headers config.
+// Empty values omit corresponding headers.
+type SecurityConfig struct {
+	HSTSMaxAge            time.Duration `env:"HSTS_MAX_AGE" envDefault:"8760h"`
+	HSTSIncludeSubdomains bool          `env:"HSTS_INCLUDE_SUBDOMAINS" envDefault:"true"`
+	HSTSPreload           bool          `env:"HSTS_PRELOAD" envDefault:"false"`
+	ContentSecurityPolicy string        `env:"CONTENT_SECURITY_POLICY" envDefault:"default-src 'none'; frame-ancestors 'none'"`
+	ReferrerPolicy        string        `env:"REFERRER_POLICY" envDefault:"no-referrer"`
+	FrameOptions          string        `env:"FRAME_OPTIONS" envDefault:"DENY"`
+}
+
+// CSRFConfig double-submit cookie CSRF protection config.
+// Only requests carrying the session cookie are protected.
+type CSRFConfig struct {
+	Enabled       bool   `env:"ENABLED" envDefault:"true"`
+	CookieName    string `env:"COOKIE_NAME" envDefault:"csrf_token"`
+	HeaderName    string `env:"HEADER_NAME" envDefault:"X-CSRF-Token"`
+	SessionCookie string `env:"SESSION_COOKIE" envDefault:"session"`
+	Secure        bool   `env:"SECURE" envDefault:"true"`
+}
+
+// CompressionConfig response compression and request decompression config.
+// Encodings are listed in the order of server preference.
+// RequestMaxSize limits decompressed size of encoded request bodies.
+type CompressionConfig struct {
+	Enabled        bool     `env:"ENABLED" envDefault:"true"`
+	Encodings      []string `env:"ENCODINGS" envDefault:"zstd,br,gzip,deflate"`
+	MinSize        int      `env:"MIN_SIZE" envDefault:"1024"`
+	ContentTypes   []string `env:"CONTENT_TYPES" envDefault:"application/json,application/problem+json,application/x-ndjson,application/xml,image/svg+xml,text/*"`
+	RequestMaxSize int64    `env:"REQUEST_MAX_SIZE" envDefault:"10485760"`
+}
+
+// IdempotencyConfig Idempotency-Key support config.
+// TTL is a time responses are kept for replay.
+type IdempotencyConfig struct {
+	Enabled bool          `env:"ENABLED" envDefault:"true"`
+	TTL     time.Duration `env:"TTL" envDefault:"24h"`
+}
+
+// MaintenanceConfig maintenance and read-only modes config.
+// Mode is one of "off", "read-only" or "maintenance", applied on start and config reload.
+type MaintenanceConfig struct {
+	Mode       string        `env:"MODE" envDefault:"off"`
+	Message    string        `env:"MESSAGE"`
+	RetryAfter time.Duration `env:"RETRY_AFTER" envDefault:"60s"`
+}
+
+// IdentityConfig identity of callers authenticated by the gateway in front of the server.
+// Identity headers are trusted only if enabled, the gateway must replace them in forwarded requests.
+type IdentityConfig struct {
+	Headers      bool   `env:"HEADERS" envDefault:"false"`
+	UserHeader   string `env:"USER_HEADER" envDefault:"X-User-ID"`
+	TeamHeader   string `env:"TEAM_HEADER" envDefault:"X-Team-ID"`
+	TenantHeader string `env:"TENANT_HEADER" envDefault:"X-Tenant-ID"`
+}
+
+// FeatureFlagsConfig feature flags config.
+// File is a JSON array of flags with targeting rules,
+// Toggles enable or disable flags over the file, e.g. FEATURE_FLAGS_TOGGLES="swimlanes=true,analytics=false".
+type FeatureFlagsConfig struct {
+	File    string          `env:"FILE"`
+	Toggles map[string]bool `env:"TOGGLES" envKeyValSeparator:"="`
+}
+
+// APIConfig versioned API config.
+// Deprecated and Sunset map versions to dates, e.g. API_DEPRECATED="v1=2024-01-01",
+// DeprecationLink points to the deprecation notice of all versions.
+// RequireIfMatch rejects changes of entities without If-Match header.
+// WorkflowFile is a JSON or YAML workflow of new boards and tasks off boards, the built-in workflow by default.
+type APIConfig struct {
+	Vendor          string            `env:"VENDOR" envDefault:"team-agile"`
+	DefaultVersion  string            `env:"DEFAULT_VERSION" envDefault:"v1"`
+	Deprecated      map[string]string `env:"DEPRECATED" envKeyValSeparator:"="`
+	Sunset          map[string]string `env:"SUNSET" envKeyValSeparator:"="`
+	DeprecationLink string            `env:"DEPRECATION_LINK"`
+	RequireIfMatch  bool              `env:"REQUIRE_IF_MATCH" envDefault:"false"`
+	WorkflowFile    string            `env:"WORKFLOW_FILE"`
 }
 
 // Origin default value will never break builder.
@@ -26,6 +230,16 @@ type Origin func(*AppConfig) error
 // MustRead reads application config from a set of origins in presented order.
 // On field collisions it will use the latest value.
 func MustRead(origins ...Origin) AppConfig {
+	cfg, err := Read(origins...)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// Read reads application config from a set of origins in presented order.
+// On field collisions it will use the latest value.
+func Read(origins ...Origin) (AppConfig, error) {
 	cfg := AppConfig{}
 	for _, opt := range origins {
 		if opt == nil {
@@ -33,10 +247,13 @@ func MustRead(origins ...Origin) AppConfig {
 		}
 		err := opt(&cfg)
 		if err != nil {
-			panic(err)
+			return AppConfig{}, err
 		}
 	}
-	return cfg
+	if cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		return AppConfig{}, errors.New(`CORS_ALLOW_CREDENTIALS must not be set with "*" in CORS_ALLOWED_ORIGINS`)
+	}
+	return cfg, nil
 }
 
 // FromEnv reads values from an env variables.
@@ -54,3 +271,20 @@ func FromEnv(path string) Origin {
 		return env.Parse(cfg)
 	}
 }
+
+// ReloadFromEnv reads values from an env variables like FromEnv,
+// but values of the file override already set variables to pick up file changes on reload.
+func ReloadFromEnv(path string) Origin {
+	return func(cfg *AppConfig) error {
+		if path != "" {
+			err := godotenv.Overload(path)
+			if err != nil {
+				return err
+			}
+		}
+		if cfg == nil {
+			return nil
+		}
+		return env.Parse(cfg)
+	}
+}
@@ -3,12 +3,15 @@ package config_test
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/asmazovec/team-agile/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMustRead_NilOption_ShouldNotPanic(t *testing.T) {
@@ -108,3 +111,214 @@ func TestFromConfig_EnvConfig_ShouldSetEnvVariables(t *testing.T) {
 
 	assert.Equal(t, val, os.Getenv("HTTP_ADDRESS"))
 }
+
+func TestFromConfig_RequestID_ShouldDefault(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "X-Request-ID", cfg.HTTPPrimaryServer.RequestID.Header)
+	assert.Equal(t, "uuidv4", cfg.HTTPPrimaryServer.RequestID.Generator)
+	assert.Equal(t, "uuid", cfg.HTTPPrimaryServer.RequestID.Policy)
+}
+
+func TestFromConfig_GroupLimits_ShouldParseOverrides(t *testing.T) {
+	t.Setenv("HTTP_GROUP_MAX_BODY_SIZES", "import=10485760")
+	t.Setenv("HTTP_GROUP_HANDLER_TIMEOUTS", "admin=1m,import=30s")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, map[string]int64{"import": 10 << 20}, cfg.HTTPPrimaryServer.GroupMaxBodySizes)
+	assert.Equal(t, map[string]time.Duration{"admin": time.Minute, "import": 30 * time.Second},
+		cfg.HTTPPrimaryServer.GroupHandlerTimeouts)
+}
+
+func TestFromConfig_Tracing_ShouldDefault(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "none", cfg.Tracing.Exporter)
+	assert.Equal(t, "primary-server", cfg.Tracing.ServiceName)
+	assert.InDelta(t, 1.0, cfg.Tracing.SampleRatio, 0)
+}
+
+func TestFromConfig_Metrics_ShouldDefault(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.True(t, cfg.Metrics.Enabled)
+	assert.Equal(t, "/metrics", cfg.Metrics.Path)
+}
+
+func TestFromConfig_Log_ShouldParse(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_ROUTE_LEVELS", "/tasks/{id}=warn,/=error")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, slog.LevelDebug, cfg.Log.Level)
+	assert.Equal(t, "json", cfg.Log.Format)
+	assert.Equal(t, map[string]string{"/tasks/{id}": "warn", "/": "error"}, cfg.Log.RouteLevels)
+}
+
+func TestFromConfig_LogSampling_ShouldParse(t *testing.T) {
+	t.Setenv("LOG_SAMPLE_ROUTE_RATES", "/livez=0.01")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.InDelta(t, 1.0, cfg.Log.SampleRate, 0)
+	assert.Equal(t, map[string]float64{"/livez": 0.01}, cfg.Log.SampleRouteRates)
+	assert.Contains(t, cfg.Log.RedactKeys, "authorization")
+}
+
+func TestFromConfig_RateLimit_ShouldParseGroups(t *testing.T) {
+	t.Setenv("RATE_LIMIT_GROUPS", "default=100/1m,admin=10/1s")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "token-bucket", cfg.RateLimit.Algorithm)
+	assert.Equal(t, "ip", cfg.RateLimit.Key)
+	assert.Equal(t, map[string]string{"default": "100/1m", "admin": "10/1s"}, cfg.RateLimit.Groups)
+}
+
+func TestFromConfig_LoadShed_ShouldParseGroups(t *testing.T) {
+	t.Setenv("LOAD_SHED_GROUPS", "default=256,admin=8")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, map[string]int{"default": 256, "admin": 8}, cfg.LoadShed.Groups)
+	assert.Equal(t, 4, cfg.LoadShed.MinLimit)
+	assert.Equal(t, 250*time.Millisecond, cfg.LoadShed.LatencyTarget)
+	assert.Equal(t, 100*time.Millisecond, cfg.LoadShed.MaxWait)
+	assert.Equal(t, 100, cfg.LoadShed.MaxQueue)
+	assert.Equal(t, time.Second, cfg.LoadShed.RetryAfter)
+}
+
+func TestFromConfig_Health_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, time.Second, cfg.Health.CheckTimeout)
+	assert.Equal(t, 5*time.Second, cfg.Health.CacheTTL)
+	assert.Equal(t, 5*time.Second, cfg.Health.DrainDelay)
+	assert.Equal(t, ".", cfg.Health.DiskPath)
+	assert.Equal(t, uint64(100<<20), cfg.Health.DiskMinFree)
+}
+
+func TestFromConfig_CORS_ShouldParseOrigins(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://board.example.com,https://*.agile.dev")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, []string{"https://board.example.com", "https://*.agile.dev"}, cfg.CORS.AllowedOrigins)
+	assert.Contains(t, cfg.CORS.AllowedMethods, "PATCH")
+	assert.Equal(t, 10*time.Minute, cfg.CORS.MaxAge)
+}
+
+func TestFromConfig_CORS_CredentialsAnyOrigin_ShouldError(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://board.example.com,*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	_, err := config.Read(config.FromEnv(""))
+
+	assert.ErrorContains(t, err, "CORS_ALLOW_CREDENTIALS")
+}
+
+func TestFromConfig_Security_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, 365*24*time.Hour, cfg.Security.HSTSMaxAge)
+	assert.Equal(t, "DENY", cfg.Security.FrameOptions)
+	assert.True(t, cfg.CSRF.Enabled)
+	assert.Equal(t, "session", cfg.CSRF.SessionCookie)
+}
+
+func TestFromConfig_Compression_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.True(t, cfg.Compression.Enabled)
+	assert.Equal(t, []string{"zstd", "br", "gzip", "deflate"}, cfg.Compression.Encodings)
+	assert.Equal(t, 1024, cfg.Compression.MinSize)
+	assert.Contains(t, cfg.Compression.ContentTypes, "text/*")
+	assert.Equal(t, int64(10<<20), cfg.Compression.RequestMaxSize)
+}
+
+func TestFromConfig_ServerLimits_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, 30*time.Second, cfg.HTTPPrimaryServer.WriteTimeout)
+	assert.Equal(t, 10*time.Second, cfg.HTTPPrimaryServer.HandlerTimeout)
+	assert.Equal(t, int64(1<<20), cfg.HTTPPrimaryServer.MaxBodySize)
+}
+
+func TestFromConfig_Idempotency_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.True(t, cfg.Idempotency.Enabled)
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.TTL)
+}
+
+func TestFromConfig_Admin_ShouldEnableDiagnostics(t *testing.T) {
+	t.Setenv("ADMIN_SNAPSHOT_DIR", "/var/lib/snapshots")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.True(t, cfg.Admin.Diagnostics)
+	assert.Equal(t, "/var/lib/snapshots", cfg.Admin.SnapshotDir)
+}
+
+func TestRead_OriginWithErr_ShouldError(t *testing.T) {
+	m := OriginMock{}
+
+	_, err := config.Read(m.WithAddress("address"), m.WithError(errors.New("error")))
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestReloadFromEnv_ChangedFile_ShouldOverrideVariables(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".env")
+	t.Setenv("MAINTENANCE_MODE", "off")
+	createEnvConfig(file, "MAINTENANCE_MODE", "read-only")
+
+	cfg, err := config.Read(config.ReloadFromEnv(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, "read-only", cfg.Maintenance.Mode)
+	assert.Equal(t, time.Minute, cfg.Maintenance.RetryAfter)
+}
+
+func TestFromConfig_FeatureFlags_ShouldParseToggles(t *testing.T) {
+	t.Setenv("FEATURE_FLAGS_FILE", "flags.json")
+	t.Setenv("FEATURE_FLAGS_TOGGLES", "swimlanes=true,analytics=false")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "flags.json", cfg.FeatureFlags.File)
+	assert.Equal(t, map[string]bool{"swimlanes": true, "analytics": false}, cfg.FeatureFlags.Toggles)
+}
+
+func TestFromConfig_Identity_ShouldUseDefaults(t *testing.T) {
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.False(t, cfg.Identity.Headers)
+	assert.Equal(t, "X-User-ID", cfg.Identity.UserHeader)
+	assert.Equal(t, "X-Team-ID", cfg.Identity.TeamHeader)
+	assert.Equal(t, "X-Tenant-ID", cfg.Identity.TenantHeader)
+}
+
+func TestFromConfig_API_ShouldParseDeprecations(t *testing.T) {
+	t.Setenv("API_DEPRECATED", "v1=2024-01-01")
+	t.Setenv("API_SUNSET", "v1=2024-07-01")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "team-agile", cfg.API.Vendor)
+	assert.Equal(t, "v1", cfg.API.DefaultVersion)
+	assert.Equal(t, map[string]string{"v1": "2024-01-01"}, cfg.API.Deprecated)
+	assert.Equal(t, map[string]string{"v1": "2024-07-01"}, cfg.API.Sunset)
+	assert.False(t, cfg.API.RequireIfMatch)
+	assert.Empty(t, cfg.API.WorkflowFile)
+}
+
+func TestFromConfig_API_ShouldReadWorkflowFile(t *testing.T) {
+	t.Setenv("API_WORKFLOW_FILE", "workflow.yaml")
+
+	cfg := config.MustRead(config.FromEnv(""))
+
+	assert.Equal(t, "workflow.yaml", cfg.API.WorkflowFile)
+}
@@ -0,0 +1,173 @@
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// FieldError describes invalid value of a request field.
+// Field is a dotted path of JSON names, e.g. "assignee.id".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a request decoding error.
+// Fields are set for unknown fields, wrong types and failed validation.
+type Error struct {
+	Status int
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validator is implemented by request payloads validating decoded values.
+// Returns no field errors if the payload is valid.
+type Validator interface {
+	Validate() []FieldError
+}
+
+// JSON strictly decodes JSON request body into v.
+// Body must be a single JSON value without unknown fields matching types of v.
+// If v implements Validator, decoded value is validated.
+// Returned *Error describes response status: 400 for malformed body,
+// 413 for body exceeding the limit, 415 for non JSON content type
+// and 422 for field errors.
+func JSON(r *http.Request, v any) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return &Error{Status: http.StatusUnsupportedMediaType, Detail: "content type must be application/json", Err: err}
+		}
+	}
+	if r.Body == nil {
+		return &Error{Status: http.StatusBadRequest, Detail: "request body is empty"}
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return decodeError(err)
+		}
+		return &Error{Status: http.StatusBadRequest, Detail: "request body must contain a single JSON value"}
+	}
+
+	if val, ok := v.(Validator); ok {
+		if fields := val.Validate(); len(fields) > 0 {
+			return &Error{Status: http.StatusUnprocessableEntity, Detail: "request body is invalid", Fields: fields}
+		}
+	}
+	return nil
+}
+
+func decodeError(err error) *Error {
+	var (
+		maxErr    *http.MaxBytesError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return &Error{Status: http.StatusBadRequest, Detail: "request body is empty", Err: err}
+	case errors.As(err, &maxErr):
+		return &Error{
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: "request body exceeds " + strconv.FormatInt(maxErr.Limit, 10) + " bytes",
+			Err:    err,
+		}
+	case errors.As(err, &syntaxErr):
+		return &Error{
+			Status: http.StatusBadRequest,
+			Detail: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset),
+			Err:    err,
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &Error{Status: http.StatusBadRequest, Detail: "malformed JSON: unexpected end of body", Err: err}
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			return &Error{Status: http.StatusBadRequest, Detail: "request body must be JSON " + jsonType(typeErr.Type), Err: err}
+		}
+		return &Error{
+			Status: http.StatusUnprocessableEntity,
+			Detail: "request body is invalid",
+			Fields: []FieldError{{Field: field, Message: "must be " + jsonType(typeErr.Type)}},
+			Err:    err,
+		}
+	}
+
+	// Unknown field error has no dedicated type.
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field, _ := strconv.Unquote(name)
+		return &Error{
+			Status: http.StatusUnprocessableEntity,
+			Detail: "request body is invalid",
+			Fields: []FieldError{{Field: field, Message: "unknown field"}},
+			Err:    err,
+		}
+	}
+	return &Error{Status: http.StatusBadRequest, Detail: "malformed JSON: " + err.Error(), Err: err}
+}
+
+// jsonType names JSON type of the Go type.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() { //nolint:exhaustive // rest kinds are not decoded from JSON
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+// Problem converts the decoding error to problem details.
+// Field errors are presented in the "errors" extension member.
+func Problem(err error) *problem.Details {
+	var e *Error
+	if !errors.As(err, &e) {
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+	p := problem.New(e.Status, e.Detail)
+	if len(e.Fields) > 0 {
+		p.With("errors", e.Fields)
+	}
+	return p
+}
@@ -0,0 +1,130 @@
+package decode_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type assignee struct {
+	ID string `json:"id"`
+}
+
+type payload struct {
+	Title    string    `json:"title"`
+	Estimate int       `json:"estimate"`
+	Labels   []string  `json:"labels"`
+	Assignee *assignee `json:"assignee"`
+}
+
+func (p payload) Validate() []decode.FieldError {
+	if p.Title == "" {
+		return []decode.FieldError{{Field: "title", Message: "must not be empty"}}
+	}
+	return nil
+}
+
+func request(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func requireDecodeError(t *testing.T, err error) *decode.Error {
+	t.Helper()
+	var e *decode.Error
+	require.ErrorAs(t, err, &e)
+	return e
+}
+
+func TestJSON_ValidBody_ShouldDecode(t *testing.T) {
+	var p payload
+
+	err := decode.JSON(request(`{"title":"Fix login","estimate":3,"labels":["bug"],"assignee":{"id":"u1"}}`), &p)
+
+	require.NoError(t, err)
+	assert.Equal(t, payload{Title: "Fix login", Estimate: 3, Labels: []string{"bug"}, Assignee: &assignee{ID: "u1"}}, p)
+}
+
+func TestJSON_UnknownField_ShouldFieldError(t *testing.T) {
+	err := decode.JSON(request(`{"title":"Fix login","priority":"high"}`), &payload{})
+
+	e := requireDecodeError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.Status)
+	assert.Equal(t, []decode.FieldError{{Field: "priority", Message: "unknown field"}}, e.Fields)
+}
+
+func TestJSON_WrongType_ShouldFieldError(t *testing.T) {
+	cases := map[string]decode.FieldError{
+		`{"title":1}`:               {Field: "title", Message: "must be string"},
+		`{"estimate":"3"}`:          {Field: "estimate", Message: "must be integer"},
+		`{"labels":"bug"}`:          {Field: "labels", Message: "must be array"},
+		`{"assignee":{"id":false}}`: {Field: "assignee.id", Message: "must be string"},
+		`{"assignee":["u1"]}`:       {Field: "assignee", Message: "must be object"},
+	}
+	for body, want := range cases {
+		err := decode.JSON(request(body), &payload{})
+
+		e := requireDecodeError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, e.Status, body)
+		assert.Equal(t, []decode.FieldError{want}, e.Fields, body)
+	}
+}
+
+func TestJSON_MalformedBody_ShouldBadRequest(t *testing.T) {
+	cases := map[string]string{
+		``:                           "request body is empty",
+		`{"title":`:                  "malformed JSON: unexpected end of body",
+		`{"title" "x"}`:              "malformed JSON at offset 10",
+		`{"title":"a"}{"title":"b"}`: "request body must contain a single JSON value",
+		`{"title":"a"} trailing`:     "request body must contain a single JSON value",
+		`["title"]`:                  "request body must be JSON object",
+	}
+	for body, detail := range cases {
+		err := decode.JSON(request(body), &payload{})
+
+		e := requireDecodeError(t, err)
+		assert.Equal(t, http.StatusBadRequest, e.Status, body)
+		assert.Equal(t, detail, e.Detail, body)
+	}
+}
+
+func TestJSON_TooLargeBody_ShouldContentTooLarge(t *testing.T) {
+	req := request(`{"title":"` + strings.Repeat("x", 100) + `"}`)
+	req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 16)
+
+	err := decode.JSON(req, &payload{})
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, requireDecodeError(t, err).Status)
+}
+
+func TestJSON_WrongContentType_ShouldUnsupportedMediaType(t *testing.T) {
+	req := request(`{"title":"Fix login"}`)
+	req.Header.Set("Content-Type", "text/plain")
+
+	err := decode.JSON(req, &payload{})
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, requireDecodeError(t, err).Status)
+}
+
+func TestJSON_InvalidPayload_ShouldValidate(t *testing.T) {
+	err := decode.JSON(request(`{"estimate":3}`), &payload{})
+
+	e := requireDecodeError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.Status)
+	assert.Equal(t, []decode.FieldError{{Field: "title", Message: "must not be empty"}}, e.Fields)
+}
+
+func TestProblem_ShouldListFieldErrors(t *testing.T) {
+	err := decode.JSON(request(`{"title":1}`), &payload{})
+
+	p := decode.Problem(err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, []decode.FieldError{{Field: "title", Message: "must be string"}}, p.Extensions["errors"])
+}
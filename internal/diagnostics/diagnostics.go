@@ -0,0 +1,85 @@
+package diagnostics
+
+import (
+	"expvar"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RuntimeStats is a snapshot of the process runtime state.
+type RuntimeStats struct {
+	GoVersion    string        `json:"go_version"`
+	Version      string        `json:"version"`
+	Uptime       time.Duration `json:"uptime_ns"`
+	Goroutines   int           `json:"goroutines"`
+	GOMAXPROCS   int           `json:"gomaxprocs"`
+	NumCPU       int           `json:"num_cpu"`
+	HeapAlloc    uint64        `json:"heap_alloc_bytes"`
+	HeapInuse    uint64        `json:"heap_inuse_bytes"`
+	HeapObjects  uint64        `json:"heap_objects"`
+	StackInuse   uint64        `json:"stack_inuse_bytes"`
+	Sys          uint64        `json:"sys_bytes"`
+	NumGC        uint32        `json:"num_gc"`
+	PauseTotal   time.Duration `json:"gc_pause_total_ns"`
+	LastGC       time.Time     `json:"last_gc"`
+	NextGCTarget uint64        `json:"next_gc_bytes"`
+}
+
+// ReadRuntimeStats collects runtime stats of the process started at the moment.
+// Reading memory stats briefly stops the world, so it should not be called on hot paths.
+func ReadRuntimeStats(started time.Time) RuntimeStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+	}
+	var lastGC time.Time
+	if ms.LastGC > 0 {
+		lastGC = time.Unix(0, int64(ms.LastGC)) //nolint:gosec // nanoseconds since epoch fit into int64
+	}
+	return RuntimeStats{
+		GoVersion:    runtime.Version(),
+		Version:      version,
+		Uptime:       time.Since(started),
+		Goroutines:   runtime.NumGoroutine(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumCPU:       runtime.NumCPU(),
+		HeapAlloc:    ms.HeapAlloc,
+		HeapInuse:    ms.HeapInuse,
+		HeapObjects:  ms.HeapObjects,
+		StackInuse:   ms.StackInuse,
+		Sys:          ms.Sys,
+		NumGC:        ms.NumGC,
+		PauseTotal:   time.Duration(ms.PauseTotalNs), //nolint:gosec // total pause never overflows int64
+		LastGC:       lastGC,
+		NextGCTarget: ms.NextGC,
+	}
+}
+
+//nolint:gochecknoglobals // expvar registry is global, stats are published once per process.
+var publishOnce sync.Once
+
+// PublishRuntimeStats publishes runtime stats as "runtime" expvar variable
+// served along with memstats and cmdline by expvar.Handler.
+// Repeated calls are no-op.
+func PublishRuntimeStats(started time.Time) {
+	publishOnce.Do(func() {
+		expvar.Publish("runtime", expvar.Func(func() any { return ReadRuntimeStats(started) }))
+	})
+}
+
+// GoroutineDump returns stack traces of all goroutines.
+func GoroutineDump() []byte {
+	const initialSize = 64 << 10
+	buf := make([]byte, initialSize)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,38 @@
+package diagnostics_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/diagnostics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRuntimeStats_ShouldCollectStats(t *testing.T) {
+	stats := diagnostics.ReadRuntimeStats(time.Now().Add(-time.Minute))
+
+	assert.GreaterOrEqual(t, stats.Uptime, time.Minute)
+	assert.Positive(t, stats.Goroutines)
+	assert.Positive(t, stats.HeapAlloc)
+	assert.NotEmpty(t, stats.GoVersion)
+}
+
+func TestPublishRuntimeStats_ShouldPublishOnce(t *testing.T) {
+	diagnostics.PublishRuntimeStats(time.Now())
+	diagnostics.PublishRuntimeStats(time.Now())
+
+	v := expvar.Get("runtime")
+	require.NotNil(t, v)
+	var stats diagnostics.RuntimeStats
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &stats))
+	assert.Positive(t, stats.Goroutines)
+}
+
+func TestGoroutineDump_ShouldContainCurrentGoroutine(t *testing.T) {
+	dump := diagnostics.GoroutineDump()
+
+	assert.Contains(t, string(dump), "TestGoroutineDump_ShouldContainCurrentGoroutine")
+}
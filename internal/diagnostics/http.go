@@ -0,0 +1,125 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Router routes runtime diagnostics:
+// net/http/pprof under /pprof, expvar variables under /vars, runtime stats under /stats,
+// goroutine dump under /goroutines, goroutine dump to the log with POST /goroutines/log
+// and heap snapshot into the directory with POST /heap/snapshot.
+// Router must be protected, profiles expose internals of the process.
+// Profiles and traces may last longer than the write timeout of the server, see ExtendWriteDeadline.
+func Router(l *slog.Logger, started time.Time, snapshotDir string) http.Handler {
+	PublishRuntimeStats(started)
+
+	r := chi.NewRouter()
+	r.Use(middleware.NoCache)
+	r.Use(ExtendWriteDeadline)
+	r.Mount("/", middleware.Profiler())
+	r.Get("/stats", StatsHandler(started))
+	r.Get("/goroutines", GoroutinesHandler)
+	r.Post("/goroutines/log", LogGoroutinesHandler(l))
+	r.Post("/heap/snapshot", HeapSnapshotHandler(snapshotDir))
+	return r
+}
+
+// defaultSeconds is the duration of profiles and traces requested without seconds, as of net/http/pprof.
+const defaultSeconds = 30
+
+// ExtendWriteDeadline middleware extends the write deadline of the request by the requested seconds
+// over the write timeout of the server. The write timeout is hidden from net/http/pprof,
+// as it rejects profiles longer than the timeout with 400 Bad Request.
+func ExtendWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv, ok := r.Context().Value(http.ServerContextKey).(*http.Server)
+		if !ok || srv.WriteTimeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		seconds, err := strconv.ParseFloat(r.FormValue("seconds"), 64)
+		if err != nil || seconds <= 0 {
+			seconds = defaultSeconds
+		}
+		timeout := srv.WriteTimeout + time.Duration(seconds*float64(time.Second))
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), http.ServerContextKey, &http.Server{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// StatsHandler serves runtime stats of the process in JSON.
+func StatsHandler(started time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, ReadRuntimeStats(started))
+	}
+}
+
+// GoroutinesHandler serves stack traces of all goroutines in plain text.
+func GoroutinesHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(GoroutineDump())
+}
+
+// LogGoroutinesHandler dumps stack traces of all goroutines to the log, like SIGQUIT does to stderr,
+// and responds with 204 No Content.
+func LogGoroutinesHandler(l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dump := GoroutineDump()
+		l.WarnContext(r.Context(), "Goroutine dump",
+			slog.Int("goroutines", runtime.NumGoroutine()),
+			slog.String("stacks", string(dump)),
+		)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// heapSnapshot describes heap profile written by HeapSnapshotHandler.
+type heapSnapshot struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+// HeapSnapshotHandler runs garbage collection and writes heap profile into a new file of the directory.
+// Responds with 201 Created and the path of the profile to be inspected with "go tool pprof".
+// Empty directory means os.TempDir.
+func HeapSnapshotHandler(dir string) http.HandlerFunc {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		runtime.GC()
+		var buf bytes.Buffer
+		if err := pprof.Lookup("heap").WriteTo(&buf, 0); err != nil {
+			problem.Write(w, r, problem.New(http.StatusInternalServerError, "heap profile: "+err.Error()))
+			return
+		}
+
+		name := fmt.Sprintf("heap-%s.pprof", time.Now().UTC().Format("20060102T150405.000000000"))
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil { //nolint:mnd // owner only
+			problem.Write(w, r, problem.New(http.StatusInternalServerError, "heap snapshot: "+err.Error()))
+			return
+		}
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, heapSnapshot{Path: path, Size: buf.Len()})
+	}
+}
@@ -0,0 +1,110 @@
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/diagnostics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveDiagnostics(t *testing.T, l *slog.Logger, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	h := diagnostics.Router(l, time.Now(), t.TempDir())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestRouter_Pprof_ShouldServeProfiles(t *testing.T) {
+	rec := serveDiagnostics(t, slog.Default(), http.MethodGet, "/pprof/heap")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Body.Bytes())
+}
+
+func TestExtendWriteDeadline_ShouldWriteAfterServerTimeout(t *testing.T) {
+	var timeout time.Duration
+	srv := httptest.NewUnstartedServer(diagnostics.ExtendWriteDeadline(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout = r.Context().Value(http.ServerContextKey).(*http.Server).WriteTimeout
+			time.Sleep(300 * time.Millisecond)
+			_, _ = w.Write([]byte("profile"))
+		}),
+	))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/pprof/profile?seconds=1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "profile", string(body))
+	assert.Zero(t, timeout)
+}
+
+func TestRouter_Vars_ShouldServeRuntimeStats(t *testing.T) {
+	rec := serveDiagnostics(t, slog.Default(), http.MethodGet, "/vars")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"runtime"`)
+	assert.Contains(t, rec.Body.String(), `"memstats"`)
+}
+
+func TestRouter_Stats_ShouldServeJSON(t *testing.T) {
+	rec := serveDiagnostics(t, slog.Default(), http.MethodGet, "/stats")
+
+	var stats diagnostics.RuntimeStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Positive(t, stats.Goroutines)
+}
+
+func TestRouter_Goroutines_ShouldServeDump(t *testing.T) {
+	rec := serveDiagnostics(t, slog.Default(), http.MethodGet, "/goroutines")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine ")
+}
+
+func TestRouter_LogGoroutines_ShouldDumpToLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	rec := serveDiagnostics(t, l, http.MethodPost, "/goroutines/log")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "Goroutine dump", record["msg"])
+	assert.Contains(t, record["stacks"], "goroutine ")
+}
+
+func TestHeapSnapshotHandler_ShouldWriteProfile(t *testing.T) {
+	dir := t.TempDir()
+	rec := httptest.NewRecorder()
+
+	diagnostics.HeapSnapshotHandler(dir)(rec, httptest.NewRequest(http.MethodPost, "/heap/snapshot", nil))
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var snapshot struct {
+		Path string `json:"path"`
+		Size int    `json:"size"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	assert.Equal(t, dir, filepath.Dir(snapshot.Path))
+	info, err := os.Stat(snapshot.Path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(snapshot.Size), info.Size())
+}
@@ -0,0 +1,107 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// ErrMalformed means entity tag is not a strong tag of an entity version.
+var ErrMalformed = errors.New("malformed entity tag")
+
+// FromVersion returns strong entity tag of the entity version, e.g. `"3"`.
+func FromVersion(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// Version parses entity version from the strong entity tag created by FromVersion.
+func Version(tag string) (uint64, error) {
+	opaque, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0, ErrMalformed
+	}
+	opaque, ok = strings.CutSuffix(opaque, `"`)
+	if !ok {
+		return 0, ErrMalformed
+	}
+	v, err := strconv.ParseUint(opaque, 10, 64)
+	if err != nil {
+		return 0, ErrMalformed
+	}
+	return v, nil
+}
+
+// Match reports whether the entity tag matches any tag of If-Match or If-None-Match header value.
+// Weak comparison ignores W/ prefix, strong comparison never matches weak tags.
+// Wildcard "*" matches any tag.
+func Match(header, tag string, weak bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	if weak {
+		tag = strings.TrimPrefix(tag, "W/")
+	} else if strings.HasPrefix(tag, "W/") {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified sets ETag header of the response and reports whether the response is not modified
+// according to If-None-Match header of GET or HEAD request.
+// Not modified response is written with 304 Not Modified.
+func NotModified(w http.ResponseWriter, r *http.Request, tag string) bool {
+	w.Header().Set("ETag", tag)
+	inm := r.Header.Get("If-None-Match")
+	if inm == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) || !Match(inm, tag, true) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// Precondition reports whether If-Match header of the request matches the current entity tag.
+// Missing header satisfies the precondition unless it is required.
+// Failed precondition is written as 412 Precondition Failed problem with the current tag,
+// missing required header is written as 428 Precondition Required problem.
+func Precondition(w http.ResponseWriter, r *http.Request, tag string, required bool) bool {
+	im := r.Header.Get("If-Match")
+	switch {
+	case im == "" && required:
+		problem.Write(w, r, problem.New(http.StatusPreconditionRequired, "If-Match header is required"))
+		return false
+	case im == "" || Match(im, tag, false):
+		return true
+	default:
+		w.Header().Set("ETag", tag)
+		problem.Write(w, r, problem.New(http.StatusPreconditionFailed, "entity was modified").With("etag", tag))
+		return false
+	}
+}
+
+// ExpectedVersion extracts the entity version expected by If-Match header of the request
+// for atomic compare-and-update in the storage.
+// Reports false if the header is absent, or it is a wildcard or a list.
+func ExpectedVersion(r *http.Request) (uint64, bool, error) {
+	im := strings.TrimSpace(r.Header.Get("If-Match"))
+	if im == "" || im == "*" || strings.Contains(im, ",") {
+		return 0, false, nil
+	}
+	v, err := Version(im)
+	if err != nil {
+		return 0, false, err
+	}
+	return v, true, nil
+}
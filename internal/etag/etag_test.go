@@ -0,0 +1,118 @@
+package etag_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/etag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromVersion_ShouldRoundTrip(t *testing.T) {
+	tag := etag.FromVersion(42)
+
+	v, err := etag.Version(tag)
+
+	require.NoError(t, err)
+	assert.Equal(t, `"42"`, tag)
+	assert.Equal(t, uint64(42), v)
+}
+
+func TestVersion_Malformed_ShouldError(t *testing.T) {
+	for _, tag := range []string{``, `42`, `"42`, `W/"42"`, `"v42"`, `"-1"`} {
+		_, err := etag.Version(tag)
+
+		assert.ErrorIs(t, err, etag.ErrMalformed, tag)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		header, tag string
+		weak, want  bool
+	}{
+		{`"1"`, `"1"`, false, true},
+		{`"1"`, `"2"`, false, false},
+		{`"0", "1"`, `"1"`, false, true},
+		{`*`, `"1"`, false, true},
+		{`W/"1"`, `"1"`, false, false},
+		{`W/"1"`, `"1"`, true, true},
+		{`"1"`, `W/"1"`, true, true},
+		{`"1"`, `W/"1"`, false, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, etag.Match(c.header, c.tag, c.weak), "%s %s weak=%v", c.header, c.tag, c.weak)
+	}
+}
+
+func TestNotModified_MatchingTag_ShouldWriteNotModified(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+	req.Header.Set("If-None-Match", `W/"3"`)
+	rec := httptest.NewRecorder()
+
+	ok := etag.NotModified(rec, req, etag.FromVersion(3))
+
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestNotModified_StaleTag_ShouldSetETag(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+	req.Header.Set("If-None-Match", `"2"`)
+	rec := httptest.NewRecorder()
+
+	ok := etag.NotModified(rec, req, etag.FromVersion(3))
+
+	assert.False(t, ok)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestPrecondition(t *testing.T) {
+	cases := []struct {
+		name     string
+		ifMatch  string
+		required bool
+		ok       bool
+		status   int
+	}{
+		{"matching", `"3"`, true, true, http.StatusOK},
+		{"absent", "", false, true, http.StatusOK},
+		{"absent required", "", true, false, http.StatusPreconditionRequired},
+		{"stale", `"2"`, false, false, http.StatusPreconditionFailed},
+		{"weak", `W/"3"`, false, false, http.StatusPreconditionFailed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/tasks/1", nil)
+			if c.ifMatch != "" {
+				req.Header.Set("If-Match", c.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+
+			ok := etag.Precondition(rec, req, etag.FromVersion(3), c.required)
+
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.status, rec.Code)
+		})
+	}
+}
+
+func TestExpectedVersion(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/1", nil)
+	_, ok, err := etag.ExpectedVersion(req)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	req.Header.Set("If-Match", `"7"`)
+	v, ok, err := etag.ExpectedVersion(req)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), v)
+
+	req.Header.Set("If-Match", `"x"`)
+	_, _, err = etag.ExpectedVersion(req)
+	assert.ErrorIs(t, err, etag.ErrMalformed)
+}
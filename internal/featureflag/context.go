@@ -0,0 +1,22 @@
+package featureflag
+
+import "context"
+
+type evaluator struct{}
+
+type flags struct {
+	reg     *Registry
+	subject Subject
+}
+
+// WithSubject injects registry and the subject flags are evaluated for into the context.
+func WithSubject(ctx context.Context, reg *Registry, s Subject) context.Context {
+	return context.WithValue(ctx, evaluator{}, flags{reg: reg, subject: s})
+}
+
+// Enabled reports whether the flag is on for the subject of the context.
+// Flags are off if the context has no registry.
+func Enabled(ctx context.Context, name string) bool {
+	f, ok := ctx.Value(evaluator{}).(flags)
+	return ok && f.reg.Enabled(name, f.subject)
+}
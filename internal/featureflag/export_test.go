@@ -0,0 +1,8 @@
+package featureflag
+
+import "time"
+
+// SetClock replaces clock of the registry for testing purposes.
+func (reg *Registry) SetClock(now func() time.Time) {
+	reg.now = now
+}
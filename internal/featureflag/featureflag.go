@@ -0,0 +1,156 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sort"
+)
+
+// percentScale is a number of rollout buckets of a single percent.
+const percentScale = 100
+
+// RolloutBy is a subject id the percentage rollout buckets subjects by.
+type RolloutBy string
+
+// Rollout bucketing keys. Empty key buckets by the first known id of the user, the team and the tenant.
+const (
+	RolloutByUser   RolloutBy = "user"
+	RolloutByTeam   RolloutBy = "team"
+	RolloutByTenant RolloutBy = "tenant"
+)
+
+// Flag is a feature flag with targeting rules.
+// Disabled flag is off for everyone.
+// Enabled flag without rules is on for everyone, otherwise it is on for the subjects
+// listed in users, teams or tenants and for the rollout percentage of the rest.
+// Rollout by team or tenant turns the flag on or off for all their members together.
+type Flag struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	Users       []string  `json:"users,omitempty"`
+	Teams       []string  `json:"teams,omitempty"`
+	Tenants     []string  `json:"tenants,omitempty"`
+	Percentage  float64   `json:"percentage,omitempty"`
+	RolloutBy   RolloutBy `json:"rollout_by,omitempty"`
+}
+
+// Subject is a requester a flag is evaluated for.
+type Subject struct {
+	UserID   string
+	TeamID   string
+	TenantID string
+}
+
+// key identifies subject for the percentage rollout by the id.
+// Subjects without the id never fall into the rollout.
+func (s Subject) key(by RolloutBy) string {
+	switch by {
+	case RolloutByUser:
+		return prefixed("user:", s.UserID)
+	case RolloutByTeam:
+		return prefixed("team:", s.TeamID)
+	case RolloutByTenant:
+		return prefixed("tenant:", s.TenantID)
+	}
+	switch {
+	case s.UserID != "":
+		return "user:" + s.UserID
+	case s.TeamID != "":
+		return "team:" + s.TeamID
+	case s.TenantID != "":
+		return "tenant:" + s.TenantID
+	default:
+		return ""
+	}
+}
+
+// prefixed returns the id with the prefix, empty id stays empty.
+func prefixed(prefix, id string) string {
+	if id == "" {
+		return ""
+	}
+	return prefix + id
+}
+
+// Evaluate reports whether the flag is on for the subject.
+func (f Flag) Evaluate(s Subject) bool {
+	if !f.Enabled {
+		return false
+	}
+	if len(f.Users) == 0 && len(f.Teams) == 0 && len(f.Tenants) == 0 && f.Percentage == 0 {
+		return true
+	}
+	if (s.UserID != "" && slices.Contains(f.Users, s.UserID)) ||
+		(s.TeamID != "" && slices.Contains(f.Teams, s.TeamID)) ||
+		(s.TenantID != "" && slices.Contains(f.Tenants, s.TenantID)) {
+		return true
+	}
+	key := s.key(f.RolloutBy)
+	if key == "" || f.Percentage <= 0 {
+		return false
+	}
+	return float64(bucket(f.Name, key)) < f.Percentage*percentScale
+}
+
+// bucket maps the subject key to a stable rollout bucket of the flag in [0, 10000).
+// Hashing with the flag name spreads rollouts of different flags across different subjects.
+func bucket(name, key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + key))
+	return h.Sum32() % (100 * percentScale) //nolint:mnd // 100 percents
+}
+
+// Validate checks the flag rules.
+func (f Flag) Validate() error {
+	if f.Name == "" {
+		return errors.New("feature flag name is empty")
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("feature flag %q: percentage should be in [0, 100]", f.Name)
+	}
+	switch f.RolloutBy {
+	case "", RolloutByUser, RolloutByTeam, RolloutByTenant:
+	default:
+		return fmt.Errorf("feature flag %q: rollout_by should be one of user, team, tenant", f.Name)
+	}
+	return nil
+}
+
+// LoadFile reads flags from JSON file containing an array of flags.
+func LoadFile(path string) ([]Flag, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("feature flags file: %w", err)
+	}
+	var flags []Flag
+	if err := json.Unmarshal(b, &flags); err != nil {
+		return nil, fmt.Errorf("feature flags file %s: %w", path, err)
+	}
+	return flags, nil
+}
+
+// Merge overrides enabled state of the flags by the toggles,
+// toggles of unknown flags define new flags without rules.
+// Result is sorted by flag names.
+func Merge(flags []Flag, toggles map[string]bool) []Flag {
+	byName := make(map[string]Flag, len(flags)+len(toggles))
+	for _, f := range flags {
+		byName[f.Name] = f
+	}
+	for name, enabled := range toggles {
+		f := byName[name]
+		f.Name, f.Enabled = name, enabled
+		byName[name] = f
+	}
+	merged := make([]Flag, 0, len(byName))
+	for _, f := range byName {
+		merged = append(merged, f)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
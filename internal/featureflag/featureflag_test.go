@@ -0,0 +1,119 @@
+package featureflag_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlag_Evaluate_Disabled_ShouldBeOff(t *testing.T) {
+	f := featureflag.Flag{Name: "swimlanes", Users: []string{"u1"}}
+
+	assert.False(t, f.Evaluate(featureflag.Subject{UserID: "u1"}))
+}
+
+func TestFlag_Evaluate_NoRules_ShouldBeOnForEveryone(t *testing.T) {
+	f := featureflag.Flag{Name: "swimlanes", Enabled: true}
+
+	assert.True(t, f.Evaluate(featureflag.Subject{}))
+}
+
+func TestFlag_Evaluate_Targets_ShouldMatchListed(t *testing.T) {
+	f := featureflag.Flag{
+		Name: "swimlanes", Enabled: true,
+		Users: []string{"u1"}, Teams: []string{"core"}, Tenants: []string{"acme"},
+	}
+
+	assert.True(t, f.Evaluate(featureflag.Subject{UserID: "u1"}))
+	assert.True(t, f.Evaluate(featureflag.Subject{UserID: "u2", TeamID: "core"}))
+	assert.True(t, f.Evaluate(featureflag.Subject{UserID: "u3", TenantID: "acme"}))
+	assert.False(t, f.Evaluate(featureflag.Subject{UserID: "u4", TeamID: "web", TenantID: "globex"}))
+	assert.False(t, f.Evaluate(featureflag.Subject{}))
+}
+
+func TestFlag_Evaluate_Percentage_ShouldRolloutStably(t *testing.T) {
+	f := featureflag.Flag{Name: "swimlanes", Enabled: true, Percentage: 30}
+	const subjects = 10000
+
+	on := 0
+	for i := range subjects {
+		s := featureflag.Subject{UserID: "user-" + strconv.Itoa(i)}
+		enabled := f.Evaluate(s)
+		require.Equal(t, enabled, f.Evaluate(s), "evaluation should be stable")
+		if enabled {
+			on++
+		}
+	}
+
+	assert.InDelta(t, 3000, on, 300)
+}
+
+func TestFlag_Evaluate_RolloutByTeam_ShouldMatchTeamMembers(t *testing.T) {
+	f := featureflag.Flag{Name: "swimlanes", Enabled: true, Percentage: 50, RolloutBy: featureflag.RolloutByTeam}
+	const teams, members = 100, 20
+
+	on := 0
+	for i := range teams {
+		team := "team-" + strconv.Itoa(i)
+		enabled := f.Evaluate(featureflag.Subject{UserID: "user-0", TeamID: team})
+		for j := 1; j < members; j++ {
+			s := featureflag.Subject{UserID: "user-" + strconv.Itoa(j), TeamID: team}
+			require.Equal(t, enabled, f.Evaluate(s), "members of %s should get the same result", team)
+		}
+		if enabled {
+			on++
+		}
+	}
+
+	assert.InDelta(t, 50, on, 20)
+	assert.False(t, f.Evaluate(featureflag.Subject{UserID: "user-0"}))
+}
+
+func TestFlag_Evaluate_FullPercentage_ShouldBeOnForIdentified(t *testing.T) {
+	f := featureflag.Flag{Name: "swimlanes", Enabled: true, Percentage: 100}
+
+	assert.True(t, f.Evaluate(featureflag.Subject{TenantID: "acme"}))
+	assert.False(t, f.Evaluate(featureflag.Subject{}))
+}
+
+func TestFlag_Validate(t *testing.T) {
+	assert.NoError(t, featureflag.Flag{Name: "swimlanes", Percentage: 100}.Validate())
+	assert.Error(t, featureflag.Flag{}.Validate())
+	assert.Error(t, featureflag.Flag{Name: "swimlanes", Percentage: 101}.Validate())
+	assert.Error(t, featureflag.Flag{Name: "swimlanes", RolloutBy: "org"}.Validate())
+}
+
+func TestLoadFile_ShouldReadFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name":"swimlanes","enabled":true,"teams":["core"]}]`), 0o600))
+
+	flags, err := featureflag.LoadFile(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []featureflag.Flag{{Name: "swimlanes", Enabled: true, Teams: []string{"core"}}}, flags)
+}
+
+func TestLoadFile_Malformed_ShouldError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+
+	_, err := featureflag.LoadFile(path)
+
+	assert.Error(t, err)
+}
+
+func TestMerge_ShouldOverrideEnabled(t *testing.T) {
+	flags := []featureflag.Flag{{Name: "swimlanes", Teams: []string{"core"}}}
+
+	merged := featureflag.Merge(flags, map[string]bool{"swimlanes": true, "analytics": false})
+
+	assert.Equal(t, []featureflag.Flag{
+		{Name: "analytics"},
+		{Name: "swimlanes", Enabled: true, Teams: []string{"core"}},
+	}, merged)
+}
@@ -0,0 +1,89 @@
+package featureflag
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// flagPatch is a partial update of a flag.
+// Nil fields are kept unchanged.
+type flagPatch struct {
+	Description *string    `json:"description"`
+	Enabled     *bool      `json:"enabled"`
+	Users       *[]string  `json:"users"`
+	Teams       *[]string  `json:"teams"`
+	Tenants     *[]string  `json:"tenants"`
+	Percentage  *float64   `json:"percentage"`
+	RolloutBy   *RolloutBy `json:"rollout_by"`
+}
+
+func (p flagPatch) apply(f Flag) Flag {
+	if p.Description != nil {
+		f.Description = *p.Description
+	}
+	if p.Enabled != nil {
+		f.Enabled = *p.Enabled
+	}
+	if p.Users != nil {
+		f.Users = *p.Users
+	}
+	if p.Teams != nil {
+		f.Teams = *p.Teams
+	}
+	if p.Tenants != nil {
+		f.Tenants = *p.Tenants
+	}
+	if p.Percentage != nil {
+		f.Percentage = *p.Percentage
+	}
+	if p.RolloutBy != nil {
+		f.RolloutBy = *p.RolloutBy
+	}
+	return f
+}
+
+// Router routes administrative API of the flags:
+// GET / lists flags, GET /audit lists the latest changes,
+// GET /{name} responds with the flag and PATCH /{name} partially updates or creates the flag.
+// Actor identifies the administrator in the audit log.
+func Router(reg *Registry, actor func(*http.Request) string) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, reg.List())
+	})
+	r.Get("/audit", func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, reg.Changes())
+	})
+	r.Get("/{name}", func(w http.ResponseWriter, r *http.Request) {
+		f, err := reg.Get(chi.URLParam(r, "name"))
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusNotFound, err.Error()))
+			return
+		}
+		render.JSON(w, r, f)
+	})
+	r.Patch("/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var p flagPatch
+		if err := decode.JSON(r, &p); err != nil {
+			problem.Write(w, r, decode.Problem(err))
+			return
+		}
+		name := chi.URLParam(r, "name")
+		f, err := reg.Get(name)
+		if errors.Is(err, ErrNotFound) {
+			f = Flag{Name: name}
+		}
+		f = p.apply(f)
+		if err := reg.Set(r.Context(), actor(r), f); err != nil {
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, err.Error()))
+			return
+		}
+		render.JSON(w, r, f)
+	})
+	return r
+}
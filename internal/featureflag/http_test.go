@@ -0,0 +1,85 @@
+package featureflag_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveFlags(reg *featureflag.Registry, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	featureflag.Router(reg, func(*http.Request) string { return "admin" }).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouter_List_ShouldServeFlags(t *testing.T) {
+	reg, _ := newRegistry(t, featureflag.Flag{Name: "swimlanes", Enabled: true})
+
+	rec := serveFlags(reg, http.MethodGet, "/", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"name":"swimlanes","enabled":true}]`, rec.Body.String())
+}
+
+func TestRouter_Get_UnknownFlag_ShouldNotFound(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	rec := serveFlags(reg, http.MethodGet, "/swimlanes", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRouter_Patch_ShouldToggleAndAudit(t *testing.T) {
+	reg, _ := newRegistry(t, featureflag.Flag{Name: "swimlanes", Teams: []string{"core"}})
+
+	rec := serveFlags(reg, http.MethodPatch, "/swimlanes", `{"enabled":true,"percentage":10}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	f, _ := reg.Get("swimlanes")
+	assert.Equal(t, featureflag.Flag{Name: "swimlanes", Enabled: true, Teams: []string{"core"}, Percentage: 10}, f)
+
+	audit := serveFlags(reg, http.MethodGet, "/audit", "")
+	var changes []featureflag.Change
+	require.NoError(t, json.Unmarshal(audit.Body.Bytes(), &changes))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "admin", changes[0].Actor)
+}
+
+func TestRouter_Patch_NewFlag_ShouldCreate(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	rec := serveFlags(reg, http.MethodPatch, "/analytics", `{"enabled":true}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, reg.Enabled("analytics", featureflag.Subject{}))
+}
+
+func TestRouter_Patch_InvalidFlag_ShouldUnprocessable(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	rec := serveFlags(reg, http.MethodPatch, "/analytics", `{"percentage":150}`)
+	rollout := serveFlags(reg, http.MethodPatch, "/analytics", `{"rollout_by":"org"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, rollout.Code)
+}
+
+func TestRouter_Patch_RolloutBy_ShouldSetBucketingKey(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	rec := serveFlags(reg, http.MethodPatch, "/analytics", `{"enabled":true,"percentage":100,"rollout_by":"team"}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, reg.Enabled("analytics", featureflag.Subject{TeamID: "core"}))
+	assert.False(t, reg.Enabled("analytics", featureflag.Subject{UserID: "alice"}))
+}
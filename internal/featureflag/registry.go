@@ -0,0 +1,114 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound means flag is not registered.
+var ErrNotFound = errors.New("feature flag not found")
+
+// auditSize is a number of the latest changes kept in memory.
+const auditSize = 100
+
+// Change is an audit record of a flag change.
+// Before is nil for a new flag.
+type Change struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Flag   string    `json:"flag"`
+	Before *Flag     `json:"before"`
+	After  Flag      `json:"after"`
+}
+
+// Registry keeps flags changed at runtime and audits the changes.
+type Registry struct {
+	mu      sync.RWMutex
+	flags   map[string]Flag
+	changes []Change
+	log     *slog.Logger
+	now     func() time.Time
+}
+
+// NewRegistry creates registry of the flags logging changes to the audit logger.
+func NewRegistry(flags []Flag, audit *slog.Logger) (*Registry, error) {
+	reg := &Registry{flags: make(map[string]Flag, len(flags)), log: audit, now: time.Now}
+	for _, f := range flags {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		reg.flags[f.Name] = f
+	}
+	return reg, nil
+}
+
+// Enabled reports whether the flag is on for the subject.
+// Unknown flags are off.
+func (reg *Registry) Enabled(name string, s Subject) bool {
+	reg.mu.RLock()
+	f, ok := reg.flags[name]
+	reg.mu.RUnlock()
+	return ok && f.Evaluate(s)
+}
+
+// Get returns the flag by name.
+func (reg *Registry) Get(name string) (Flag, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	f, ok := reg.flags[name]
+	if !ok {
+		return Flag{}, ErrNotFound
+	}
+	return f, nil
+}
+
+// List returns flags sorted by names.
+func (reg *Registry) List() []Flag {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	flags := make([]Flag, 0, len(reg.flags))
+	for _, f := range reg.flags {
+		flags = append(flags, f)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// Set creates or replaces the flag on behalf of the actor and records the change.
+func (reg *Registry) Set(ctx context.Context, actor string, f Flag) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	reg.mu.Lock()
+	var before *Flag
+	if old, ok := reg.flags[f.Name]; ok {
+		before = &old
+	}
+	reg.flags[f.Name] = f
+	change := Change{Time: reg.now(), Actor: actor, Flag: f.Name, Before: before, After: f}
+	reg.changes = append(reg.changes, change)
+	if len(reg.changes) > auditSize {
+		reg.changes = reg.changes[len(reg.changes)-auditSize:]
+	}
+	reg.mu.Unlock()
+
+	reg.log.InfoContext(ctx, "Feature flag changed",
+		slog.String("flag", f.Name),
+		slog.String("actor", actor),
+		slog.Any("before", before),
+		slog.Any("after", f),
+	)
+	return nil
+}
+
+// Changes returns the latest changes, the most recent last.
+func (reg *Registry) Changes() []Change {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return append([]Change(nil), reg.changes...)
+}
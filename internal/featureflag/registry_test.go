@@ -0,0 +1,81 @@
+package featureflag_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistry(t *testing.T, flags ...featureflag.Flag) (*featureflag.Registry, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	reg, err := featureflag.NewRegistry(flags, slog.New(slog.NewJSONHandler(&buf, nil)))
+	require.NoError(t, err)
+	return reg, &buf
+}
+
+func TestNewRegistry_InvalidFlag_ShouldError(t *testing.T) {
+	_, err := featureflag.NewRegistry([]featureflag.Flag{{Name: "x", Percentage: -1}}, slog.Default())
+
+	assert.Error(t, err)
+}
+
+func TestRegistry_Enabled_UnknownFlag_ShouldBeOff(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	assert.False(t, reg.Enabled("swimlanes", featureflag.Subject{UserID: "u1"}))
+}
+
+func TestRegistry_Set_ShouldChangeAndAudit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg, log := newRegistry(t, featureflag.Flag{Name: "swimlanes"})
+	reg.SetClock(func() time.Time { return now })
+
+	err := reg.Set(context.Background(), "admin", featureflag.Flag{Name: "swimlanes", Enabled: true})
+
+	require.NoError(t, err)
+	assert.True(t, reg.Enabled("swimlanes", featureflag.Subject{}))
+	assert.Equal(t, []featureflag.Change{{
+		Time:   now,
+		Actor:  "admin",
+		Flag:   "swimlanes",
+		Before: &featureflag.Flag{Name: "swimlanes"},
+		After:  featureflag.Flag{Name: "swimlanes", Enabled: true},
+	}}, reg.Changes())
+	assert.Contains(t, log.String(), `"msg":"Feature flag changed"`)
+	assert.Contains(t, log.String(), `"actor":"admin"`)
+}
+
+func TestRegistry_Set_InvalidFlag_ShouldError(t *testing.T) {
+	reg, _ := newRegistry(t)
+
+	err := reg.Set(context.Background(), "admin", featureflag.Flag{Name: "swimlanes", Percentage: 200})
+
+	assert.Error(t, err)
+	assert.Empty(t, reg.Changes())
+}
+
+func TestRegistry_List_ShouldSortByName(t *testing.T) {
+	reg, _ := newRegistry(t, featureflag.Flag{Name: "b"}, featureflag.Flag{Name: "a"})
+
+	flags := reg.List()
+
+	require.Len(t, flags, 2)
+	assert.Equal(t, "a", flags[0].Name)
+	assert.Equal(t, "b", flags[1].Name)
+}
+
+func TestEnabled_FromContext_ShouldEvaluateSubject(t *testing.T) {
+	reg, _ := newRegistry(t, featureflag.Flag{Name: "swimlanes", Enabled: true, Teams: []string{"core"}})
+
+	ctx := featureflag.WithSubject(context.Background(), reg, featureflag.Subject{TeamID: "core"})
+
+	assert.True(t, featureflag.Enabled(ctx, "swimlanes"))
+	assert.False(t, featureflag.Enabled(context.Background(), "swimlanes"))
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrShuttingDown is reported by Drain check once shutdown has begun.
+var ErrShuttingDown = errors.New("shutting down")
+
+// Drain flips readiness to failing once shutdown begins,
+// so load balancers stop routing new traffic before resources are released.
+// Zero value is ready.
+type Drain struct {
+	draining atomic.Bool
+}
+
+// Begin marks the start of shutdown.
+func (d *Drain) Begin() {
+	d.draining.Store(true)
+}
+
+// Draining reports whether shutdown has begun.
+func (d *Drain) Draining() bool {
+	return d.draining.Load()
+}
+
+// Check fails with ErrShuttingDown once shutdown has begun.
+func (d *Drain) Check(context.Context) error {
+	if d.Draining() {
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+// Pinger is a dependency able to verify connectivity, e.g. storage.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping check fails if dependency could not be reached.
+func Ping(p Pinger) Check {
+	return p.Ping
+}
+
+// MaxLag check fails if processing lag reported by the function exceeds the maximum,
+// e.g. age of the oldest message of the queue.
+func MaxLag(lag func(ctx context.Context) (time.Duration, error), maximum time.Duration) Check {
+	return func(ctx context.Context) error {
+		d, err := lag(ctx)
+		if err != nil {
+			return err
+		}
+		if d > maximum {
+			return fmt.Errorf("lag %s exceeds %s", d, maximum)
+		}
+		return nil
+	}
+}
+
+// Saturated check fails while the function reports saturation, e.g. of the load shedder.
+func Saturated(saturated func() bool) Check {
+	return func(context.Context) error {
+		if saturated() {
+			return errors.New("saturated")
+		}
+		return nil
+	}
+}
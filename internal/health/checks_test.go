@@ -0,0 +1,54 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain_Check_AfterBegin_ShouldFail(t *testing.T) {
+	var d health.Drain
+	require.NoError(t, d.Check(context.Background()))
+
+	d.Begin()
+
+	assert.True(t, d.Draining())
+	assert.ErrorIs(t, d.Check(context.Background()), health.ErrShuttingDown)
+}
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+func TestPing_Unreachable_ShouldFail(t *testing.T) {
+	err := errors.New("connection refused")
+
+	check := health.Ping(pingerFunc(func(context.Context) error { return err }))
+
+	assert.ErrorIs(t, check(context.Background()), err)
+}
+
+func TestMaxLag_ShouldFailOverMaximum(t *testing.T) {
+	lag := time.Second
+	check := health.MaxLag(func(context.Context) (time.Duration, error) { return lag, nil }, time.Second)
+	require.NoError(t, check(context.Background()))
+
+	lag = 2 * time.Second
+
+	assert.EqualError(t, check(context.Background()), "lag 2s exceeds 1s")
+}
+
+func TestSaturated_ShouldFailWhileSaturated(t *testing.T) {
+	saturated := false
+	check := health.Saturated(func() bool { return saturated })
+	require.NoError(t, check(context.Background()))
+
+	saturated = true
+
+	assert.Error(t, check(context.Background()))
+}
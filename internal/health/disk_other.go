@@ -0,0 +1,15 @@
+//go:build !unix
+
+package health
+
+import (
+	"context"
+	"errors"
+)
+
+// DiskSpace check is not supported on the platform and always fails.
+func DiskSpace(string, uint64) Check {
+	return func(context.Context) error {
+		return errors.ErrUnsupported
+	}
+}
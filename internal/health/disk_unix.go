@@ -0,0 +1,25 @@
+//go:build unix
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// DiskSpace check fails if free space of the file system containing the path
+// available to unprivileged users drops below minFree bytes.
+func DiskSpace(path string, minFree uint64) Check {
+	return func(context.Context) error {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(path, &st); err != nil {
+			return fmt.Errorf("disk space of %s: %w", path, err)
+		}
+		free := st.Bavail * uint64(st.Bsize) //nolint:gosec,unconvert // block size is positive and its type differs by platform
+		if free < minFree {
+			return fmt.Errorf("disk space of %s: %d bytes free, %d required", path, free, minFree)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package health_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskSpace_ShouldCompareFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, health.DiskSpace(dir, 0)(context.Background()))
+	assert.Error(t, health.DiskSpace(dir, math.MaxUint64)(context.Background()))
+	assert.Error(t, health.DiskSpace(dir+"/missing", 0)(context.Background()))
+}
@@ -0,0 +1,20 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// Handler serves JSON report of the registry checks.
+// Responds with 200 if every check passes and 503 otherwise.
+func Handler(reg *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := reg.Run(r.Context())
+		w.Header().Set("Cache-Control", "no-store")
+		if !report.Passed() {
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+		render.JSON(w, r, report)
+	})
+}
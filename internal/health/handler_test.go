@@ -0,0 +1,39 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ServeHTTP_Passed_ShouldOK(t *testing.T) {
+	reg := health.NewRegistry()
+	reg.Register("storage", pass)
+	rec := httptest.NewRecorder()
+
+	health.Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Contains(t, rec.Body.String(), `"status":"pass"`)
+}
+
+func TestHandler_ServeHTTP_Draining_ShouldServiceUnavailable(t *testing.T) {
+	var d health.Drain
+	now := time.Now()
+	reg := health.NewRegistry()
+	reg.SetClock(func() time.Time { return now })
+	reg.Register("shutdown", d.Check)
+	d.Begin()
+	rec := httptest.NewRecorder()
+
+	health.Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"fail","checks":{"shutdown":{"status":"fail","error":"shutting down","duration":"0s"}}}`,
+		rec.Body.String())
+}
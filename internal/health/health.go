@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Check status values.
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// DefaultTimeout of the check registered without Timeout option.
+const DefaultTimeout = time.Second
+
+// Check verifies health of a dependency.
+// Returns an error describing the failure if dependency is unhealthy.
+type Check func(ctx context.Context) error
+
+// Result of a single check run.
+type Result struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"-"`
+	Cached   bool          `json:"cached,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// Duration is presented in human-readable form, e.g. "1.5ms".
+func (r Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	return json.Marshal(struct {
+		result
+		Duration string `json:"duration"`
+	}{result: result(r), Duration: r.Duration.String()})
+}
+
+// Report of all registered checks.
+// Report passes only if every check passes.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Passed reports whether every check passed.
+func (r Report) Passed() bool {
+	return r.Status == StatusPass
+}
+
+// Option configures a registered check.
+type Option func(*entry)
+
+// Timeout limits duration of the check, timed out check fails.
+// Non-positive timeout keeps the DefaultTimeout.
+func Timeout(d time.Duration) Option {
+	return func(e *entry) {
+		if d <= 0 {
+			return
+		}
+		e.timeout = d
+	}
+}
+
+// CacheFor reuses the latest result of the check for the duration
+// to protect expensive dependencies from frequent probes.
+func CacheFor(d time.Duration) Option {
+	return func(e *entry) {
+		e.ttl = d
+	}
+}
+
+type entry struct {
+	name    string
+	check   Check
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu   sync.Mutex
+	last Result
+	at   time.Time
+}
+
+// Registry is a set of named checks run together.
+type Registry struct {
+	mu      sync.RWMutex
+	entries []*entry
+	now     func() time.Time
+}
+
+// NewRegistry creates an empty registry, empty registry always passes.
+func NewRegistry() *Registry {
+	return &Registry{now: time.Now}
+}
+
+// Register adds named check to the registry replacing the check with the same name.
+func (reg *Registry) Register(name string, check Check, opts ...Option) {
+	e := &entry{name: name, check: check, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(e)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	for i, old := range reg.entries {
+		if old.name == name {
+			reg.entries[i] = e
+			return
+		}
+	}
+	reg.entries = append(reg.entries, e)
+}
+
+// Names returns sorted names of the registered checks.
+func (reg *Registry) Names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	names := make([]string, 0, len(reg.entries))
+	for _, e := range reg.entries {
+		names = append(names, e.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs all checks concurrently and collects the report.
+func (reg *Registry) Run(ctx context.Context) Report {
+	reg.mu.RLock()
+	entries := append([]*entry(nil), reg.entries...)
+	reg.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = reg.run(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusPass, Checks: make(map[string]Result, len(entries))}
+	for i, e := range entries {
+		report.Checks[e.name] = results[i]
+		if results[i].Status != StatusPass {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (reg *Registry) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ttl > 0 && !e.at.IsZero() && reg.now().Sub(e.at) < e.ttl {
+		res := e.last
+		res.Cached = true
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	start := reg.now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- e.check(ctx) }()
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := Result{Status: StatusPass, Duration: reg.now().Sub(start)}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	e.last, e.at = res, reg.now()
+	return res
+}
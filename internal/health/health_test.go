@@ -0,0 +1,86 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pass(context.Context) error { return nil }
+
+func TestRegistry_Run_Empty_ShouldPass(t *testing.T) {
+	reg := health.NewRegistry()
+
+	report := reg.Run(context.Background())
+
+	assert.True(t, report.Passed())
+	assert.Empty(t, report.Checks)
+}
+
+func TestRegistry_Run_FailingCheck_ShouldFailReport(t *testing.T) {
+	reg := health.NewRegistry()
+	reg.Register("storage", pass)
+	reg.Register("queue", func(context.Context) error { return errors.New("unreachable") })
+
+	report := reg.Run(context.Background())
+
+	assert.False(t, report.Passed())
+	assert.Equal(t, health.StatusPass, report.Checks["storage"].Status)
+	assert.Equal(t, health.StatusFail, report.Checks["queue"].Status)
+	assert.Equal(t, "unreachable", report.Checks["queue"].Error)
+}
+
+func TestRegistry_Run_SlowCheck_ShouldTimeout(t *testing.T) {
+	reg := health.NewRegistry()
+	block := make(chan struct{})
+	defer close(block)
+	reg.Register("slow", func(context.Context) error { <-block; return nil }, health.Timeout(time.Millisecond))
+
+	report := reg.Run(context.Background())
+
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestRegistry_Run_CachedCheck_ShouldReuseResult(t *testing.T) {
+	now := time.Now()
+	reg := health.NewRegistry()
+	reg.SetClock(func() time.Time { return now })
+	var calls int
+	reg.Register("disk", func(context.Context) error { calls++; return nil }, health.CacheFor(time.Second))
+
+	reg.Run(context.Background())
+	cached := reg.Run(context.Background())
+	now = now.Add(time.Second)
+	fresh := reg.Run(context.Background())
+
+	assert.Equal(t, 2, calls)
+	assert.True(t, cached.Checks["disk"].Cached)
+	assert.False(t, fresh.Checks["disk"].Cached)
+}
+
+func TestRegistry_Register_SameName_ShouldReplace(t *testing.T) {
+	reg := health.NewRegistry()
+	reg.Register("storage", func(context.Context) error { return errors.New("down") })
+	reg.Register("storage", pass)
+	reg.Register("disk", pass)
+
+	report := reg.Run(context.Background())
+
+	assert.True(t, report.Passed())
+	assert.Equal(t, []string{"disk", "storage"}, reg.Names())
+}
+
+func TestResult_MarshalJSON_ShouldFormatDuration(t *testing.T) {
+	res := health.Result{Status: health.StatusFail, Error: "down", Duration: 1500 * time.Microsecond}
+
+	b, err := json.Marshal(res)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"fail","error":"down","duration":"1.5ms"}`, string(b))
+}
@@ -0,0 +1,8 @@
+package idempotency
+
+import "time"
+
+// SetClock replaces clock of the store for testing purposes.
+func (s *MemoryStore) SetClock(now func() time.Time) {
+	s.now = now
+}
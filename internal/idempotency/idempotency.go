@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is a state of the request stored under the idempotency key.
+// Fingerprint identifies request payload to detect key reuse with a different request.
+// Response fields are set once the request is completed.
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// Store keeps idempotency records shared between service instances.
+// A Redis-compatible store implements Reserve with SET NX.
+type Store interface {
+	// Reserve stores in-flight record under the key with ttl if the key is absent or expired.
+	// Reports false and returns the stored record if the key is already reserved or completed.
+	Reserve(ctx context.Context, key string, rec Record, ttl time.Duration) (Record, bool, error)
+	// Complete replaces record of the key with the completed one keeping it for ttl.
+	Complete(ctx context.Context, key string, rec Record, ttl time.Duration) error
+	// Release removes record of the key allowing the request to be retried.
+	Release(ctx context.Context, key string) error
+}
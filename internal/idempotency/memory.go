@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is a number of writes between expired entries sweeps.
+const sweepEvery = 1024
+
+type entry struct {
+	rec     Record
+	expires time.Time
+}
+
+// MemoryStore is an in-memory Store for a single service instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryStore creates empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]entry), now: time.Now}
+}
+
+// Reserve implements Store interface.
+func (s *MemoryStore) Reserve(_ context.Context, key string, rec Record, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e.rec, false, nil
+	}
+	s.put(key, rec, now.Add(ttl))
+	return rec, true, nil
+}
+
+// Complete implements Store interface.
+func (s *MemoryStore) Complete(_ context.Context, key string, rec Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(key, rec, s.now().Add(ttl))
+	return nil
+}
+
+// Release implements Store interface.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Len returns number of stored entries including expired ones not swept yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func (s *MemoryStore) put(key string, rec Record, expires time.Time) {
+	s.entries[key] = entry{rec: rec, expires: expires}
+
+	s.writes++
+	if s.writes%sweepEvery == 0 {
+		now := s.now()
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Reserve_NewKey_ShouldReserve(t *testing.T) {
+	s := idempotency.NewMemoryStore()
+	rec := idempotency.Record{Fingerprint: "fp"}
+
+	got, ok, err := s.Reserve(context.Background(), "key", rec, time.Minute)
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, rec, got)
+}
+
+func TestMemoryStore_Reserve_ReservedKey_ShouldReturnStored(t *testing.T) {
+	s := idempotency.NewMemoryStore()
+	_, _, _ = s.Reserve(context.Background(), "key", idempotency.Record{Fingerprint: "first"}, time.Minute)
+
+	got, ok, err := s.Reserve(context.Background(), "key", idempotency.Record{Fingerprint: "second"}, time.Minute)
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "first", got.Fingerprint)
+}
+
+func TestMemoryStore_Reserve_ExpiredKey_ShouldReserveAgain(t *testing.T) {
+	now := time.Now()
+	s := idempotency.NewMemoryStore()
+	s.SetClock(func() time.Time { return now })
+	_, _, _ = s.Reserve(context.Background(), "key", idempotency.Record{Fingerprint: "first"}, time.Minute)
+
+	now = now.Add(time.Minute)
+	got, ok, _ := s.Reserve(context.Background(), "key", idempotency.Record{Fingerprint: "second"}, time.Minute)
+
+	assert.True(t, ok)
+	assert.Equal(t, "second", got.Fingerprint)
+}
+
+func TestMemoryStore_Complete_ShouldReplaceRecord(t *testing.T) {
+	s := idempotency.NewMemoryStore()
+	_, _, _ = s.Reserve(context.Background(), "key", idempotency.Record{Fingerprint: "fp"}, time.Minute)
+	done := idempotency.Record{Fingerprint: "fp", Completed: true, Status: 201, Body: []byte("{}")}
+
+	require.NoError(t, s.Complete(context.Background(), "key", done, time.Hour))
+	got, ok, _ := s.Reserve(context.Background(), "key", idempotency.Record{}, time.Minute)
+
+	assert.False(t, ok)
+	assert.Equal(t, done, got)
+}
+
+func TestMemoryStore_Release_ShouldAllowRetry(t *testing.T) {
+	s := idempotency.NewMemoryStore()
+	_, _, _ = s.Reserve(context.Background(), "key", idempotency.Record{}, time.Minute)
+
+	require.NoError(t, s.Release(context.Background(), "key"))
+	_, ok, _ := s.Reserve(context.Background(), "key", idempotency.Record{}, time.Minute)
+
+	assert.True(t, ok)
+	assert.Equal(t, 1, s.Len())
+}
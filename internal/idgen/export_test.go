@@ -0,0 +1,14 @@
+package idgen
+
+import "time"
+
+// Exported for testing purposes.
+var (
+	ULIDAt  = ulidAt
+	KSUIDAt = ksuidAt
+)
+
+// NewSnowflakeAt creates snowflake generator with a fixed clock.
+func NewSnowflakeAt(node uint16, now func() time.Time) Generator {
+	return (&snowflake{now: now, node: int64(node)}).next
+}
@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Generator produces a new unique identifier on each call.
+type Generator func() string
+
+// Generator names supported by ByName.
+const (
+	NameUUIDv4    = "uuidv4"
+	NameUUIDv7    = "uuidv7"
+	NameULID      = "ulid"
+	NameKSUID     = "ksuid"
+	NameSnowflake = "snowflake"
+)
+
+// ByName returns a generator registered under the name.
+// Node is used only by generators distinguishing service instances.
+func ByName(name string, node uint16) (Generator, error) {
+	switch name {
+	case NameUUIDv4:
+		return UUIDv4, nil
+	case NameUUIDv7:
+		return UUIDv7, nil
+	case NameULID:
+		return ULID, nil
+	case NameKSUID:
+		return KSUID, nil
+	case NameSnowflake:
+		return NewSnowflake(node)
+	default:
+		return nil, fmt.Errorf("unknown id generator %q", name)
+	}
+}
+
+// UUIDv4 generates random UUID version 4.
+func UUIDv4() string {
+	return uuid.NewString()
+}
+
+// UUIDv7 generates time ordered UUID version 7.
+func UUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
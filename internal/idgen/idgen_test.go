@@ -0,0 +1,42 @@
+package idgen_test
+
+import (
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByName_Known_ShouldReturnGenerator(t *testing.T) {
+	for _, name := range []string{
+		idgen.NameUUIDv4, idgen.NameUUIDv7, idgen.NameULID, idgen.NameKSUID, idgen.NameSnowflake,
+	} {
+		g, err := idgen.ByName(name, 1)
+
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, g(), name)
+	}
+}
+
+func TestByName_Unknown_ShouldError(t *testing.T) {
+	g, err := idgen.ByName("unknown", 0)
+
+	assert.Nil(t, g)
+	assert.Error(t, err)
+}
+
+func TestUUIDv4_ShouldBeVersion4(t *testing.T) {
+	id, err := uuid.Parse(idgen.UUIDv4())
+
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), id.Version())
+}
+
+func TestUUIDv7_ShouldBeVersion7(t *testing.T) {
+	id, err := uuid.Parse(idgen.UUIDv7())
+
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), id.Version())
+}
@@ -0,0 +1,43 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"time"
+)
+
+const (
+	base62Alphabet  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	ksuidEpoch      = 1400000000
+	ksuidTimeLen    = 4
+	ksuidPayloadLen = 16
+	ksuidEncodedLen = 27
+)
+
+// KSUID generates K-Sortable unique identifier.
+// See https://github.com/segmentio/ksuid for the format.
+func KSUID() string {
+	return ksuidAt(time.Now())
+}
+
+func ksuidAt(t time.Time) string {
+	var id [ksuidTimeLen + ksuidPayloadLen]byte
+	binary.BigEndian.PutUint32(id[:ksuidTimeLen], uint32(t.Unix()-ksuidEpoch)) //nolint:gosec // fits until 2150
+	_, _ = rand.Read(id[ksuidTimeLen:])
+
+	return encodeBase62(id[:], ksuidEncodedLen)
+}
+
+// encodeBase62 encodes bytes into base62 string left padded with zeros up to size.
+func encodeBase62(b []byte, size int) string {
+	out := make([]byte, size)
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(int64(len(base62Alphabet)))
+	mod := new(big.Int)
+	for i := size - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
@@ -0,0 +1,30 @@
+package idgen_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKSUID_ShouldBeBase62(t *testing.T) {
+	id := idgen.KSUID()
+
+	assert.Len(t, id, 27)
+	assert.Empty(t, strings.Trim(id, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"))
+}
+
+func TestKSUIDAt_ShouldBeSortable(t *testing.T) {
+	now := time.Now()
+
+	first := idgen.KSUIDAt(now)
+	second := idgen.KSUIDAt(now.Add(time.Second))
+
+	assert.Less(t, first, second)
+}
+
+func TestKSUID_ShouldBeUnique(t *testing.T) {
+	assert.NotEqual(t, idgen.KSUID(), idgen.KSUID())
+}
@@ -0,0 +1,58 @@
+package idgen
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// snowflakeEpoch is 2024-01-01T00:00:00Z in unix milliseconds.
+const snowflakeEpoch = 1704067200000
+
+type snowflake struct {
+	mu   sync.Mutex
+	now  func() time.Time
+	node int64
+	last int64
+	seq  int64
+}
+
+// NewSnowflake creates snowflake-like generator for the node.
+// Identifier is a decimal of 41 bits milliseconds, 10 bits node and 12 bits sequence.
+func NewSnowflake(node uint16) (Generator, error) {
+	if node > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node %d exceeds %d", node, snowflakeMaxNode)
+	}
+	s := &snowflake{now: time.Now, node: int64(node)}
+	return s.next, nil
+}
+
+func (s *snowflake) next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.now().UnixMilli() - snowflakeEpoch
+	if ms < s.last {
+		ms = s.last // Clock moved backwards, keep identifiers monotonic.
+	}
+	if ms == s.last {
+		s.seq = (s.seq + 1) & snowflakeMaxSeq
+		if s.seq == 0 {
+			ms++ // Sequence exhausted, borrow the next millisecond.
+		}
+	} else {
+		s.seq = 0
+	}
+	s.last = ms
+
+	id := ms<<(snowflakeNodeBits+snowflakeSeqBits) | s.node<<snowflakeSeqBits | s.seq
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,54 @@
+package idgen_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSnowflake_NodeOverflow_ShouldError(t *testing.T) {
+	g, err := idgen.NewSnowflake(1 << 10)
+
+	assert.Nil(t, g)
+	assert.Error(t, err)
+}
+
+func TestSnowflake_SameMillisecond_ShouldIncrementSequence(t *testing.T) {
+	now := time.Now()
+	g := idgen.NewSnowflakeAt(3, func() time.Time { return now })
+
+	first, _ := strconv.ParseInt(g(), 10, 64)
+	second, _ := strconv.ParseInt(g(), 10, 64)
+
+	assert.Equal(t, first+1, second)
+	assert.Equal(t, int64(3), first>>12&(1<<10-1))
+}
+
+func TestSnowflake_ClockBackwards_ShouldStayMonotonic(t *testing.T) {
+	now := time.Now()
+	g := idgen.NewSnowflakeAt(0, func() time.Time { return now })
+
+	first, _ := strconv.ParseInt(g(), 10, 64)
+	now = now.Add(-time.Second)
+	second, _ := strconv.ParseInt(g(), 10, 64)
+
+	assert.Greater(t, second, first)
+}
+
+func TestSnowflake_SequenceExhausted_ShouldBorrowNextMillisecond(t *testing.T) {
+	now := time.Now()
+	g := idgen.NewSnowflakeAt(0, func() time.Time { return now })
+
+	first, err := strconv.ParseInt(g(), 10, 64)
+	require.NoError(t, err)
+	var last int64
+	for range 1 << 12 {
+		last, _ = strconv.ParseInt(g(), 10, 64)
+	}
+
+	assert.Equal(t, first>>22+1, last>>22)
+}
@@ -0,0 +1,54 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	ulidTimeLen       = 6
+	ulidRandLen       = 10
+	ulidEncodedLen    = 26
+)
+
+// ULID generates lexicographically sortable identifier.
+// See https://github.com/ulid/spec for the format.
+func ULID() string {
+	return ulidAt(time.Now())
+}
+
+func ulidAt(t time.Time) string {
+	var id [ulidTimeLen + ulidRandLen]byte
+	ms := uint64(t.UnixMilli()) //nolint:gosec // time before epoch is not expected
+	for i := range ulidTimeLen {
+		id[ulidTimeLen-1-i] = byte(ms >> (8 * i))
+	}
+	_, _ = rand.Read(id[ulidTimeLen:])
+
+	return encodeCrockford(id)
+}
+
+// encodeCrockford encodes 128 bits into 26 base32 symbols, 5 bits each, with 2 leading zero bits.
+func encodeCrockford(id [ulidTimeLen + ulidRandLen]byte) string {
+	const bits = 5
+	var out [ulidEncodedLen]byte
+	var acc uint32
+	var accBits uint
+	pos := ulidEncodedLen - 1
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << accBits
+		accBits += 8
+		for accBits >= bits {
+			out[pos] = crockfordAlphabet[acc&0x1f]
+			pos--
+			acc >>= bits
+			accBits -= bits
+		}
+	}
+	for ; pos >= 0; pos-- {
+		out[pos] = crockfordAlphabet[acc&0x1f]
+		acc >>= bits
+	}
+	return string(out[:])
+}
@@ -0,0 +1,32 @@
+package idgen_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULID_ShouldBeCrockfordBase32(t *testing.T) {
+	id := idgen.ULID()
+
+	assert.Len(t, id, 26)
+	assert.Empty(t, strings.Trim(id, "0123456789ABCDEFGHJKMNPQRSTVWXYZ"))
+}
+
+func TestULIDAt_ShouldEncodeTimestamp(t *testing.T) {
+	id := idgen.ULIDAt(time.UnixMilli(1469918176385))
+
+	assert.Equal(t, "01ARYZ6S41", id[:10])
+}
+
+func TestULIDAt_ShouldBeSortable(t *testing.T) {
+	now := time.Now()
+
+	first := idgen.ULIDAt(now)
+	second := idgen.ULIDAt(now.Add(time.Millisecond))
+
+	assert.Less(t, first, second)
+}
@@ -0,0 +1,8 @@
+package loadshed
+
+import "time"
+
+// SetClock replaces clock of the limiter for testing purposes.
+func (l *Limiter) SetClock(now func() time.Time) {
+	l.now = now
+}
@@ -0,0 +1,144 @@
+package loadshed
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// backoff is a multiplicative decrease of the limit once latency exceeds the target.
+	backoff = 0.9
+	// saturationCooldown keeps limiter saturated for a while after the last shed request.
+	saturationCooldown = time.Second
+)
+
+// Config of the adaptive concurrency limiter.
+type Config struct {
+	MinLimit int           // lower bound of the adaptive limit
+	MaxLimit int           // upper bound and the initial value of the adaptive limit
+	Target   time.Duration // latency target, slower requests decrease the limit
+	MaxWait  time.Duration // maximal time a request waits in the queue for a slot
+	MaxQueue int           // maximal number of waiting requests
+}
+
+// Stats is a snapshot of the limiter state.
+type Stats struct {
+	Limit    int
+	InFlight int
+	Queued   int
+	Shed     uint64
+}
+
+// Limiter caps number of in-flight requests with the limit adapted by AIMD algorithm:
+// the limit grows additively while latency stays under the target
+// and decreases multiplicatively once it is exceeded.
+// Requests over the limit wait in a bounded queue and are shed once they wait too long.
+type Limiter struct {
+	mu       sync.Mutex
+	cfg      Config
+	limit    float64
+	inFlight int
+	waiters  []chan struct{}
+	shed     uint64
+	lastShed time.Time
+	now      func() time.Time
+}
+
+// New creates adaptive concurrency limiter.
+func New(cfg Config) *Limiter {
+	cfg.MinLimit = max(cfg.MinLimit, 1)
+	cfg.MaxLimit = max(cfg.MaxLimit, cfg.MinLimit)
+	return &Limiter{cfg: cfg, limit: float64(cfg.MaxLimit), now: time.Now}
+}
+
+// Acquire takes a slot for a request waiting in the queue if needed.
+// Returns release function to be called once the request is completed.
+// Reports false if the request is shed.
+func (l *Limiter) Acquire(ctx context.Context) (func(), bool) {
+	l.mu.Lock()
+	if l.inFlight < l.current() && len(l.waiters) == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaser(), true
+	}
+	if len(l.waiters) >= l.cfg.MaxQueue || l.cfg.MaxWait <= 0 {
+		l.shedLocked()
+		l.mu.Unlock()
+		return nil, false
+	}
+	granted := make(chan struct{})
+	l.waiters = append(l.waiters, granted)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.cfg.MaxWait)
+	defer timer.Stop()
+	select {
+	case <-granted:
+		return l.releaser(), true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, w := range l.waiters {
+		if w == granted {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			l.shedLocked()
+			return nil, false
+		}
+	}
+	// Slot was granted concurrently with the timeout.
+	return l.releaser(), true
+}
+
+// Saturated reports whether requests have been shed recently.
+func (l *Limiter) Saturated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.shed > 0 && l.now().Sub(l.lastShed) < saturationCooldown
+}
+
+// Stats returns snapshot of the limiter state.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{Limit: l.current(), InFlight: l.inFlight, Queued: len(l.waiters), Shed: l.shed}
+}
+
+func (l *Limiter) releaser() func() {
+	start := l.now()
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.release(l.now().Sub(start)) })
+	}
+}
+
+func (l *Limiter) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if latency > l.cfg.Target {
+		l.limit = math.Max(float64(l.cfg.MinLimit), l.limit*backoff)
+	} else {
+		l.limit = math.Min(float64(l.cfg.MaxLimit), l.limit+1/l.limit)
+	}
+
+	for len(l.waiters) > 0 && l.inFlight < l.current() {
+		l.inFlight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+func (l *Limiter) shedLocked() {
+	l.shed++
+	l.lastShed = l.now()
+}
+
+func (l *Limiter) current() int {
+	return int(l.limit)
+}
@@ -0,0 +1,139 @@
+package loadshed_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Acquire_UnderLimit_ShouldAcquire(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 2, Target: time.Second})
+
+	release, ok := l.Acquire(context.Background())
+
+	require.True(t, ok)
+	assert.Equal(t, 1, l.Stats().InFlight)
+	release()
+	release()
+	assert.Equal(t, 0, l.Stats().InFlight, "Release should be idempotent")
+}
+
+func TestLimiter_Acquire_OverLimitNoQueue_ShouldShed(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second})
+	_, _ = l.Acquire(context.Background())
+
+	release, ok := l.Acquire(context.Background())
+
+	assert.False(t, ok)
+	assert.Nil(t, release)
+	assert.True(t, l.Saturated())
+	assert.Equal(t, uint64(1), l.Stats().Shed)
+}
+
+func TestLimiter_Acquire_Queued_ShouldWaitForRelease(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second, MaxWait: time.Second, MaxQueue: 1})
+	release, _ := l.Acquire(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var ok bool
+	go func() {
+		defer wg.Done()
+		var second func()
+		second, ok = l.Acquire(context.Background())
+		second()
+	}()
+
+	require.Eventually(t, func() bool { return l.Stats().Queued == 1 }, time.Second, time.Millisecond)
+	release()
+	wg.Wait()
+
+	assert.True(t, ok)
+	assert.Equal(t, loadshed.Stats{Limit: 1}, l.Stats())
+}
+
+func TestLimiter_Acquire_QueueFull_ShouldShed(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second, MaxWait: time.Second, MaxQueue: 1})
+	release, _ := l.Acquire(context.Background())
+	defer release()
+	go func() { _, _ = l.Acquire(context.Background()) }()
+	require.Eventually(t, func() bool { return l.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+	_, ok := l.Acquire(context.Background())
+
+	assert.False(t, ok)
+}
+
+func TestLimiter_Acquire_WaitTimeout_ShouldShed(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second, MaxWait: time.Millisecond, MaxQueue: 1})
+	release, _ := l.Acquire(context.Background())
+	defer release()
+
+	_, ok := l.Acquire(context.Background())
+
+	assert.False(t, ok)
+	assert.Equal(t, 0, l.Stats().Queued)
+}
+
+func TestLimiter_Acquire_CanceledContext_ShouldShed(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second, MaxWait: time.Hour, MaxQueue: 1})
+	release, _ := l.Acquire(context.Background())
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok := l.Acquire(ctx)
+
+	assert.False(t, ok)
+}
+
+func TestLimiter_Release_SlowRequest_ShouldDecreaseLimit(t *testing.T) {
+	now := time.Now()
+	l := loadshed.New(loadshed.Config{MinLimit: 5, MaxLimit: 10, Target: time.Second})
+	l.SetClock(func() time.Time { return now })
+
+	release, _ := l.Acquire(context.Background())
+	now = now.Add(2 * time.Second)
+	release()
+	for range 20 {
+		release, _ = l.Acquire(context.Background())
+		now = now.Add(2 * time.Second)
+		release()
+	}
+
+	assert.Equal(t, 5, l.Stats().Limit)
+}
+
+func TestLimiter_Release_FastRequests_ShouldRecoverLimit(t *testing.T) {
+	now := time.Now()
+	l := loadshed.New(loadshed.Config{MinLimit: 1, MaxLimit: 10, Target: time.Second})
+	l.SetClock(func() time.Time { return now })
+	release, _ := l.Acquire(context.Background())
+	now = now.Add(2 * time.Second)
+	release()
+	decreased := l.Stats().Limit
+
+	for range 100 {
+		release, _ = l.Acquire(context.Background())
+		release()
+	}
+
+	assert.Equal(t, 9, decreased)
+	assert.Equal(t, 10, l.Stats().Limit)
+}
+
+func TestLimiter_Saturated_AfterCooldown_ShouldRecover(t *testing.T) {
+	now := time.Now()
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second})
+	l.SetClock(func() time.Time { return now })
+	_, _ = l.Acquire(context.Background())
+	_, _ = l.Acquire(context.Background())
+
+	now = now.Add(time.Minute)
+
+	assert.False(t, l.Saturated())
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextExtension is an additional attribute extracted from the record context.
+type ContextExtension func(ctx context.Context) (slog.Attr, bool)
+
+type contextHandler struct {
+	h    slog.Handler
+	e    []ContextExtension
+	keys map[string]bool // keys added with WithAttrs into the innermost group
+}
+
+// NewContextHandler wraps handler enriching records logged with a context
+// by attributes of the extensions.
+// Attributes already added with the same key are never duplicated.
+// Attributes are added into the innermost group opened with slog.Logger.WithGroup.
+func NewContextHandler(h slog.Handler, extensions ...ContextExtension) slog.Handler {
+	return &contextHandler{h: h, e: extensions}
+}
+
+// Enabled implements slog.Handler interface.
+func (c *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return c.h.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler interface.
+func (c *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil || len(c.e) == 0 {
+		return c.h.Handle(ctx, r)
+	}
+
+	present := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		present[a.Key] = true
+		return true
+	})
+
+	for _, ext := range c.e {
+		if ext == nil {
+			continue
+		}
+		a, ok := ext(ctx)
+		if !ok || c.keys[a.Key] || present[a.Key] {
+			continue
+		}
+		present[a.Key] = true
+		r.AddAttrs(a)
+	}
+	return c.h.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler interface.
+func (c *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keys := make(map[string]bool, len(c.keys)+len(attrs))
+	for k := range c.keys {
+		keys[k] = true
+	}
+	for _, a := range attrs {
+		keys[a.Key] = true
+	}
+	return &contextHandler{h: c.h.WithAttrs(attrs), e: c.e, keys: keys}
+}
+
+// WithGroup implements slog.Handler interface.
+func (c *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return c
+	}
+	return &contextHandler{h: c.h.WithGroup(name), e: c.e}
+}
+
+// RequestID context extension for log request id.
+func RequestID(ctx context.Context) (slog.Attr, bool) {
+	return stringAttr("request-id", middleware.RequestIDFrom(ctx))
+}
+
+// TraceID context extension for log trace id of the current span.
+func TraceID(ctx context.Context) (slog.Attr, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return slog.Attr{}, false
+	}
+	return slog.String("trace-id", sc.TraceID().String()), true
+}
+
+// SpanID context extension for log span id of the current span.
+func SpanID(ctx context.Context) (slog.Attr, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return slog.Attr{}, false
+	}
+	return slog.String("span-id", sc.SpanID().String()), true
+}
+
+// UserID context extension for log authenticated user id.
+func UserID(ctx context.Context) (slog.Attr, bool) {
+	return stringAttr("user-id", middleware.UserIDFrom(ctx))
+}
+
+// TenantID context extension for log tenant id.
+func TenantID(ctx context.Context) (slog.Attr, bool) {
+	return stringAttr("tenant-id", middleware.TenantIDFrom(ctx))
+}
+
+func stringAttr(key, val string) (slog.Attr, bool) {
+	if val == "" {
+		return slog.Attr{}, false
+	}
+	return slog.String(key, val), true
+}
@@ -0,0 +1,112 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newContextLogger(buf *bytes.Buffer, extensions ...logging.ContextExtension) *slog.Logger {
+	return slog.New(logging.NewContextHandler(slog.NewJSONHandler(buf, nil), extensions...))
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	out := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	return out
+}
+
+func identityContext() context.Context {
+	ctx := middleware.WithRequestID(context.Background(), "")
+	ctx = middleware.WithUserID(ctx, "user")
+	ctx = middleware.WithTenantID(ctx, "tenant")
+	return ctx
+}
+
+func TestContextHandler_Handle_ShouldAddContextAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, logging.RequestID, logging.UserID, logging.TenantID)
+	ctx := identityContext()
+
+	l.InfoContext(ctx, "msg")
+
+	out := decode(t, buf)
+	assert.Equal(t, middleware.RequestIDFrom(ctx), out["request-id"])
+	assert.Equal(t, "user", out["user-id"])
+	assert.Equal(t, "tenant", out["tenant-id"])
+}
+
+func TestContextHandler_Handle_EmptyContext_ShouldNotAddAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, logging.RequestID, logging.UserID, logging.TenantID, logging.TraceID, logging.SpanID)
+
+	l.InfoContext(context.Background(), "msg")
+
+	out := decode(t, buf)
+	assert.Len(t, out, 3, "Should contain only time, level and msg")
+}
+
+func TestContextHandler_Handle_ShouldAddTraceAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, logging.TraceID, logging.SpanID)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	l.InfoContext(ctx, "msg")
+
+	out := decode(t, buf)
+	assert.Equal(t, sc.TraceID().String(), out["trace-id"])
+	assert.Equal(t, sc.SpanID().String(), out["span-id"])
+}
+
+func TestContextHandler_WithAttrs_ShouldNotDuplicateKeys(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, logging.RequestID, logging.UserID).With("request-id", "explicit")
+
+	l.InfoContext(identityContext(), "msg", "user-id", "record")
+
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"request-id"`)))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"user-id"`)))
+	out := decode(t, buf)
+	assert.Equal(t, "explicit", out["request-id"])
+	assert.Equal(t, "record", out["user-id"])
+}
+
+func TestContextHandler_WithGroup_ShouldAddAttrsIntoGroup(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, logging.UserID).With("user-id", "outer").WithGroup("g")
+
+	l.InfoContext(identityContext(), "msg")
+
+	out := decode(t, buf)
+	assert.Equal(t, "outer", out["user-id"])
+	assert.Equal(t, map[string]any{"user-id": "user"}, out["g"])
+}
+
+func TestContextHandler_NilExtension_ShouldNotPanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newContextLogger(buf, nil)
+
+	assert.NotPanics(t, func() {
+		l.InfoContext(identityContext(), "msg")
+	})
+}
+
+func TestContextHandler_Enabled_ShouldDelegate(t *testing.T) {
+	h := logging.NewContextHandler(slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	assert.False(t, h.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelError))
+}
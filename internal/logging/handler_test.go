@@ -0,0 +1,43 @@
+package logging_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler_Formats_ShouldWrite(t *testing.T) {
+	for format, expected := range map[string]string{
+		logging.FormatJSON:   `"msg":"hello"`,
+		logging.FormatText:   `msg=hello`,
+		logging.FormatPretty: `hello`,
+	} {
+		buf := &bytes.Buffer{}
+		h, err := logging.NewHandler(buf, format, slog.LevelInfo)
+		require.NoError(t, err, format)
+
+		slog.New(h).Info("hello")
+
+		assert.Contains(t, buf.String(), expected, format)
+	}
+}
+
+func TestNewHandler_Level_ShouldFilter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h, _ := logging.NewHandler(buf, logging.FormatJSON, slog.LevelWarn)
+
+	slog.New(h).Info("hello")
+
+	assert.Empty(t, buf.String())
+}
+
+func TestNewHandler_UnknownFormat_ShouldError(t *testing.T) {
+	h, err := logging.NewHandler(&bytes.Buffer{}, "xml", slog.LevelInfo)
+
+	assert.Nil(t, h)
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PackageKey is an attribute key naming the package of a logger.
+const PackageKey = "package"
+
+// ForPackage returns logger attributed with the package name
+// to be controlled by the package level overrides.
+func ForPackage(l *slog.Logger, name string) *slog.Logger {
+	return l.With(PackageKey, name)
+}
+
+// LevelsState is a snapshot of runtime log levels.
+type LevelsState struct {
+	Level    slog.Level            `json:"level"`
+	Packages map[string]slog.Level `json:"packages"`
+	Routes   map[string]slog.Level `json:"routes"`
+}
+
+// Levels controls minimal log level at runtime.
+// Base level could be overridden per package and per chi route pattern,
+// route overrides take precedence over package overrides.
+type Levels struct {
+	base     slog.LevelVar
+	initial  slog.Level
+	mu       sync.RWMutex
+	packages map[string]slog.Level
+	routes   map[string]slog.Level
+}
+
+// NewLevels creates levels control with the initial base level.
+func NewLevels(level slog.Level) *Levels {
+	lv := &Levels{initial: level}
+	lv.base.Set(level)
+	return lv
+}
+
+// Level returns current base level and implements slog.Leveler interface.
+func (lv *Levels) Level() slog.Level {
+	return lv.base.Level()
+}
+
+// SetLevel sets base level.
+func (lv *Levels) SetLevel(level slog.Level) {
+	lv.base.Set(level)
+}
+
+// ToggleDebug switches base level between debug and the initial one.
+// Returns the new base level.
+func (lv *Levels) ToggleDebug() slog.Level {
+	level := slog.LevelDebug
+	if lv.base.Level() == slog.LevelDebug {
+		level = lv.initial
+	}
+	lv.base.Set(level)
+	return level
+}
+
+// SetPackages replaces package level overrides.
+func (lv *Levels) SetPackages(levels map[string]slog.Level) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.packages = maps.Clone(levels)
+}
+
+// SetRoutes replaces chi route pattern level overrides.
+func (lv *Levels) SetRoutes(levels map[string]slog.Level) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.routes = maps.Clone(levels)
+}
+
+// State returns snapshot of current levels.
+func (lv *Levels) State() LevelsState {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+	return LevelsState{
+		Level:    lv.base.Level(),
+		Packages: maps.Clone(lv.packages),
+		Routes:   maps.Clone(lv.routes),
+	}
+}
+
+// enabled reports whether level is enabled for the package and route of the context.
+func (lv *Levels) enabled(ctx context.Context, pkg string, level slog.Level) bool {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+
+	if len(lv.routes) != 0 && ctx != nil {
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			if minLevel, ok := lv.routes[rctx.RoutePattern()]; ok {
+				return level >= minLevel
+			}
+		}
+	}
+	if minLevel, ok := lv.packages[pkg]; ok && pkg != "" {
+		return level >= minLevel
+	}
+	return level >= lv.base.Level()
+}
+
+// ParseLevels parses level names of the overrides.
+func ParseLevels(names map[string]string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level, len(names))
+	for key, name := range names {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("parsing level of %q: %w", key, err)
+		}
+		levels[key] = level
+	}
+	return levels, nil
+}
+
+type levelHandler struct {
+	h   slog.Handler
+	lv  *Levels
+	pkg string
+}
+
+// NewLevelHandler wraps handler filtering records by the runtime levels.
+// Wrapped handler level is ignored.
+func NewLevelHandler(h slog.Handler, lv *Levels) slog.Handler {
+	return &levelHandler{h: h, lv: lv}
+}
+
+// Enabled implements slog.Handler interface.
+func (l *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.lv.enabled(ctx, l.pkg, level)
+}
+
+// Handle implements slog.Handler interface.
+func (l *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return l.h.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler interface.
+func (l *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	pkg := l.pkg
+	for _, a := range attrs {
+		if a.Key == PackageKey {
+			pkg = a.Value.String()
+		}
+	}
+	return &levelHandler{h: l.h.WithAttrs(attrs), lv: l.lv, pkg: pkg}
+}
+
+// WithGroup implements slog.Handler interface.
+func (l *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{h: l.h.WithGroup(name), lv: l.lv, pkg: l.pkg}
+}
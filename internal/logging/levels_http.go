@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// levelsPatch is a partial update of runtime log levels.
+// Nil fields are kept unchanged, empty maps reset the overrides.
+type levelsPatch struct {
+	Level    *slog.Level           `json:"level"`
+	Packages map[string]slog.Level `json:"packages"`
+	Routes   map[string]slog.Level `json:"routes"`
+}
+
+// LevelsHandler serves runtime log levels.
+// GET responds with the current state, PUT applies partial update and responds with the new state.
+func LevelsHandler(lv *Levels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var p levelsPatch
+			if err := render.DecodeJSON(r.Body, &p); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if p.Level != nil {
+				lv.SetLevel(*p.Level)
+			}
+			if p.Packages != nil {
+				lv.SetPackages(p.Packages)
+			}
+			if p.Routes != nil {
+				lv.SetRoutes(p.Routes)
+			}
+		default:
+			w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		render.JSON(w, r, lv.State())
+	})
+}
@@ -0,0 +1,59 @@
+package logging_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelsHandler_Get_ShouldRespondState(t *testing.T) {
+	lv := logging.NewLevels(slog.LevelWarn)
+	rec := httptest.NewRecorder()
+
+	logging.LevelsHandler(lv).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var state logging.LevelsState
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&state))
+	assert.Equal(t, slog.LevelWarn, state.Level)
+}
+
+func TestLevelsHandler_Put_ShouldApplyPatch(t *testing.T) {
+	lv := logging.NewLevels(slog.LevelInfo)
+	lv.SetRoutes(map[string]slog.Level{"/": slog.LevelDebug})
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"level":"DEBUG","packages":{"task":"ERROR"}}`)
+
+	logging.LevelsHandler(lv).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", body))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	state := lv.State()
+	assert.Equal(t, slog.LevelDebug, state.Level)
+	assert.Equal(t, map[string]slog.Level{"task": slog.LevelError}, state.Packages)
+	assert.Equal(t, map[string]slog.Level{"/": slog.LevelDebug}, state.Routes)
+}
+
+func TestLevelsHandler_Put_BadBody_ShouldBadRequest(t *testing.T) {
+	lv := logging.NewLevels(slog.LevelInfo)
+	rec := httptest.NewRecorder()
+
+	logging.LevelsHandler(lv).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"LOUD"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, slog.LevelInfo, lv.Level())
+}
+
+func TestLevelsHandler_Post_ShouldNotAllow(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	logging.LevelsHandler(logging.NewLevels(slog.LevelInfo)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
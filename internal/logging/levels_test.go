@@ -0,0 +1,115 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLevelsLogger(buf *bytes.Buffer, lv *logging.Levels) *slog.Logger {
+	return slog.New(logging.NewLevelHandler(slog.NewTextHandler(buf, nil), lv))
+}
+
+func TestLevels_SetLevel_ShouldFilterAtRuntime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lv := logging.NewLevels(slog.LevelInfo)
+	l := newLevelsLogger(buf, lv)
+
+	l.Debug("first")
+	lv.SetLevel(slog.LevelDebug)
+	l.Debug("second")
+
+	assert.NotContains(t, buf.String(), "first")
+	assert.Contains(t, buf.String(), "second")
+}
+
+func TestLevels_ToggleDebug_ShouldSwitchBack(t *testing.T) {
+	lv := logging.NewLevels(slog.LevelWarn)
+
+	first := lv.ToggleDebug()
+	second := lv.ToggleDebug()
+
+	assert.Equal(t, slog.LevelDebug, first)
+	assert.Equal(t, slog.LevelWarn, second)
+	assert.Equal(t, slog.LevelWarn, lv.Level())
+}
+
+func TestLevels_Package_ShouldOverrideBase(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lv := logging.NewLevels(slog.LevelInfo)
+	lv.SetPackages(map[string]slog.Level{"task": slog.LevelDebug, "board": slog.LevelError})
+	l := newLevelsLogger(buf, lv)
+
+	logging.ForPackage(l, "task").Debug("task debug")
+	logging.ForPackage(l, "board").Warn("board warn")
+	l.Debug("base debug")
+
+	assert.Contains(t, buf.String(), "task debug")
+	assert.NotContains(t, buf.String(), "board warn")
+	assert.NotContains(t, buf.String(), "base debug")
+}
+
+func TestLevels_Route_ShouldOverridePackage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lv := logging.NewLevels(slog.LevelInfo)
+	lv.SetPackages(map[string]slog.Level{"task": slog.LevelError})
+	lv.SetRoutes(map[string]slog.Level{"/tasks/{id}": slog.LevelDebug})
+	l := logging.ForPackage(newLevelsLogger(buf, lv), "task")
+	router := chi.NewRouter()
+	router.Get("/tasks/{id}", func(_ http.ResponseWriter, r *http.Request) {
+		l.DebugContext(r.Context(), "route debug")
+	})
+	router.Get("/tasks", func(_ http.ResponseWriter, r *http.Request) {
+		l.WarnContext(r.Context(), "other warn")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks", nil))
+
+	assert.Contains(t, buf.String(), "route debug")
+	assert.NotContains(t, buf.String(), "other warn")
+}
+
+func TestLevels_WithGroup_ShouldKeepPackage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lv := logging.NewLevels(slog.LevelInfo)
+	lv.SetPackages(map[string]slog.Level{"task": slog.LevelDebug})
+
+	logging.ForPackage(newLevelsLogger(buf, lv), "task").WithGroup("g").DebugContext(context.Background(), "debug")
+
+	assert.Contains(t, buf.String(), "debug")
+}
+
+func TestLevels_State_ShouldSnapshot(t *testing.T) {
+	lv := logging.NewLevels(slog.LevelInfo)
+	packages := map[string]slog.Level{"task": slog.LevelDebug}
+	lv.SetPackages(packages)
+
+	state := lv.State()
+	packages["board"] = slog.LevelWarn
+
+	assert.Equal(t, slog.LevelInfo, state.Level)
+	assert.Equal(t, map[string]slog.Level{"task": slog.LevelDebug}, state.Packages)
+	assert.Empty(t, state.Routes)
+}
+
+func TestParseLevels_ShouldParseNames(t *testing.T) {
+	levels, err := logging.ParseLevels(map[string]string{"task": "debug", "board": "WARN"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]slog.Level{"task": slog.LevelDebug, "board": slog.LevelWarn}, levels)
+}
+
+func TestParseLevels_Unknown_ShouldError(t *testing.T) {
+	_, err := logging.ParseLevels(map[string]string{"task": "verbose"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+type prettyHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	opts   slog.HandlerOptions
+	attrs  []byte
+	prefix string
+}
+
+// NewPrettyHandler creates human-readable colored log handler for development.
+// Record is written in a single line: time, level, message and key=value attributes.
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	h := &prettyHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler interface.
+func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler interface.
+func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+	buf := &bytes.Buffer{}
+	if !r.Time.IsZero() {
+		buf.WriteString(colorGray + r.Time.Format(time.TimeOnly+".000") + colorReset + " ")
+	}
+	buf.WriteString(levelColor(r.Level) + fmt.Sprintf("%-5s", r.Level.String()) + colorReset + " ")
+	buf.WriteString(r.Message)
+	buf.Write(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(buf, h.prefix, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs implements slog.Handler interface.
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	buf := bytes.NewBuffer(bytes.Clone(h.attrs))
+	for _, a := range attrs {
+		appendAttr(buf, h.prefix, a)
+	}
+	c := *h
+	c.attrs = buf.Bytes()
+	return &c
+}
+
+// WithGroup implements slog.Handler interface.
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+func appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(buf, prefix, ga)
+		}
+		return
+	}
+	fmt.Fprintf(buf, " %s%s%s=%s", colorCyan, prefix+a.Key, colorReset, a.Value.String())
+}
+
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
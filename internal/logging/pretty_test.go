@@ -0,0 +1,57 @@
+package logging_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyHandler_Handle_ShouldWriteSingleLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := slog.New(logging.NewPrettyHandler(buf, nil))
+
+	l.Warn("hello", "key", "value")
+
+	line := buf.String()
+	assert.True(t, strings.HasSuffix(line, "\n"))
+	assert.Equal(t, 1, strings.Count(line, "\n"))
+	assert.Contains(t, line, "WARN")
+	assert.Contains(t, line, "hello")
+	assert.Contains(t, line, "key\033[0m=value")
+}
+
+func TestPrettyHandler_WithGroup_ShouldQualifyKeys(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := slog.New(logging.NewPrettyHandler(buf, nil))
+
+	l.With("a", 1).WithGroup("g").Info("hello", slog.Group("h", "b", 2), "c", 3)
+
+	line := buf.String()
+	assert.Contains(t, line, "a\033[0m=1")
+	assert.Contains(t, line, "g.h.b\033[0m=2")
+	assert.Contains(t, line, "g.c\033[0m=3")
+}
+
+func TestPrettyHandler_Enabled_ShouldRespectLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := slog.New(logging.NewPrettyHandler(buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	l.Warn("hello")
+
+	assert.Empty(t, buf.String())
+}
+
+func TestPrettyHandler_DefaultLevel_ShouldBeInfo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := slog.New(logging.NewPrettyHandler(buf, nil))
+
+	l.Debug("debug")
+	l.Info("info")
+
+	assert.NotContains(t, buf.String(), "debug")
+	assert.Contains(t, buf.String(), "info")
+}
@@ -0,0 +1,33 @@
+package maintenance
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/render"
+)
+
+// Handler serves maintenance state.
+// GET responds with the current state, PUT replaces the state, logs the change and responds with the new state.
+func Handler(s *Switch, l *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var state State
+			if err := decode.JSON(r, &state); err != nil {
+				problem.Write(w, r, decode.Problem(err))
+				return
+			}
+			s.Set(state)
+			l.WarnContext(r.Context(), "Maintenance mode switched to "+string(s.State().Mode))
+		default:
+			w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		render.JSON(w, r, s.State())
+	})
+}
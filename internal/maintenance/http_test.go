@@ -0,0 +1,53 @@
+package maintenance_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveSwitch(s *maintenance.Switch, method, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/maintenance", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	maintenance.Handler(s, slog.New(slog.NewTextHandler(io.Discard, nil))).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Get_ShouldServeState(t *testing.T) {
+	s := maintenance.NewSwitch(maintenance.State{Mode: maintenance.ModeReadOnly})
+
+	rec := serveSwitch(s, http.MethodGet, "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"mode":"read-only"}`, rec.Body.String())
+}
+
+func TestHandler_Put_ShouldSwitchMode(t *testing.T) {
+	s := maintenance.NewSwitch(maintenance.State{})
+
+	rec := serveSwitch(s, http.MethodPut, `{"mode":"maintenance","message":"migration"}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, maintenance.State{Mode: maintenance.ModeMaintenance, Message: "migration"}, s.State())
+}
+
+func TestHandler_PutUnknownMode_ShouldBadRequest(t *testing.T) {
+	s := maintenance.NewSwitch(maintenance.State{})
+
+	rec := serveSwitch(s, http.MethodPut, `{"mode":"readonly"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, maintenance.ModeOff, s.State().Mode)
+}
+
+func TestHandler_Delete_ShouldMethodNotAllowed(t *testing.T) {
+	rec := serveSwitch(maintenance.NewSwitch(maintenance.State{}), http.MethodDelete, "")
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,79 @@
+package maintenance
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Mode of the service availability.
+type Mode string
+
+// Supported modes.
+const (
+	// ModeOff serves all requests.
+	ModeOff Mode = "off"
+	// ModeReadOnly rejects mutating requests while reads keep working.
+	ModeReadOnly Mode = "read-only"
+	// ModeMaintenance rejects all requests except health and administrative ones.
+	ModeMaintenance Mode = "maintenance"
+)
+
+// ParseMode parses mode by its name, empty name means ModeOff.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeReadOnly, ModeMaintenance:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown maintenance mode %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (m *Mode) UnmarshalText(text []byte) error {
+	parsed, err := ParseMode(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// State of the maintenance switch.
+// Message explains the reason to clients, e.g. "data migration till 18:00 UTC".
+type State struct {
+	Mode    Mode   `json:"mode"`
+	Message string `json:"message,omitempty"`
+}
+
+// Switch holds maintenance state changed at runtime.
+type Switch struct {
+	mu    sync.RWMutex
+	state State
+}
+
+// NewSwitch creates switch in the initial state.
+func NewSwitch(state State) *Switch {
+	if state.Mode == "" {
+		state.Mode = ModeOff
+	}
+	return &Switch{state: state}
+}
+
+// State returns the current state.
+func (s *Switch) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Set replaces the current state.
+func (s *Switch) Set(state State) {
+	if state.Mode == "" {
+		state.Mode = ModeOff
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
@@ -0,0 +1,36 @@
+package maintenance_test
+
+import (
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	cases := map[string]maintenance.Mode{
+		"":            maintenance.ModeOff,
+		"off":         maintenance.ModeOff,
+		"read-only":   maintenance.ModeReadOnly,
+		"maintenance": maintenance.ModeMaintenance,
+	}
+	for s, want := range cases {
+		m, err := maintenance.ParseMode(s)
+
+		require.NoError(t, err, s)
+		assert.Equal(t, want, m, s)
+	}
+
+	_, err := maintenance.ParseMode("readonly")
+	assert.Error(t, err)
+}
+
+func TestSwitch_Set_ShouldReplaceState(t *testing.T) {
+	s := maintenance.NewSwitch(maintenance.State{})
+	assert.Equal(t, maintenance.ModeOff, s.State().Mode)
+
+	s.Set(maintenance.State{Mode: maintenance.ModeReadOnly, Message: "migration"})
+
+	assert.Equal(t, maintenance.State{Mode: maintenance.ModeReadOnly, Message: "migration"}, s.State())
+}
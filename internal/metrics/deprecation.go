@@ -0,0 +1,31 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Deprecations counts requests served by deprecated API routes labeled by version and route pattern.
+// Clients are not used as labels to keep cardinality bounded, they are logged instead.
+type Deprecations struct {
+	requests *prometheus.CounterVec
+}
+
+// NewDeprecations creates and registers deprecated API usage metrics in the namespace.
+func NewDeprecations(reg prometheus.Registerer, namespace string) *Deprecations {
+	m := &Deprecations{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "deprecated_requests_total",
+			Help:      "Total number of requests served by deprecated API routes.",
+		}, []string{"version", "route"}),
+	}
+	reg.MustRegister(m.requests)
+	return m
+}
+
+// Observe counts a request served by the deprecated route.
+func (m *Deprecations) Observe(version, route string) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	m.requests.WithLabelValues(version, route).Inc()
+}
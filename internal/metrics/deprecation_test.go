@@ -0,0 +1,28 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecations_Observe_ShouldCountByVersionAndRoute(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewDeprecations(reg, "test")
+
+	m.Observe("v1", "/api/v1/tasks")
+	m.Observe("v1", "/api/v1/tasks")
+	m.Observe("v1", "")
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_api_deprecated_requests_total Total number of requests served by deprecated API routes.
+# TYPE test_api_deprecated_requests_total counter
+test_api_deprecated_requests_total{route="/api/v1/tasks",version="v1"} 2
+test_api_deprecated_requests_total{route="unmatched",version="v1"} 1
+`))
+	require.NoError(t, err)
+}
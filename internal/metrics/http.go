@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UnmatchedRoute is a route label of requests not matched by any route pattern.
+const UnmatchedRoute = "unmatched"
+
+// HTTP collects RED metrics of the HTTP server labeled by route pattern.
+// Raw request paths are never used as labels to keep cardinality bounded.
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTP creates and registers HTTP server metrics in the namespace.
+func NewHTTP(reg prometheus.Registerer, namespace string) *HTTP {
+	labels := []string{"method", "route", "code"}
+	m := &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of handled HTTP requests.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of handled HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "response_size_bytes",
+			Help:      "Size of HTTP response bodies.",
+			Buckets:   prometheus.ExponentialBuckets(100, 10, 7),
+		}, labels),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being handled.",
+		}),
+	}
+	reg.MustRegister(m.requests, m.duration, m.size, m.inFlight)
+	return m
+}
+
+// Start marks request as in flight until returned done function is called.
+func (m *HTTP) Start() func() {
+	m.inFlight.Inc()
+	return m.inFlight.Dec
+}
+
+// Observe records handled request.
+// Empty route is recorded as UnmatchedRoute.
+func (m *HTTP) Observe(method, route string, code int, d time.Duration, size int) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	lv := []string{method, route, strconv.Itoa(code)}
+	m.requests.WithLabelValues(lv...).Inc()
+	m.duration.WithLabelValues(lv...).Observe(d.Seconds())
+	m.size.WithLabelValues(lv...).Observe(float64(size))
+}
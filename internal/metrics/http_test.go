@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Observe_ShouldCountByRoute(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewHTTP(reg, "test")
+
+	m.Observe("GET", "/tasks/{id}", 200, time.Millisecond, 10)
+	m.Observe("GET", "/tasks/{id}", 200, time.Millisecond, 10)
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_http_requests_total Total number of handled HTTP requests.
+# TYPE test_http_requests_total counter
+test_http_requests_total{code="200",method="GET",route="/tasks/{id}"} 2
+`), "test_http_requests_total")
+	require.NoError(t, err)
+}
+
+func TestHTTP_Observe_EmptyRoute_ShouldUseUnmatched(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewHTTP(reg, "test")
+
+	m.Observe("GET", "", 404, time.Millisecond, 0)
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_http_requests_total Total number of handled HTTP requests.
+# TYPE test_http_requests_total counter
+test_http_requests_total{code="404",method="GET",route="unmatched"} 1
+`), "test_http_requests_total")
+	require.NoError(t, err)
+}
+
+func TestHTTP_Start_ShouldTrackInFlight(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewHTTP(reg, "test")
+	expected := func(v string) *strings.Reader {
+		return strings.NewReader(`
+# HELP test_http_requests_in_flight Number of HTTP requests being handled.
+# TYPE test_http_requests_in_flight gauge
+test_http_requests_in_flight ` + v + "\n")
+	}
+
+	done := m.Start()
+	during := testutil.GatherAndCompare(reg, expected("1"), "test_http_requests_in_flight")
+	done()
+	after := testutil.GatherAndCompare(reg, expected("0"), "test_http_requests_in_flight")
+
+	require.NoError(t, during)
+	require.NoError(t, after)
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterLoadShed registers gauges of the concurrency limiter state and counter of shed requests
+// labeled with the route group.
+func RegisterLoadShed(reg prometheus.Registerer, namespace, group string, stats func() loadshed.Stats) {
+	labels := prometheus.Labels{"group": group}
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "load_shed",
+			Name:        "limit",
+			Help:        "Current adaptive limit of concurrent requests.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Limit) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "load_shed",
+			Name:        "in_flight",
+			Help:        "Number of requests holding a concurrency slot.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().InFlight) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "load_shed",
+			Name:        "queued",
+			Help:        "Number of requests waiting for a concurrency slot.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Queued) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "load_shed",
+			Name:        "shed_total",
+			Help:        "Total number of requests shed by the concurrency limiter.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Shed) }),
+	)
+}
@@ -0,0 +1,36 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterLoadShed_ShouldExposeLimiterState(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	metrics.RegisterLoadShed(reg, "test", "default", func() loadshed.Stats {
+		return loadshed.Stats{Limit: 8, InFlight: 5, Queued: 2, Shed: 3}
+	})
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_load_shed_in_flight Number of requests holding a concurrency slot.
+# TYPE test_load_shed_in_flight gauge
+test_load_shed_in_flight{group="default"} 5
+# HELP test_load_shed_limit Current adaptive limit of concurrent requests.
+# TYPE test_load_shed_limit gauge
+test_load_shed_limit{group="default"} 8
+# HELP test_load_shed_queued Number of requests waiting for a concurrency slot.
+# TYPE test_load_shed_queued gauge
+test_load_shed_queued{group="default"} 2
+# HELP test_load_shed_shed_total Total number of requests shed by the concurrency limiter.
+# TYPE test_load_shed_shed_total counter
+test_load_shed_shed_total{group="default"} 3
+`))
+	require.NoError(t, err)
+}
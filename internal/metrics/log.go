@@ -0,0 +1,21 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// RegisterLogSampling registers counters of sampled and dropped request log lines.
+func RegisterLogSampling(reg prometheus.Registerer, namespace string, logged, dropped func() uint64) {
+	reg.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "log",
+			Name:      "requests_logged_total",
+			Help:      "Total number of sampled request log lines.",
+		}, func() float64 { return float64(logged()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "log",
+			Name:      "requests_dropped_total",
+			Help:      "Total number of request log lines dropped by sampling.",
+		}, func() float64 { return float64(dropped()) }),
+	)
+}
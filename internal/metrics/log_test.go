@@ -0,0 +1,27 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterLogSampling_ShouldExposeCounters(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	metrics.RegisterLogSampling(reg, "test", func() uint64 { return 3 }, func() uint64 { return 7 })
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_log_requests_dropped_total Total number of request log lines dropped by sampling.
+# TYPE test_log_requests_dropped_total counter
+test_log_requests_dropped_total 7
+# HELP test_log_requests_logged_total Total number of sampled request log lines.
+# TYPE test_log_requests_logged_total counter
+test_log_requests_logged_total 3
+`))
+	require.NoError(t, err)
+}
@@ -0,0 +1,20 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ShouldServeRuntimeMetrics(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	metrics.Handler(metrics.NewRegistry()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// BearerToken middleware allows only requests authorized with the static bearer token.
+// Other requests are rejected with 401 Unauthorized.
+// Id of the verified token is injected into the request context.
+func BearerToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "valid bearer token required"))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithTokenID(r.Context(), hashToken(got))))
+		})
+	}
+}
+
+// hashToken returns id of the token never revealing the token itself.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveBearer(token, header string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	mw := middleware.BearerToken(token)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mw.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBearerToken_ValidToken_ShouldPass(t *testing.T) {
+	rec := serveBearer("secret", "Bearer secret")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestBearerToken_WrongToken_ShouldUnauthorized(t *testing.T) {
+	rec := serveBearer("secret", "Bearer guess")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+}
+
+func TestBearerToken_NoHeader_ShouldUnauthorized(t *testing.T) {
+	rec := serveBearer("secret", "")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestBearerToken_EmptyToken_ShouldRejectAll(t *testing.T) {
+	rec := serveBearer("", "Bearer ")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
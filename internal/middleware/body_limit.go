@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// BodyLimit middleware limits request body size by maxBytes.
+// Requests declaring larger Content-Length are rejected with 413 Content Too Large,
+// reading beyond the limit fails with *http.MaxBytesError.
+func BodyLimit(maxBytes int64) func(http.Handler) http.Handler {
+	detail := "request body exceeds " + strconv.FormatInt(maxBytes, 10) + " bytes"
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, detail))
+				return
+			}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimit_UnderLimit_ShouldPass(t *testing.T) {
+	var body []byte
+	h := middleware.BodyLimit(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345678")))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "12345678", string(body))
+}
+
+func TestBodyLimit_DeclaredLength_ShouldContentTooLarge(t *testing.T) {
+	var called bool
+	h := middleware.BodyLimit(8)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("123456789")))
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBodyLimit_ChunkedBody_ShouldFailReading(t *testing.T) {
+	var err error
+	h := middleware.BodyLimit(8)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, err = io.ReadAll(r.Body)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("123456789"))
+	req.ContentLength = -1
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var maxErr *http.MaxBytesError
+	require.ErrorAs(t, err, &maxErr)
+}
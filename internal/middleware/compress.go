@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/compress"
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// CompressOptions configures Compress middleware.
+// Encodings are content codings in the order of server preference.
+// Content types are media types, or type wildcards like "text/*", allowed to be compressed.
+type CompressOptions struct {
+	Encodings    []string
+	MinSize      int
+	ContentTypes []string
+}
+
+// Compress middleware compresses responses with content coding negotiated by Accept-Encoding header.
+// Responses smaller than the minimal size, of not allowed content types or already encoded are sent as is.
+// Strong ETag of a compressed response is suffixed with the content coding, e.g. "3-gzip",
+// the suffix is stripped from If-Match and If-None-Match headers of requests.
+func Compress(opts CompressOptions) func(http.Handler) http.Handler {
+	encodings := make([]string, 0, len(opts.Encodings))
+	for _, enc := range opts.Encodings {
+		if compress.Supported(enc) {
+			encodings = append(encodings, enc)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			for _, name := range []string{"If-Match", "If-None-Match"} {
+				if v := r.Header.Get(name); v != "" {
+					r.Header.Set(name, decodedTags(v, encodings))
+				}
+			}
+			enc := compress.Negotiate(r.Header.Get("Accept-Encoding"), encodings)
+			if enc == compress.Identity || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, opts: &opts, encoding: enc}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter buffers response until minimal size is reached
+// to decide whether the response should be compressed.
+type compressWriter struct {
+	http.ResponseWriter
+	opts     *CompressOptions
+	encoding string
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	enc         compress.Encoder
+	release     func()
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified || cw.Header().Get("Content-Encoding") != "" {
+		_ = cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.opts.MinSize {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush implements http.Flusher interface.
+// Flushed response is compressed regardless of its size.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		_ = cw.decide(true)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// decide writes the header and the buffered body compressed if allowed.
+func (cw *compressWriter) decide(compressible bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if compressible {
+		ct := h.Get("Content-Type")
+		if ct == "" && len(cw.buf) > 0 {
+			ct = http.DetectContentType(cw.buf)
+			h.Set("Content-Type", ct)
+		}
+		compressible = h.Get("Content-Encoding") == "" && allowedContentType(cw.opts.ContentTypes, ct)
+	}
+	if compressible {
+		enc, release, err := compress.NewEncoder(cw.encoding, cw.ResponseWriter)
+		if err == nil {
+			cw.enc, cw.release = enc, release
+			h.Set("Content-Encoding", cw.encoding)
+			if tag := h.Get("ETag"); strings.HasPrefix(tag, `"`) {
+				h.Set("ETag", strings.TrimSuffix(tag, `"`)+"-"+cw.encoding+`"`)
+			}
+			h.Del("Content-Length")
+			h.Del("Accept-Ranges")
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (cw *compressWriter) close() {
+	if !cw.wroteHeader {
+		return
+	}
+	if !cw.decided {
+		_ = cw.decide(false)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		cw.release()
+	}
+}
+
+// decodedTags strips content coding suffixes of strong entity tags of the header value.
+func decodedTags(header string, encodings []string) string {
+	tags := strings.Split(header, ",")
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		for _, enc := range encodings {
+			if trimmed, ok := strings.CutSuffix(tag, "-"+enc+`"`); ok && strings.HasPrefix(tag, `"`) {
+				tag = trimmed + `"`
+				break
+			}
+		}
+		tags[i] = tag
+	}
+	return strings.Join(tags, ", ")
+}
+
+// allowedContentType reports whether media type of the content type is allowed.
+func allowedContentType(allowed []string, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		if a == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "*"); ok && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decompress middleware transparently decodes request body with content coding of Content-Encoding header.
+// Decoded body is limited by maxSize bytes, reading beyond the limit fails with *http.MaxBytesError.
+// Requests with unsupported content coding are rejected with 415 Unsupported Media Type.
+func Decompress(maxSize int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Content-Encoding")
+			if header == "" || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var encodings []string
+			for _, enc := range strings.Split(header, ",") {
+				enc = strings.ToLower(strings.TrimSpace(enc))
+				if enc == compress.Identity {
+					continue
+				}
+				if !compress.Supported(enc) {
+					w.Header().Set("Accept-Encoding", strings.Join([]string{
+						compress.Zstd, compress.Brotli, compress.Gzip, compress.Deflate,
+					}, ", "))
+					problem.Write(w, r, problem.New(http.StatusUnsupportedMediaType, "unsupported content coding "+enc))
+					return
+				}
+				encodings = append(encodings, enc)
+			}
+
+			body := io.ReadCloser(r.Body)
+			var decoders []io.Closer
+			defer func() {
+				for _, dec := range decoders {
+					_ = dec.Close()
+				}
+			}()
+			// Codings are listed in the order they were applied, so decoding goes in reverse.
+			for i := len(encodings) - 1; i >= 0; i-- {
+				dec, err := compress.NewDecoder(encodings[i], body)
+				if err != nil {
+					problem.Write(w, r, problem.New(http.StatusBadRequest, "malformed "+encodings[i]+" request body"))
+					return
+				}
+				decoders = append(decoders, dec)
+				body = dec
+			}
+
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			r.Body = http.MaxBytesReader(w, body, maxSize)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
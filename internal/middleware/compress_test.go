@@ -0,0 +1,241 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/compress"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressOptions() middleware.CompressOptions {
+	return middleware.CompressOptions{
+		Encodings:    []string{compress.Zstd, compress.Brotli, compress.Gzip, compress.Deflate},
+		MinSize:      64,
+		ContentTypes: []string{"application/json", "text/*"},
+	}
+}
+
+func serveCompressed(h http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	rec := httptest.NewRecorder()
+	middleware.Compress(compressOptions())(h).ServeHTTP(rec, req)
+	return rec
+}
+
+func writeBody(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func decode(t *testing.T, enc string, body *bytes.Buffer) string {
+	t.Helper()
+	r, err := compress.NewDecoder(enc, body)
+	require.NoError(t, err)
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestCompress_LargeResponse_ShouldCompress(t *testing.T) {
+	body := strings.Repeat(`{"title":"task"}`, 100)
+	for _, enc := range []string{compress.Gzip, compress.Deflate, compress.Zstd, compress.Brotli} {
+		t.Run(enc, func(t *testing.T) {
+			rec := serveCompressed(writeBody("application/json; charset=utf-8", body), enc)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, enc, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Equal(t, body, decode(t, enc, rec.Body))
+		})
+	}
+}
+
+func TestCompress_StrongETag_ShouldSuffixEncoding(t *testing.T) {
+	var ifMatch, ifNoneMatch string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		ifMatch, ifNoneMatch = r.Header.Get("If-Match"), r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"3"`)
+		writeBody("application/json", strings.Repeat(`{"title":"task"}`, 100))(w, r)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", compress.Gzip)
+	req.Header.Set("If-Match", `"3-gzip"`)
+	req.Header.Set("If-None-Match", `"2-br", W/"1"`)
+	rec := httptest.NewRecorder()
+
+	middleware.Compress(compressOptions())(http.HandlerFunc(h)).ServeHTTP(rec, req)
+	small := serveCompressed(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"3"`)
+		writeBody("application/json", "{}")(w, r)
+	}, compress.Gzip)
+
+	assert.Equal(t, `"3-gzip"`, rec.Header().Get("ETag"))
+	assert.Equal(t, `"3"`, ifMatch)
+	assert.Equal(t, `"2", W/"1"`, ifNoneMatch)
+	assert.Equal(t, `"3"`, small.Header().Get("ETag"))
+}
+
+func TestCompress_SmallResponse_ShouldPassAsIs(t *testing.T) {
+	rec := serveCompressed(writeBody("application/json", `{"title":"task"}`), "gzip")
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"title":"task"}`, rec.Body.String())
+}
+
+func TestCompress_NotAllowedContentType_ShouldPassAsIs(t *testing.T) {
+	body := strings.Repeat("\x89PNG", 100)
+
+	rec := serveCompressed(writeBody("image/png", body), "gzip")
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestCompress_SniffedContentType_ShouldCompress(t *testing.T) {
+	body := strings.Repeat("plain text ", 100)
+
+	rec := serveCompressed(writeBody("", body), "gzip")
+
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+}
+
+func TestCompress_AlreadyEncoded_ShouldPassAsIs(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	rec := serveCompressed(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = io.WriteString(w, body)
+	}, "gzip")
+
+	assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestCompress_NoContent_ShouldKeepStatus(t *testing.T) {
+	rec := serveCompressed(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, "gzip")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+}
+
+func TestCompress_StatusWithSmallBody_ShouldKeepStatus(t *testing.T) {
+	rec := serveCompressed(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "{}")
+	}, "gzip")
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "{}", rec.Body.String())
+}
+
+func TestCompress_Flush_ShouldCompressStream(t *testing.T) {
+	rec := serveCompressed(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: 1\n\n")
+		http.NewResponseController(w).Flush()
+	}, "gzip")
+
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: 1\n\n", decode(t, compress.Gzip, rec.Body))
+}
+
+func TestCompress_IdentityOnly_ShouldPassAsIs(t *testing.T) {
+	body := strings.Repeat("plain text ", 100)
+
+	rec := serveCompressed(writeBody("text/plain", body), "identity")
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func encode(t *testing.T, enc, data string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w, release, err := compress.NewEncoder(enc, &buf)
+	require.NoError(t, err)
+	_, _ = io.WriteString(w, data)
+	require.NoError(t, w.Close())
+	release()
+	return &buf
+}
+
+func serveDecompressed(maxSize int64, req *http.Request) (*httptest.ResponseRecorder, string, error) {
+	var body []byte
+	var readErr error
+	h := middleware.Decompress(maxSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, readErr = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, string(body), readErr
+}
+
+func TestDecompress_EncodedBody_ShouldDecode(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", encode(t, compress.Zstd, `{"title":"task"}`))
+	req.Header.Set("Content-Encoding", "zstd")
+
+	rec, body, err := serveDecompressed(1024, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, `{"title":"task"}`, body)
+}
+
+func TestDecompress_SeveralCodings_ShouldDecodeInReverse(t *testing.T) {
+	inner := encode(t, compress.Gzip, "payload")
+	req := httptest.NewRequest(http.MethodPost, "/", encode(t, compress.Brotli, inner.String()))
+	req.Header.Set("Content-Encoding", "gzip, br")
+
+	_, body, err := serveDecompressed(1024, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "payload", body)
+}
+
+func TestDecompress_Bomb_ShouldLimitSize(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", encode(t, compress.Gzip, strings.Repeat("0", 1<<20)))
+	req.Header.Set("Content-Encoding", "gzip")
+
+	_, body, err := serveDecompressed(1024, req)
+
+	var maxErr *http.MaxBytesError
+	require.ErrorAs(t, err, &maxErr)
+	assert.Len(t, body, 1024)
+}
+
+func TestDecompress_UnsupportedCoding_ShouldUnsupportedMediaType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("data"))
+	req.Header.Set("Content-Encoding", "lzma")
+
+	rec, _, _ := serveDecompressed(1024, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Accept-Encoding"))
+}
+
+func TestDecompress_MalformedBody_ShouldBadRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rec, _, _ := serveDecompressed(1024, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
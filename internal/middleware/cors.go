@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCORSCredentialsAnyOrigin means credentials are allowed for any origin.
+var ErrCORSCredentialsAnyOrigin = errors.New(`CORS credentials must not be allowed for "*" origins`)
+
+// CORSOptions configures CORS middleware.
+// Allowed origins are exact origins, "*" for any origin
+// or wildcard subdomains, e.g. "https://*.example.com".
+// Allowed headers could be "*" to allow any request header.
+// Credentials could be allowed for explicit origins only.
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// Validate returns ErrCORSCredentialsAnyOrigin if credentials are allowed for "*" origins.
+func (o CORSOptions) Validate() error {
+	if o.AllowCredentials && slices.Contains(o.AllowedOrigins, "*") {
+		return ErrCORSCredentialsAnyOrigin
+	}
+	return nil
+}
+
+// CORS middleware implements cross-origin resource sharing.
+// Preflight requests are answered with 204 No Content without calling the next handler.
+// Requests of disallowed origins are served without CORS headers, so browsers block the response.
+// CORS panics on invalid options.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	if err := opts.Validate(); err != nil {
+		panic(err)
+	}
+	methods := make([]string, 0, len(opts.AllowedMethods))
+	for _, m := range opts.AllowedMethods {
+		methods = append(methods, strings.ToUpper(m))
+	}
+	headers := make([]string, 0, len(opts.AllowedHeaders))
+	for _, h := range opts.AllowedHeaders {
+		headers = append(headers, http.CanonicalHeaderKey(h))
+	}
+	anyHeader := slices.Contains(headers, "*")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+			if origin == "" || !allowedOrigin(opts.AllowedOrigins, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !slices.Contains(opts.AllowedOrigins, "*") {
+				h.Set("Access-Control-Allow-Origin", origin)
+			} else {
+				h.Set("Access-Control-Allow-Origin", "*")
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if !preflight {
+				if exposed != "" {
+					h.Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+			requested := requestedHeaders(r.Header.Get("Access-Control-Request-Headers"))
+			if !slices.Contains(methods, method) || !allowedHeaders(headers, anyHeader, requested) {
+				h.Del("Access-Control-Allow-Origin")
+				h.Del("Access-Control-Allow-Credentials")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			h.Set("Access-Control-Allow-Methods", method)
+			if len(requested) > 0 {
+				h.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+			}
+			if opts.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// allowedOrigin reports whether origin matches any allowed origin pattern.
+func allowedOrigin(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		prefix, suffix, ok := strings.Cut(pattern, "*")
+		if ok && len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+			return true
+		}
+	}
+	return false
+}
+
+func requestedHeaders(s string) []string {
+	var headers []string
+	for _, h := range strings.Split(s, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			headers = append(headers, http.CanonicalHeaderKey(h))
+		}
+	}
+	return headers
+}
+
+func allowedHeaders(allowed []string, anyHeader bool, requested []string) bool {
+	if anyHeader {
+		return true
+	}
+	for _, h := range requested {
+		if !slices.Contains(allowed, h) {
+			return false
+		}
+	}
+	return true
+}
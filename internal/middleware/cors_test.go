@@ -0,0 +1,125 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveCORS(opts middleware.CORSOptions, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	var called bool
+	h := middleware.CORS(opts)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, called
+}
+
+func corsOptions() middleware.CORSOptions {
+	return middleware.CORSOptions{
+		AllowedOrigins: []string{"https://board.example.com", "https://*.agile.dev"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+func preflight(origin, method, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/tasks", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORS_Preflight_Allowed_ShouldAnswer(t *testing.T) {
+	rec, called := serveCORS(corsOptions(), preflight("https://board.example.com", "POST", "content-type, x-request-id"))
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://board.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, X-Request-Id", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+}
+
+func TestCORS_Preflight_DisallowedMethod_ShouldOmitHeaders(t *testing.T) {
+	rec, _ := serveCORS(corsOptions(), preflight("https://board.example.com", "DELETE", ""))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestCORS_Preflight_DisallowedHeader_ShouldOmitHeaders(t *testing.T) {
+	rec, _ := serveCORS(corsOptions(), preflight("https://board.example.com", "POST", "X-Secret"))
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Request_WildcardSubdomain_ShouldAllow(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Origin", "https://team.agile.dev")
+
+	rec, called := serveCORS(corsOptions(), req)
+
+	assert.True(t, called)
+	assert.Equal(t, "https://team.agile.dev", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCORS_Request_DisallowedOrigin_ShouldPassWithoutHeaders(t *testing.T) {
+	for _, origin := range []string{"https://evil.example.com", "https://evil.com/.agile.dev", "http://team.agile.dev"} {
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Origin", origin)
+
+		rec, called := serveCORS(corsOptions(), req)
+
+		assert.True(t, called, origin)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), origin)
+	}
+}
+
+func TestCORS_Request_AnyOrigin_ShouldAllowAll(t *testing.T) {
+	opts := corsOptions()
+	opts.AllowedOrigins = []string{"*"}
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Origin", "https://any.example.org")
+
+	rec, _ := serveCORS(opts, req)
+
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Request_Credentials_ShouldEchoOrigin(t *testing.T) {
+	opts := corsOptions()
+	opts.AllowCredentials = true
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Origin", "https://team.agile.dev")
+
+	rec, _ := serveCORS(opts, req)
+
+	assert.Equal(t, "https://team.agile.dev", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_CredentialsAnyOrigin_ShouldPanic(t *testing.T) {
+	opts := corsOptions()
+	opts.AllowedOrigins = []string{"https://board.example.com", "*"}
+	opts.AllowCredentials = true
+
+	assert.ErrorIs(t, opts.Validate(), middleware.ErrCORSCredentialsAnyOrigin)
+	assert.PanicsWithValue(t, middleware.ErrCORSCredentialsAnyOrigin, func() {
+		middleware.CORS(opts)
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// Default CSRF token exchange names.
+const (
+	DefaultCSRFCookie = "csrf_token"
+	DefaultCSRFHeader = "X-CSRF-Token"
+)
+
+// csrfTokenSize is a number of random bytes of the CSRF token.
+const csrfTokenSize = 32
+
+// CSRFOptions configures CSRF middleware.
+// SessionCookie limits protection to cookie-authenticated requests,
+// requests without the session cookie are not vulnerable to CSRF and pass.
+// Empty SessionCookie protects every unsafe request.
+type CSRFOptions struct {
+	CookieName    string
+	HeaderName    string
+	SessionCookie string
+	Secure        bool
+}
+
+// CSRF middleware implements double-submit cookie protection.
+// Safe requests without the token cookie receive a new random token in the cookie.
+// Unsafe requests must echo the cookie token in the header,
+// otherwise they are rejected with 403 Forbidden.
+func CSRF(opts CSRFOptions) func(http.Handler) http.Handler {
+	if opts.CookieName == "" {
+		opts.CookieName = DefaultCSRFCookie
+	}
+	if opts.HeaderName == "" {
+		opts.HeaderName = DefaultCSRFHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(opts.CookieName)
+			hasToken := err == nil && cookie.Value != ""
+
+			if safeMethod(r.Method) {
+				if !hasToken {
+					http.SetCookie(w, &http.Cookie{
+						Name:     opts.CookieName,
+						Value:    newCSRFToken(),
+						Path:     "/",
+						Secure:   opts.Secure,
+						SameSite: http.SameSiteStrictMode,
+					})
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if opts.SessionCookie != "" {
+				if _, err := r.Cookie(opts.SessionCookie); err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			header := r.Header.Get(opts.HeaderName)
+			if !hasToken || header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+				problem.Write(w, r, problem.New(http.StatusForbidden, "CSRF token missing or invalid"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func newCSRFToken() string {
+	b := make([]byte, csrfTokenSize)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveCSRF(opts middleware.CSRFOptions, req *http.Request) *httptest.ResponseRecorder {
+	h := middleware.CSRF(opts)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCSRF_SafeRequest_ShouldIssueToken(t *testing.T) {
+	rec := serveCSRF(middleware.CSRFOptions{Secure: true}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, middleware.DefaultCSRFCookie, cookies[0].Name)
+	assert.Len(t, cookies[0].Value, 43)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+}
+
+func TestCSRF_SafeRequestWithToken_ShouldKeepToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookie, Value: "token"})
+
+	rec := serveCSRF(middleware.CSRFOptions{}, req)
+
+	assert.Empty(t, rec.Result().Cookies())
+}
+
+func TestCSRF_UnsafeRequest_MatchingToken_ShouldPass(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookie, Value: "token"})
+	req.Header.Set(middleware.DefaultCSRFHeader, "token")
+
+	rec := serveCSRF(middleware.CSRFOptions{}, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestCSRF_UnsafeRequest_InvalidToken_ShouldForbid(t *testing.T) {
+	cases := map[string]func(*http.Request){
+		"no cookie": func(r *http.Request) { r.Header.Set(middleware.DefaultCSRFHeader, "token") },
+		"no header": func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookie, Value: "token"})
+		},
+		"mismatch": func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookie, Value: "token"})
+			r.Header.Set(middleware.DefaultCSRFHeader, "forged")
+		},
+	}
+	for name, prepare := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			prepare(req)
+
+			rec := serveCSRF(middleware.CSRFOptions{}, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+		})
+	}
+}
+
+func TestCSRF_UnsafeRequest_WithoutSession_ShouldPass(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := serveCSRF(middleware.CSRFOptions{SessionCookie: "session"}, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestCSRF_UnsafeRequest_WithSession_ShouldRequireToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "id"})
+
+	rec := serveCSRF(middleware.CSRFOptions{SessionCookie: "session"}, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
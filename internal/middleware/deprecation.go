@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+)
+
+// DeprecationInfo describes deprecated API routes.
+type DeprecationInfo struct {
+	// Version of the API the routes belong to, used in logs and metrics.
+	Version string
+	// Since is the moment the routes are deprecated.
+	Since time.Time
+	// Sunset is the moment the routes are going to be removed, optional.
+	Sunset time.Time
+	// Link to the deprecation notice or migration guide, optional.
+	Link string
+	// Successor link to the replacing version of the routes, optional.
+	Successor string
+}
+
+// Deprecated middleware marks responses of the routes as deprecated with
+// Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers,
+// and reports usage of the routes by client to the log and metrics.
+// Metrics are optional.
+func Deprecated(info DeprecationInfo, m *metrics.Deprecations) func(http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(info.Since.Unix(), 10)
+	var sunset string
+	if !info.Sunset.IsZero() {
+		sunset = info.Sunset.UTC().Format(http.TimeFormat)
+	}
+	var links []string
+	if info.Link != "" {
+		links = append(links, "<"+info.Link+`>; rel="deprecation"; type="text/html"`)
+	}
+	if info.Successor != "" {
+		links = append(links, "<"+info.Successor+`>; rel="successor-version"`)
+	}
+	client := FirstKey(KeyByUser, KeyByToken, KeyByIP)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", deprecation)
+			if sunset != "" {
+				h.Set("Sunset", sunset)
+			}
+			for _, link := range links {
+				h.Add("Link", link)
+			}
+
+			next.ServeHTTP(w, r)
+
+			route := routePattern(r)
+			if m != nil {
+				m.Observe(info.Version, route)
+			}
+			if lg := LoggerFrom(r.Context()); lg != nil {
+				key, _ := client(r)
+				lg.InfoContext(r.Context(), "Deprecated API route used",
+					"api-version", info.Version,
+					"route", route,
+					"client", key,
+					"user-agent", r.UserAgent(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated_ShouldSetHeaders(t *testing.T) {
+	info := middleware.DeprecationInfo{
+		Version:   "v1",
+		Since:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		Link:      "https://example.com/deprecation",
+		Successor: "/api/v2",
+	}
+	h := middleware.Deprecated(info, nil)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "@1704067200", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 01 Jul 2024 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://example.com/deprecation>; rel="deprecation"; type="text/html"`,
+		`</api/v2>; rel="successor-version"`,
+	}, rec.Header().Values("Link"))
+}
+
+func TestDeprecated_ShouldReportUsageByClient(t *testing.T) {
+	var buf bytes.Buffer
+	reg := prometheus.NewRegistry()
+	m := metrics.NewDeprecations(reg, "test")
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := middleware.WithLogger(r.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.With(middleware.Deprecated(middleware.DeprecationInfo{Version: "v1"}, m)).
+		Get("/tasks/{id}", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_api_deprecated_requests_total Total number of requests served by deprecated API routes.
+# TYPE test_api_deprecated_requests_total counter
+test_api_deprecated_requests_total{route="/tasks/{id}",version="v1"} 1
+`)))
+	assert.Contains(t, buf.String(), "Deprecated API route used")
+	assert.Contains(t, buf.String(), "client=ip:10.0.0.1")
+	assert.Contains(t, buf.String(), "route=/tasks/{id}")
+}
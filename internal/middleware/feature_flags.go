@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/asmazovec/team-agile/internal/featureflag"
+)
+
+// FeatureFlags middleware exposes flags evaluated for the authenticated user, team and tenant
+// to handlers through featureflag.Enabled.
+func FeatureFlags(reg *featureflag.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			ctx = featureflag.WithSubject(ctx, reg, featureflag.Subject{
+				UserID:   UserIDFrom(ctx),
+				TeamID:   TeamIDFrom(ctx),
+				TenantID: TenantIDFrom(ctx),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
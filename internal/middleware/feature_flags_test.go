@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/featureflag"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlags_ShouldEvaluateForIdentity(t *testing.T) {
+	reg, err := featureflag.NewRegistry([]featureflag.Flag{
+		{Name: "swimlanes", Enabled: true, Teams: []string{"core"}},
+		{Name: "analytics", Enabled: true, Tenants: []string{"globex"}},
+	}, slog.Default())
+	require.NoError(t, err)
+	var swimlanes, analytics bool
+	h := middleware.FeatureFlags(reg)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		swimlanes = featureflag.Enabled(r.Context(), "swimlanes")
+		analytics = featureflag.Enabled(r.Context(), "analytics")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := middleware.WithTeamID(middleware.WithTenantID(req.Context(), "acme"), "core")
+
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+	assert.True(t, swimlanes)
+	assert.False(t, analytics)
+}
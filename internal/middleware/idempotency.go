@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idempotency"
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// IdempotencyKeyHeader is a header carrying client generated idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLen limits size of the idempotency key.
+const maxIdempotencyKeyLen = 255
+
+// Idempotency middleware makes POST and PATCH requests with Idempotency-Key header safe to retry.
+// Response of the completed request is stored for ttl by the key, client and method and path of the request
+// and replayed on retry. Client is the user, the bearer token or, for anonymous requests, the remote IP.
+// Concurrent duplicate of the in-flight request is rejected with 409 Conflict,
+// reuse of the key with a different request is rejected with 422 Unprocessable Content.
+// Failed requests with 5xx status are not stored, so they could be retried.
+func Idempotency(store idempotency.Store, ttl time.Duration) func(http.Handler) http.Handler {
+	client := FirstKey(KeyByUser, KeyByToken, KeyByIP)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idemKey := r.Header.Get(IdempotencyKeyHeader)
+			if idemKey == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idemKey) > maxIdempotencyKeyLen {
+				problem.Write(w, r, problem.New(http.StatusBadRequest, "Idempotency-Key is too long"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				status := http.StatusBadRequest
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				problem.Write(w, r, problem.New(status, "request body could not be read"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			c, _ := client(r)
+			// Middleware may run before sub-routers match the route, so the full path is used.
+			key := c + ":" + r.Method + " " + r.URL.Path + ":" + idemKey
+			sum := sha256.Sum256(body)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			ctx := r.Context()
+			rec, reserved, err := store.Reserve(ctx, key, idempotency.Record{Fingerprint: fingerprint}, ttl)
+			if err != nil {
+				if lg := LoggerFrom(ctx); lg != nil {
+					lg.WarnContext(ctx, "Idempotency skipped: "+err.Error())
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !reserved {
+				replay(w, r, rec, fingerprint)
+				return
+			}
+
+			rw := &recordingWriter{ResponseWriter: w}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := store.Release(ctx, key); err != nil {
+					if lg := LoggerFrom(ctx); lg != nil {
+						lg.WarnContext(ctx, "Idempotency key release failed: "+err.Error())
+					}
+				}
+			}()
+			next.ServeHTTP(rw, r)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+			completed = true
+			err = store.Complete(ctx, key, idempotency.Record{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				Status:      status,
+				Header:      rw.header,
+				Body:        rw.body.Bytes(),
+			}, ttl)
+			if err != nil {
+				if lg := LoggerFrom(ctx); lg != nil {
+					lg.WarnContext(ctx, "Idempotency response not stored: "+err.Error())
+				}
+			}
+		})
+	}
+}
+
+// replay responds to the retried request with the stored record.
+func replay(w http.ResponseWriter, r *http.Request, rec idempotency.Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity,
+			"Idempotency-Key was already used with a different request"))
+	case !rec.Completed:
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, r, problem.New(http.StatusConflict, "request with the Idempotency-Key is in progress"))
+	default:
+		h := w.Header()
+		// Headers set by the current request, e.g. request id, are kept.
+		for k, v := range rec.Header {
+			if _, ok := h[k]; !ok {
+				h[k] = v
+			}
+		}
+		h.Set("Idempotent-Replayed", "true")
+		w.WriteHeader(rec.Status)
+		_, _ = w.Write(rec.Body)
+	}
+}
+
+// recordingWriter writes response through capturing status, header and body.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.status == 0 && code >= http.StatusOK {
+		rw.status = code
+		rw.header = rw.Header().Clone()
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(p)
+	return rw.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,181 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/idempotency"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createdHandler struct {
+	calls  atomic.Int32
+	status int
+	block  chan struct{}
+}
+
+func (h *createdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.calls.Add(1)
+	if h.block != nil {
+		<-h.block
+	}
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", n))
+	w.Header().Set(middleware.DefaultRequestIDHeader, fmt.Sprintf("request-%d", n))
+	status := h.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func idempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return req.WithContext(middleware.WithUserID(req.Context(), "u1"))
+}
+
+func TestIdempotency_Retry_ShouldReplayResponse(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, idempotentRequest("k1", `{"title":"task"}`))
+	retry := httptest.NewRecorder()
+	retry.Header().Set(middleware.DefaultRequestIDHeader, "request-retry")
+
+	h.ServeHTTP(retry, idempotentRequest("k1", `{"title":"task"}`))
+
+	assert.Equal(t, int32(1), next.calls.Load())
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "/tasks/1", retry.Header().Get("Location"))
+	assert.Equal(t, "request-retry", retry.Header().Get(middleware.DefaultRequestIDHeader))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+}
+
+func TestIdempotency_DifferentBody_ShouldUnprocessable(t *testing.T) {
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(&createdHandler{})
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{"title":"task"}`))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, idempotentRequest("k1", `{"title":"other"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestIdempotency_InFlightDuplicate_ShouldConflict(t *testing.T) {
+	next := &createdHandler{block: make(chan struct{})}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+	}()
+	require.Eventually(t, func() bool { return next.calls.Load() == 1 }, time.Second, time.Millisecond)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, idempotentRequest("k1", `{}`))
+	close(next.block)
+	<-done
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestIdempotency_ServerError_ShouldAllowRetry(t *testing.T) {
+	next := &createdHandler{status: http.StatusInternalServerError}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+	next.status = http.StatusCreated
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, idempotentRequest("k1", `{}`))
+
+	assert.Equal(t, int32(2), next.calls.Load())
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_DifferentUsers_ShouldNotShareKeys(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+	req := idempotentRequest("k1", `{}`)
+	req = req.WithContext(middleware.WithUserID(req.Context(), "u2"))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, int32(2), next.calls.Load())
+}
+
+func TestIdempotency_DifferentPaths_ShouldNotShareKeys(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+	req := idempotentRequest("k1", `{}`)
+	req.URL.Path = "/boards"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, int32(2), next.calls.Load())
+	assert.Empty(t, rec.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_AnonymousClients_ShouldNotShareKeys(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+	anonymous := func(addr string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{}`))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+		req.RemoteAddr = addr
+		return req
+	}
+	h.ServeHTTP(httptest.NewRecorder(), anonymous("192.0.2.1:1234"))
+
+	h.ServeHTTP(httptest.NewRecorder(), anonymous("192.0.2.2:1234"))
+	h.ServeHTTP(httptest.NewRecorder(), anonymous("192.0.2.1:5678"))
+
+	assert.Equal(t, int32(2), next.calls.Load())
+}
+
+func TestIdempotency_NoKey_ShouldPass(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(next)
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("", `{}`))
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("", `{}`))
+
+	assert.Equal(t, int32(2), next.calls.Load())
+}
+
+type failingIdempotencyStore struct{ idempotency.Store }
+
+func (failingIdempotencyStore) Reserve(context.Context, string, idempotency.Record, time.Duration) (
+	idempotency.Record, bool, error,
+) {
+	return idempotency.Record{}, false, errors.New("store is down")
+}
+
+func TestIdempotency_StoreFailure_ShouldFailOpen(t *testing.T) {
+	next := &createdHandler{}
+	h := middleware.Idempotency(failingIdempotencyStore{}, time.Hour)(next)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, idempotentRequest("k1", `{}`))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
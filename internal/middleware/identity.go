@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+type (
+	userID   struct{}
+	teamID   struct{}
+	tenantID struct{}
+	tokenID  struct{}
+)
+
+// WithUserID injects authenticated user id into the context.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userID{}, id)
+}
+
+// UserIDFrom extracts authenticated user id from context.
+// Returns empty string if user id can not be found.
+func UserIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(userID{}).(string)
+	return id
+}
+
+// WithTeamID injects team id of the authenticated user into the context.
+func WithTeamID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, teamID{}, id)
+}
+
+// TeamIDFrom extracts team id from context.
+// Returns empty string if team id can not be found.
+func TeamIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(teamID{}).(string)
+	return id
+}
+
+// WithTenantID injects tenant id into the context.
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantID{}, id)
+}
+
+// TenantIDFrom extracts tenant id from context.
+// Returns empty string if tenant id can not be found.
+func TenantIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(tenantID{}).(string)
+	return id
+}
+
+// WithTokenID injects id of the verified API token into the context.
+func WithTokenID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tokenID{}, id)
+}
+
+// TokenIDFrom extracts id of the verified API token from context.
+// Returns empty string if the request token was not verified.
+func TokenIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(tokenID{}).(string)
+	return id
+}
+
+// UserIDLog logger extension for log authenticated user id.
+func UserIDLog(r *http.Request) (slog.Attr, bool) {
+	id := UserIDFrom(r.Context())
+	if id == "" {
+		return slog.Attr{}, false
+	}
+	return slog.String("user-id", id), true
+}
+
+// TenantIDLog logger extension for log tenant id.
+func TenantIDLog(r *http.Request) (slog.Attr, bool) {
+	id := TenantIDFrom(r.Context())
+	if id == "" {
+		return slog.Attr{}, false
+	}
+	return slog.String("tenant-id", id), true
+}
+
+// IdentityHeaders middleware injects user, team and tenant ids of the caller authenticated by the gateway
+// in front of the server from the request headers of the names, empty names are skipped.
+// Headers are trusted as is, so the gateway must replace them in every request it forwards.
+func IdentityHeaders(user, team, tenant string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if id := identityHeader(r, user); id != "" {
+				ctx = WithUserID(ctx, id)
+			}
+			if id := identityHeader(r, team); id != "" {
+				ctx = WithTeamID(ctx, id)
+			}
+			if id := identityHeader(r, tenant); id != "" {
+				ctx = WithTenantID(ctx, id)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func identityHeader(r *http.Request, name string) string {
+	if name == "" {
+		return ""
+	}
+	return r.Header.Get(name)
+}
@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUserID_ShouldInjectUserID(t *testing.T) {
+	ctx := middleware.WithUserID(context.Background(), "user")
+
+	assert.Equal(t, "user", middleware.UserIDFrom(ctx))
+}
+
+func TestWithTenantID_ShouldInjectTenantID(t *testing.T) {
+	ctx := middleware.WithTenantID(context.Background(), "tenant")
+
+	assert.Equal(t, "tenant", middleware.TenantIDFrom(ctx))
+}
+
+func TestWithTeamID_ShouldInjectTeamID(t *testing.T) {
+	ctx := middleware.WithTeamID(context.Background(), "team")
+
+	assert.Equal(t, "team", middleware.TeamIDFrom(ctx))
+}
+
+func TestUserIDFrom_WithEmptyContext_ShouldEmptyString(t *testing.T) {
+	assert.Equal(t, "", middleware.UserIDFrom(context.Background()))
+	assert.Equal(t, "", middleware.TeamIDFrom(context.Background()))
+	assert.Equal(t, "", middleware.TenantIDFrom(context.Background()))
+}
+
+func TestUserIDLog_ShouldLogUserAndTenant(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := middleware.WithTenantID(middleware.WithUserID(req.Context(), "user"), "tenant")
+	req = req.WithContext(ctx)
+
+	userArg, userOk := middleware.UserIDLog(req)
+	tenantArg, tenantOk := middleware.TenantIDLog(req)
+
+	assert.True(t, userOk)
+	assert.Equal(t, "user-id", userArg.Key)
+	assert.Equal(t, "user", userArg.Value.String())
+	assert.True(t, tenantOk)
+	assert.Equal(t, "tenant-id", tenantArg.Key)
+	assert.Equal(t, "tenant", tenantArg.Value.String())
+}
+
+func TestUserIDLog_WithNoIdentity_ShouldNotOk(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, userOk := middleware.UserIDLog(req)
+	_, tenantOk := middleware.TenantIDLog(req)
+
+	assert.False(t, userOk)
+	assert.False(t, tenantOk)
+}
+
+func TestIdentityHeaders_ShouldInjectIdentity(t *testing.T) {
+	var user, team, tenant string
+	h := middleware.IdentityHeaders("X-User-ID", "", "X-Tenant-ID")(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			user = middleware.UserIDFrom(r.Context())
+			team = middleware.TeamIDFrom(r.Context())
+			tenant = middleware.TenantIDFrom(r.Context())
+		}),
+	)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User-ID", "user")
+	req.Header.Set("X-Team-ID", "team")
+	req.Header.Set("X-Tenant-ID", "tenant")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "user", user)
+	assert.Empty(t, team)
+	assert.Equal(t, "tenant", tenant)
+}
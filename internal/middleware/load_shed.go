@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// LoadShed middleware limits concurrent requests of the route group by the adaptive limiter.
+// Requests exceeding the limit are shed with 503 problem and Retry-After header.
+func LoadShed(l *loadshed.Limiter, group string, retryAfter time.Duration) func(http.Handler) http.Handler {
+	retry := strconv.Itoa(max(seconds(retryAfter), 1))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			release, ok := l.Acquire(r.Context())
+			if !ok {
+				w.Header().Set("Retry-After", retry)
+				problem.Write(w, r, problem.New(http.StatusServiceUnavailable, "concurrency limit of "+group+" exceeded"))
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
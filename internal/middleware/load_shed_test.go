@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/loadshed"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShed_ServeHTTP_UnderLimit_ShouldPass(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second})
+	var inFlight int
+	h := middleware.LoadShed(l, "default", time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		inFlight = l.Stats().InFlight
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 1, inFlight)
+	assert.Equal(t, 0, l.Stats().InFlight)
+}
+
+func TestLoadShed_ServeHTTP_OverLimit_ShouldServiceUnavailable(t *testing.T) {
+	l := loadshed.New(loadshed.Config{MaxLimit: 1, Target: time.Second})
+	release, _ := l.Acquire(context.Background())
+	defer release()
+	h := middleware.LoadShed(l, "default", 1500*time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
 )
 
 type loggerKey struct{}
@@ -24,6 +27,7 @@ type logger struct {
 	h http.Handler
 	l *slog.Logger
 	e []LogExtension
+	s *Sampler
 }
 
 // LogExtension is an additional attributes for logging.
@@ -33,7 +37,16 @@ type LogExtension func(r *http.Request) (slog.Attr, bool)
 // Logger is configured slog.Logger with additions of LogExtension.
 func Logger(l *slog.Logger, extensions ...LogExtension) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
-		return &logger{next, l, extensions}
+		return &logger{next, l, extensions, nil}
+	}
+}
+
+// SampledLogger middleware injects logger into each request context as the Logger does,
+// but logs the request once it is completed with response status and duration.
+// Requests are logged only if the sampler decides to.
+func SampledLogger(l *slog.Logger, s *Sampler, extensions ...LogExtension) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return &logger{next, l, extensions, s}
 	}
 }
 
@@ -67,9 +80,52 @@ func (l *logger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		lg = lg.With(a)
 	}
 
-	lg.Info("Request")
+	if l.s == nil {
+		lg.InfoContext(r.Context(), "Request")
+	}
 
 	ctx := WithLogger(r.Context(), lg)
 	r = r.WithContext(ctx)
-	l.h.ServeHTTP(w, r)
+	if l.s == nil {
+		l.h.ServeHTTP(w, r)
+		return
+	}
+
+	start := time.Now()
+	ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+	l.h.ServeHTTP(ww, r)
+
+	status := ww.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if !l.s.Sample(r, status) {
+		return
+	}
+	level := slog.LevelInfo
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	lg.Log(ctx, level, "Request",
+		slog.Int("status", status),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("bytes", ww.BytesWritten()),
+	)
+}
+
+// OriginalRequestIDLog logger extension for log incoming request id replaced by the RequestIDWith.
+func OriginalRequestIDLog(r *http.Request) (slog.Attr, bool) {
+	reqID := OriginalRequestIDFrom(r.Context())
+	if reqID == "" {
+		return slog.Attr{}, false
+	}
+	return slog.String("original-request-id", reqID), true
+}
+
+// PathLog logger extension for log request path.
+func PathLog(r *http.Request) (slog.Attr, bool) {
+	return slog.String("path", r.URL.Path), true
 }
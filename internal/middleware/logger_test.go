@@ -1,7 +1,9 @@
 package middleware_test
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -110,3 +112,86 @@ func TestLogger_ServeHTTP_Ext_ShouldCall(t *testing.T) {
 
 	assert.True(t, ext.called)
 }
+
+func TestOriginalRequestIDLog_ShouldLogOriginalRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "gateway-42")
+	var (
+		arg slog.Attr
+		ok  bool
+	)
+	mw := middleware.RequestID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		arg, ok = middleware.OriginalRequestIDLog(r)
+	}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "gateway-42", arg.Value.String())
+	assert.Equal(t, "original-request-id", arg.Key)
+}
+
+func TestOriginalRequestIDLog_WithNoOriginal_ShouldNotOk(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, ok := middleware.OriginalRequestIDLog(req)
+
+	assert.False(t, ok)
+}
+
+func TestSampledLogger_ServeHTTP_ShouldLogCompletedRequest(t *testing.T) {
+	buf := &bytes.Buffer{}
+	origLogger := slog.New(slog.NewJSONHandler(buf, nil))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	mw := middleware.SampledLogger(origLogger, middleware.NewSampler(1, nil), middleware.MethodLog)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NotNil(t, middleware.LoggerFrom(r.Context()))
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"status":201`)
+	assert.Contains(t, buf.String(), `"method":"GET"`)
+	assert.Contains(t, buf.String(), `"duration":`)
+}
+
+func TestSampledLogger_ServeHTTP_Dropped_ShouldNotLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	origLogger := slog.New(slog.NewJSONHandler(buf, nil))
+	s := middleware.NewSampler(0, nil)
+	mw := middleware.SampledLogger(origLogger, s)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Empty(t, buf.String())
+	assert.Equal(t, uint64(1), s.Dropped())
+}
+
+func TestSampledLogger_ServeHTTP_ServerError_ShouldLogError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	origLogger := slog.New(slog.NewJSONHandler(buf, nil))
+	mw := middleware.SampledLogger(origLogger, middleware.NewSampler(0, nil))(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Contains(t, buf.String(), `"level":"ERROR"`)
+	assert.Contains(t, buf.String(), `"status":502`)
+}
+
+func TestPathLog_ShouldLogPath(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/42?page=1", nil)
+
+	arg, ok := middleware.PathLog(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "/tasks/42", arg.Value.String())
+	assert.Equal(t, "path", arg.Key)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, nil))
+}
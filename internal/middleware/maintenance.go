@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// Maintenance middleware rejects requests according to the maintenance switch state.
+// In maintenance mode all requests are rejected with 503 Service Unavailable and Retry-After header,
+// in read-only mode requests of unsafe methods are rejected the same way.
+// Health and administrative routes should be served outside the middleware.
+func Maintenance(s *maintenance.Switch, retryAfter time.Duration) func(http.Handler) http.Handler {
+	retry := strconv.Itoa(max(seconds(retryAfter), 1))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := s.State()
+			switch state.Mode {
+			case maintenance.ModeMaintenance:
+			case maintenance.ModeReadOnly:
+				if safeMethod(r.Method) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			detail := state.Message
+			if detail == "" {
+				detail = "service is in " + string(state.Mode) + " mode"
+			}
+			w.Header().Set("Retry-After", retry)
+			problem.Write(w, r, problem.New(http.StatusServiceUnavailable, detail).With("mode", state.Mode))
+		})
+	}
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/maintenance"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveMaintenance(state maintenance.State, method string) *httptest.ResponseRecorder {
+	s := maintenance.NewSwitch(state)
+	h := middleware.Maintenance(s, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/tasks", nil))
+	return rec
+}
+
+func TestMaintenance_Off_ShouldPass(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		rec := serveMaintenance(maintenance.State{}, method)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code, method)
+	}
+}
+
+func TestMaintenance_Maintenance_ShouldRejectAll(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		rec := serveMaintenance(maintenance.State{Mode: maintenance.ModeMaintenance, Message: "migration"}, method)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, method)
+		assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+		assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `"detail":"migration"`)
+	}
+}
+
+func TestMaintenance_ReadOnly_ShouldRejectMutations(t *testing.T) {
+	read := serveMaintenance(maintenance.State{Mode: maintenance.ModeReadOnly}, http.MethodGet)
+	write := serveMaintenance(maintenance.State{Mode: maintenance.ModeReadOnly}, http.MethodDelete)
+
+	assert.Equal(t, http.StatusNoContent, read.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, write.Code)
+	assert.Contains(t, write.Body.String(), `"mode":"read-only"`)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// Metrics middleware records RED metrics of each request labeled by the chi route pattern.
+func Metrics(m *metrics.HTTP) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			done := m.Start()
+			defer done()
+
+			start := time.Now()
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.Observe(r.Method, routePattern(r), status, time.Since(start), ww.BytesWritten())
+		})
+	}
+}
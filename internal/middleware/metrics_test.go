@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/metrics"
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_ServeHTTP_ShouldRecordByRoutePattern(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(middleware.Metrics(metrics.NewHTTP(reg, "test")))
+	router.Get("/tasks/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_http_requests_total Total number of handled HTTP requests.
+# TYPE test_http_requests_total counter
+test_http_requests_total{code="201",method="GET",route="/tasks/{id}"} 2
+test_http_requests_total{code="404",method="GET",route="unmatched"} 1
+`), "test_http_requests_total")
+	require.NoError(t, err)
+}
+
+func TestMetrics_ServeHTTP_ShouldRecordResponseSize(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(middleware.Metrics(metrics.NewHTTP(reg, "test")))
+	router.Get("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("Hello world!"))
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "test_http_response_size_bytes"))
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+)
+
+// RateLimitKey extracts a client key of the request to be limited.
+// Reports false if the request can not be attributed to a client.
+type RateLimitKey func(r *http.Request) (string, bool)
+
+// Rate limit key names supported by RateLimitKeyByName.
+const (
+	KeyNameIP    = "ip"
+	KeyNameToken = "token"
+	KeyNameUser  = "user"
+	KeyNameAuto  = "auto"
+)
+
+// RateLimitKeyByName returns rate limit key registered under the name.
+// Auto key prefers user id, then verified API token, then client IP.
+func RateLimitKeyByName(name string) (RateLimitKey, error) {
+	switch name {
+	case KeyNameIP:
+		return KeyByIP, nil
+	case KeyNameToken:
+		return KeyByToken, nil
+	case KeyNameUser:
+		return KeyByUser, nil
+	case KeyNameAuto:
+		return FirstKey(KeyByUser, KeyByToken, KeyByIP), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", name)
+	}
+}
+
+// KeyByIP rate limit key of the client IP address.
+// Use behind a proxy requires restoring RemoteAddr from trusted headers first.
+func KeyByIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host, host != ""
+}
+
+// KeyByToken rate limit key of the API token verified by the authentication middleware, e.g. BearerToken.
+// Unverified credentials are ignored, so made-up tokens never get buckets of their own.
+func KeyByToken(r *http.Request) (string, bool) {
+	id := TokenIDFrom(r.Context())
+	return "token:" + id, id != ""
+}
+
+// KeyByUser rate limit key of the authenticated user.
+func KeyByUser(r *http.Request) (string, bool) {
+	id := UserIDFrom(r.Context())
+	return "user:" + id, id != ""
+}
+
+// FirstKey returns the first key extracted in presented order.
+func FirstKey(keys ...RateLimitKey) RateLimitKey {
+	return func(r *http.Request) (string, bool) {
+		for _, key := range keys {
+			if k, ok := key(r); ok {
+				return k, true
+			}
+		}
+		return "", false
+	}
+}
+
+// RateLimit middleware limits requests of each client in the route group.
+// Responds 429 Too Many Requests with Retry-After header once the limit is exceeded
+// and sets RateLimit-* headers on each limited response.
+// Requests pass if the limiter store fails.
+func RateLimit(l *ratelimit.Limiter, group string, key RateLimitKey) func(http.Handler) http.Handler {
+	rule := l.Rule()
+	policy := strconv.Itoa(rule.Limit) + ";w=" + strconv.Itoa(seconds(rule.Window))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k, ok := key(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := l.Take(r.Context(), group+":"+k)
+			if err != nil {
+				if lg := LoggerFrom(r.Context()); lg != nil {
+					lg.WarnContext(r.Context(), "Rate limit skipped: "+err.Error())
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", policy)
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+				problem.Write(w, r, problem.New(http.StatusTooManyRequests, "rate limit of "+group+" exceeded"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds rounds duration up to whole seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,164 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimited(limit int) http.Handler {
+	l := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.TokenBucket,
+		ratelimit.Rule{Limit: limit, Window: time.Minute})
+	return middleware.RateLimit(l, "default", middleware.KeyByIP)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+}
+
+func TestRateLimit_ServeHTTP_UnderLimit_ShouldPassWithHeaders(t *testing.T) {
+	h := newRateLimited(2)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_ServeHTTP_OverLimit_ShouldTooManyRequests(t *testing.T) {
+	h := newRateLimited(1)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+}
+
+func TestRateLimit_ServeHTTP_DifferentClients_ShouldLimitSeparately(t *testing.T) {
+	h := newRateLimited(1)
+	first := httptest.NewRequest(http.MethodGet, "/", nil)
+	first.RemoteAddr = "10.0.0.1:1234"
+	second := httptest.NewRequest(http.MethodGet, "/", nil)
+	second.RemoteAddr = "10.0.0.2:1234"
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(httptest.NewRecorder(), first)
+	h.ServeHTTP(rec, second)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRateLimit_ServeHTTP_NoKey_ShouldPass(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.TokenBucket, ratelimit.Rule{Limit: 1, Window: time.Minute})
+	h := middleware.RateLimit(l, "default", middleware.KeyByUser)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
+
+type FailingStore struct{}
+
+func (FailingStore) Get(context.Context, string) ([]byte, uint64, error) {
+	return nil, 0, errors.New("unavailable")
+}
+
+func (FailingStore) CompareAndSwap(context.Context, string, uint64, []byte, time.Duration) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func TestRateLimit_ServeHTTP_StoreError_ShouldPass(t *testing.T) {
+	l := ratelimit.NewLimiter(FailingStore{}, ratelimit.TokenBucket, ratelimit.Rule{Limit: 1, Window: time.Minute})
+	h := middleware.Logger(discardLogger())(middleware.RateLimit(l, "default", middleware.KeyByIP)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestKeyByIP_ShouldUseHost(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	key, ok := middleware.KeyByIP(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "ip:10.0.0.1", key)
+}
+
+// verifiedKey returns the key of the request passed BearerToken middleware.
+func verifiedKey(t *testing.T, key middleware.RateLimitKey, req *http.Request) string {
+	t.Helper()
+	var got string
+	middleware.BearerToken("secret")(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = key(r)
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+func TestKeyByToken_ShouldHashVerifiedToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	_, unverified := middleware.KeyByToken(req)
+	key := verifiedKey(t, middleware.KeyByToken, req)
+
+	assert.False(t, unverified)
+	assert.NotContains(t, key, "secret")
+	assert.Len(t, key, len("token:")+32)
+}
+
+func TestRateLimitKeyByName_Auto_ShouldPreferUser(t *testing.T) {
+	key, err := middleware.RateLimitKeyByName(middleware.KeyNameAuto)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("Authorization", "Bearer made-up")
+	userReq := req.WithContext(middleware.WithUserID(req.Context(), "user"))
+	tokenReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	tokenReq.Header.Set("Authorization", "Bearer secret")
+
+	ipKey, _ := key(req)
+	userKey, _ := key(userReq)
+	tokenKey := verifiedKey(t, key, tokenReq)
+
+	assert.Equal(t, "ip:10.0.0.1", ipKey)
+	assert.Equal(t, "user:user", userKey)
+	assert.Contains(t, tokenKey, "token:")
+}
+
+func TestRateLimitKeyByName_Known_ShouldReturn(t *testing.T) {
+	for _, name := range []string{middleware.KeyNameIP, middleware.KeyNameToken, middleware.KeyNameUser} {
+		key, err := middleware.RateLimitKeyByName(name)
+
+		require.NoError(t, err)
+		assert.NotNil(t, key)
+	}
+}
+
+func TestRateLimitKeyByName_Unknown_ShouldError(t *testing.T) {
+	_, err := middleware.RateLimitKeyByName("cookie")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces values of sensitive keys in logs.
+const Redacted = "[REDACTED]"
+
+// emailPattern matches e-mail addresses in logged values.
+const emailPattern = `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`
+
+// Redactor masks sensitive values before they are logged.
+// Zero value masks nothing.
+type Redactor struct {
+	keys   []string
+	emails *regexp.Regexp
+}
+
+// NewRedactor creates redactor of values with keys matching case-insensitive path.Match patterns,
+// e.g. "authorization" or "*token*".
+// E-mail addresses are masked in any value if emails is set.
+func NewRedactor(keys []string, emails bool) *Redactor {
+	rd := &Redactor{}
+	for _, k := range keys {
+		if k = strings.TrimSpace(k); k != "" {
+			rd.keys = append(rd.keys, strings.ToLower(k))
+		}
+	}
+	if emails {
+		rd.emails = regexp.MustCompile(emailPattern)
+	}
+	return rd
+}
+
+// Sensitive reports whether values of the key should be masked.
+func (rd *Redactor) Sensitive(key string) bool {
+	key = strings.ToLower(key)
+	return slices.ContainsFunc(rd.keys, func(pattern string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	})
+}
+
+// Redact returns value safe to log under the key.
+func (rd *Redactor) Redact(key, val string) string {
+	if rd.Sensitive(key) {
+		return Redacted
+	}
+	if rd.emails != nil {
+		val = rd.emails.ReplaceAllStringFunc(val, maskEmail)
+	}
+	return val
+}
+
+// HeadersLog returns logger extension for log request headers masked by the redactor.
+func HeadersLog(rd *Redactor) LogExtension {
+	return func(r *http.Request) (slog.Attr, bool) {
+		return redactedGroup(rd, "headers", r.Header)
+	}
+}
+
+// QueryLog returns logger extension for log request query parameters masked by the redactor.
+func QueryLog(rd *Redactor) LogExtension {
+	return func(r *http.Request) (slog.Attr, bool) {
+		return redactedGroup(rd, "query", r.URL.Query())
+	}
+}
+
+func redactedGroup(rd *Redactor, name string, values map[string][]string) (slog.Attr, bool) {
+	if len(values) == 0 {
+		return slog.Attr{}, false
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, k := range keys {
+		vals := make([]string, len(values[k]))
+		for i, v := range values[k] {
+			vals[i] = rd.Redact(k, v)
+		}
+		attrs = append(attrs, slog.String(k, strings.Join(vals, ", ")))
+	}
+	return slog.Group(name, attrs...), true
+}
+
+// maskEmail keeps the first letter of the local part and the domain.
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	return email[:1] + "***" + email[at:]
+}
@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Redact_SensitiveKey_ShouldMask(t *testing.T) {
+	rd := middleware.NewRedactor([]string{"Authorization", "*token*", " "}, false)
+
+	assert.Equal(t, middleware.Redacted, rd.Redact("authorization", "Bearer secret"))
+	assert.Equal(t, middleware.Redacted, rd.Redact("X-Api-Token", "secret"))
+	assert.Equal(t, middleware.Redacted, rd.Redact("access_token", "secret"))
+	assert.Equal(t, "gzip", rd.Redact("Accept-Encoding", "gzip"))
+}
+
+func TestRedactor_Redact_Emails_ShouldMask(t *testing.T) {
+	rd := middleware.NewRedactor(nil, true)
+
+	assert.Equal(t, "to j***@example.com, a***@team.io", rd.Redact("q", "to john.doe@example.com, a@team.io"))
+}
+
+func TestRedactor_ZeroValue_ShouldKeepValues(t *testing.T) {
+	rd := &middleware.Redactor{}
+
+	assert.Equal(t, "john@example.com", rd.Redact("authorization", "john@example.com"))
+}
+
+func TestHeadersLog_ShouldLogRedactedHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/json")
+	rd := middleware.NewRedactor([]string{"authorization"}, false)
+
+	arg, ok := middleware.HeadersLog(rd)(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "headers", arg.Key)
+	assert.Equal(t, "[Accept=text/html, application/json Authorization=[REDACTED]]", arg.Value.String())
+}
+
+func TestQueryLog_ShouldLogRedactedQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?token=secret&email=john@example.com&page=2", nil)
+	rd := middleware.NewRedactor([]string{"token"}, true)
+
+	arg, ok := middleware.QueryLog(rd)(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "query", arg.Key)
+	assert.Equal(t, "[email=j***@example.com page=2 token=[REDACTED]]", arg.Value.String())
+}
+
+func TestQueryLog_NoQuery_ShouldNotOk(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, ok := middleware.QueryLog(middleware.NewRedactor(nil, false))(req)
+
+	assert.False(t, ok)
+}
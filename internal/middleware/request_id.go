@@ -4,10 +4,20 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/asmazovec/team-agile/internal/idgen"
 	"github.com/google/uuid"
 )
 
-type requestID struct{}
+// DefaultRequestIDHeader is a header used to exchange request id by default.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+type (
+	requestID         struct{}
+	originalRequestID struct{}
+)
+
+// maxOriginalRequestIDLen limits size of recorded rejected request id.
+const maxOriginalRequestIDLen = 128
 
 // WithRequestID injects request id into the context.
 // Generates new UUID if id is empty string.
@@ -28,12 +38,131 @@ func RequestIDFrom(ctx context.Context) string {
 	return reqID
 }
 
+// OriginalRequestIDFrom extracts incoming request id replaced by the RequestIDWith middleware.
+// Returns empty string if incoming request id was accepted or absent.
+func OriginalRequestIDFrom(ctx context.Context) string {
+	reqID, _ := ctx.Value(originalRequestID{}).(string)
+	return reqID
+}
+
+type requestIDConfig struct {
+	header   string
+	generate idgen.Generator
+	accept   RequestIDPolicy
+}
+
+// RequestIDOption configures RequestIDWith middleware.
+type RequestIDOption func(*requestIDConfig)
+
+// RequestIDHeader sets a header name to read and echo request id.
+// Empty name keeps the DefaultRequestIDHeader.
+func RequestIDHeader(name string) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		if name == "" {
+			return
+		}
+		cfg.header = name
+	}
+}
+
+// RequestIDGenerate sets a generator for new request ids.
+// Nil generator keeps the default UUID version 4 generator.
+func RequestIDGenerate(g idgen.Generator) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		if g == nil {
+			return
+		}
+		cfg.generate = g
+	}
+}
+
+// RequestIDAccept sets a policy for incoming request ids.
+// Nil policy keeps the default AcceptUUIDRequestID policy.
+func RequestIDAccept(p RequestIDPolicy) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		if p == nil {
+			return
+		}
+		cfg.accept = p
+	}
+}
+
 // RequestID middleware injects request id for each request.
 // It searches for X-Request-ID header in request, if not present, generates a new one.
+// Request id is echoed back in the X-Request-ID response header.
 func RequestID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		reqID := r.Header.Get("X-Request-ID")
-		r = r.WithContext(WithRequestID(r.Context(), reqID))
-		next.ServeHTTP(w, r)
-	})
+	return RequestIDWith()(next)
+}
+
+// RequestIDWith middleware injects request id for each request configured with options.
+// Incoming request id rejected by the policy is replaced with a generated one
+// and could be extracted with OriginalRequestIDFrom.
+// Request id is echoed back in the response header with the same name.
+func RequestIDWith(opts ...RequestIDOption) func(http.Handler) http.Handler {
+	cfg := requestIDConfig{
+		header:   DefaultRequestIDHeader,
+		generate: idgen.UUIDv4,
+		accept:   AcceptUUIDRequestID,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			reqID := r.Header.Get(cfg.header)
+			if reqID != "" && !cfg.accept(reqID) {
+				ctx = context.WithValue(ctx, originalRequestID{}, truncate(reqID, maxOriginalRequestIDLen))
+				reqID = ""
+			}
+			if reqID == "" {
+				reqID = cfg.generate()
+			}
+
+			ctx = context.WithValue(ctx, requestID{}, reqID)
+			w.Header().Set(cfg.header, reqID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDTransport is an http.RoundTripper propagating request id
+// from the outgoing request context to the request header.
+// Zero value uses http.DefaultTransport and DefaultRequestIDHeader.
+type RequestIDTransport struct {
+	Base   http.RoundTripper
+	Header string
+}
+
+// RoundTrip implements http.RoundTripper interface.
+// Request id already set in the request header is never overridden.
+func (t *RequestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	header := t.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	reqID := RequestIDFrom(r.Context())
+	if reqID == "" || r.Header.Get(header) != "" {
+		return base.RoundTrip(r)
+	}
+
+	r = r.Clone(r.Context())
+	r.Header.Set(header, reqID)
+	return base.RoundTrip(r)
+}
+
+func truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	return s[:size]
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDPolicy decides whether an incoming request id is acceptable.
+type RequestIDPolicy func(id string) bool
+
+// Request id policy names supported by RequestIDPolicyByName.
+const (
+	PolicyNameSafe       = "safe"
+	PolicyNameUUID       = "uuid"
+	PolicyNameRegenerate = "regenerate"
+)
+
+// RequestIDPolicyByName returns a policy registered under the name.
+// The maxLen is used only by the safe policy.
+func RequestIDPolicyByName(name string, maxLen int) (RequestIDPolicy, error) {
+	switch name {
+	case PolicyNameSafe:
+		return AcceptSafeRequestID(maxLen), nil
+	case PolicyNameUUID:
+		return AcceptUUIDRequestID, nil
+	case PolicyNameRegenerate:
+		return RegenerateRequestID, nil
+	default:
+		return nil, fmt.Errorf("unknown request id policy %q", name)
+	}
+}
+
+// AcceptSafeRequestID accepts any non-empty id up to maxLen characters
+// consisting of ASCII letters, digits and "-", "_", ".", ":" symbols.
+func AcceptSafeRequestID(maxLen int) RequestIDPolicy {
+	return func(id string) bool {
+		if id == "" || len(id) > maxLen {
+			return false
+		}
+		for _, c := range []byte(id) {
+			if !isSafeRequestIDChar(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AcceptUUIDRequestID accepts only correct UUIDs.
+func AcceptUUIDRequestID(id string) bool {
+	return uuid.Validate(id) == nil
+}
+
+// RegenerateRequestID never accepts incoming ids.
+func RegenerateRequestID(string) bool {
+	return false
+}
+
+func isSafeRequestIDChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	case c == '-', c == '_', c == '.', c == ':':
+		return true
+	default:
+		return false
+	}
+}
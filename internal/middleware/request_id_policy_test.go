@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDPolicyByName_Known_ShouldReturnPolicy(t *testing.T) {
+	for _, name := range []string{
+		middleware.PolicyNameSafe, middleware.PolicyNameUUID, middleware.PolicyNameRegenerate,
+	} {
+		p, err := middleware.RequestIDPolicyByName(name, 64)
+
+		require.NoError(t, err, name)
+		assert.NotNil(t, p, name)
+	}
+}
+
+func TestRequestIDPolicyByName_Unknown_ShouldError(t *testing.T) {
+	p, err := middleware.RequestIDPolicyByName("unknown", 64)
+
+	assert.Nil(t, p)
+	assert.Error(t, err)
+}
+
+func TestAcceptSafeRequestID_ShouldAcceptSafeCharset(t *testing.T) {
+	p := middleware.AcceptSafeRequestID(64)
+
+	assert.True(t, p("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+	assert.True(t, p("gw-1:req_42.a"))
+	assert.True(t, p(uuid.NewString()))
+}
+
+func TestAcceptSafeRequestID_ShouldRejectUnsafe(t *testing.T) {
+	p := middleware.AcceptSafeRequestID(8)
+
+	assert.False(t, p(""))
+	assert.False(t, p("123456789"))
+	assert.False(t, p("a b"))
+	assert.False(t, p("a\nb"))
+	assert.False(t, p(strings.Repeat("ä", 2)))
+}
+
+func TestAcceptUUIDRequestID_ShouldAcceptOnlyUUID(t *testing.T) {
+	assert.True(t, middleware.AcceptUUIDRequestID(uuid.NewString()))
+	assert.False(t, middleware.AcceptUUIDRequestID("123asdf"))
+}
+
+func TestRegenerateRequestID_ShouldRejectAll(t *testing.T) {
+	assert.False(t, middleware.RegenerateRequestID(uuid.NewString()))
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/asmazovec/team-agile/internal/middleware"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithRequestID_ShouldInjectRequestID(t *testing.T) {
@@ -75,3 +77,165 @@ func TestRequestID_ServeHTTP_WithRequestIDInRequest_ShouldNotGenerate(t *testing
 
 	mw.ServeHTTP(httptest.NewRecorder(), req)
 }
+
+func TestRequestID_ServeHTTP_ShouldEchoRequestID(t *testing.T) {
+	id := uuid.NewString()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", id)
+	rec := httptest.NewRecorder()
+	mw := middleware.RequestID(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	mw.ServeHTTP(rec, req)
+
+	assert.Equal(t, id, rec.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDWith_CustomHeader_ShouldReadAndEcho(t *testing.T) {
+	id := uuid.NewString()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Correlation-ID", id)
+	rec := httptest.NewRecorder()
+	nextHandler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, id, middleware.RequestIDFrom(r.Context()))
+	})
+	mw := middleware.RequestIDWith(middleware.RequestIDHeader("X-Correlation-ID"))(nextHandler)
+
+	mw.ServeHTTP(rec, req)
+
+	assert.Equal(t, id, rec.Header().Get("X-Correlation-ID"))
+	assert.Empty(t, rec.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDWith_EmptyHeaderAndNilOption_ShouldUseDefault(t *testing.T) {
+	rec := httptest.NewRecorder()
+	mw := middleware.RequestIDWith(middleware.RequestIDHeader(""), nil)(
+		http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	mw.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NoError(t, uuid.Validate(rec.Header().Get("X-Request-ID")))
+}
+
+type RoundTripperMock struct {
+	req *http.Request
+}
+
+func (m *RoundTripperMock) RoundTrip(r *http.Request) (*http.Response, error) {
+	m.req = r
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+}
+
+func TestRequestIDTransport_ShouldInjectRequestID(t *testing.T) {
+	id := uuid.NewString()
+	base := &RoundTripperMock{}
+	tr := &middleware.RequestIDTransport{Base: base}
+	ctx := middleware.WithRequestID(context.Background(), id)
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Equal(t, id, base.req.Header.Get("X-Request-ID"))
+	assert.Empty(t, req.Header.Get("X-Request-ID"), "Should not mutate original request")
+}
+
+func TestRequestIDTransport_CustomHeader_ShouldInjectRequestID(t *testing.T) {
+	id := uuid.NewString()
+	base := &RoundTripperMock{}
+	tr := &middleware.RequestIDTransport{Base: base, Header: "X-Correlation-ID"}
+	ctx := middleware.WithRequestID(context.Background(), id)
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Equal(t, id, base.req.Header.Get("X-Correlation-ID"))
+}
+
+func TestRequestIDTransport_HeaderAlreadySet_ShouldNotOverride(t *testing.T) {
+	id := uuid.NewString()
+	base := &RoundTripperMock{}
+	tr := &middleware.RequestIDTransport{Base: base}
+	ctx := middleware.WithRequestID(context.Background(), uuid.NewString())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	req.Header.Set("X-Request-ID", id)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Equal(t, id, base.req.Header.Get("X-Request-ID"))
+}
+
+func TestRequestIDTransport_NoRequestID_ShouldPassThrough(t *testing.T) {
+	base := &RoundTripperMock{}
+	tr := &middleware.RequestIDTransport{Base: base}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Same(t, req, base.req)
+}
+
+func TestRequestIDWith_Generator_ShouldUseGenerator(t *testing.T) {
+	rec := httptest.NewRecorder()
+	mw := middleware.RequestIDWith(middleware.RequestIDGenerate(func() string { return "generated" }))(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "generated", middleware.RequestIDFrom(r.Context()))
+		}))
+
+	mw.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "generated", rec.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDWith_AcceptedID_ShouldNotRecordOriginal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "gateway-42")
+	mw := middleware.RequestIDWith(middleware.RequestIDAccept(middleware.AcceptSafeRequestID(64)))(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "gateway-42", middleware.RequestIDFrom(r.Context()))
+			assert.Empty(t, middleware.OriginalRequestIDFrom(r.Context()))
+		}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRequestIDWith_RejectedID_ShouldRecordOriginal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "gateway-42")
+	mw := middleware.RequestIDWith(middleware.RequestIDAccept(middleware.RegenerateRequestID), nil)(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, uuid.Validate(middleware.RequestIDFrom(r.Context())))
+			assert.Equal(t, "gateway-42", middleware.OriginalRequestIDFrom(r.Context()))
+		}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRequestIDWith_LongRejectedID_ShouldTruncateOriginal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", strings.Repeat("a", 1024))
+	mw := middleware.RequestIDWith()(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			assert.Len(t, middleware.OriginalRequestIDFrom(r.Context()), 128)
+		}))
+
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRequestIDWith_NilGeneratorAndPolicy_ShouldUseDefaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "123asdf")
+	rec := httptest.NewRecorder()
+	mw := middleware.RequestIDWith(middleware.RequestIDGenerate(nil), middleware.RequestIDAccept(nil))(
+		http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	mw.ServeHTTP(rec, req)
+
+	assert.NoError(t, uuid.Validate(rec.Header().Get("X-Request-ID")))
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+)
+
+// Sampler decides which completed requests are logged.
+// Error responses are always logged, successful ones are logged with the rate
+// of the route pattern or the default rate.
+type Sampler struct {
+	rate    float64
+	routes  map[string]float64
+	logged  atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// NewSampler creates sampler logging fraction of successful requests.
+// Rates are in range [0, 1], routes override the default rate by chi route pattern.
+func NewSampler(rate float64, routes map[string]float64) *Sampler {
+	return &Sampler{rate: rate, routes: routes}
+}
+
+// Sample reports whether the completed request should be logged.
+func (s *Sampler) Sample(r *http.Request, status int) bool {
+	rate := s.rate
+	if len(s.routes) != 0 {
+		if routeRate, ok := s.routes[routePattern(r)]; ok {
+			rate = routeRate
+		}
+	}
+
+	if status >= http.StatusBadRequest || rate >= 1 || rand.Float64() < rate { //nolint:gosec // sampling is not security sensitive
+		s.logged.Add(1)
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Logged returns number of sampled log lines.
+func (s *Sampler) Logged() uint64 {
+	return s.logged.Load()
+}
+
+// Dropped returns number of dropped log lines.
+func (s *Sampler) Dropped() uint64 {
+	return s.dropped.Load()
+}
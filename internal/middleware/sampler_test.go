@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_Sample_Errors_ShouldAlwaysLog(t *testing.T) {
+	s := middleware.NewSampler(0, nil)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	assert.True(t, s.Sample(req, http.StatusBadRequest))
+	assert.True(t, s.Sample(req, http.StatusInternalServerError))
+	assert.Equal(t, uint64(2), s.Logged())
+}
+
+func TestSampler_Sample_ZeroRate_ShouldDropSuccess(t *testing.T) {
+	s := middleware.NewSampler(0, nil)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	assert.False(t, s.Sample(req, http.StatusOK))
+	assert.Equal(t, uint64(1), s.Dropped())
+}
+
+func TestSampler_Sample_FullRate_ShouldLogSuccess(t *testing.T) {
+	s := middleware.NewSampler(1, nil)
+
+	assert.True(t, s.Sample(httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK))
+}
+
+func TestSampler_Sample_Fraction_ShouldLogApproximately(t *testing.T) {
+	s := middleware.NewSampler(0.5, nil)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for range 1000 {
+		s.Sample(req, http.StatusOK)
+	}
+
+	assert.InDelta(t, 500, s.Logged(), 150)
+	assert.Equal(t, uint64(1000), s.Logged()+s.Dropped())
+}
+
+func TestSampler_Sample_RouteRate_ShouldOverrideDefault(t *testing.T) {
+	s := middleware.NewSampler(1, map[string]float64{"/livez": 0})
+	router := chi.NewRouter()
+	var sampled bool
+	router.Get("/livez", func(_ http.ResponseWriter, r *http.Request) {
+		sampled = s.Sample(r, http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.False(t, sampled)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+)
+
+// Timeout middleware limits handler execution by the timeout propagated through the request context.
+// Response is buffered until the handler completes, the handler starts with headers set by outer middlewares.
+// Handler exceeding the timeout is answered with 504 Gateway Timeout,
+// request canceled by the client is answered with 503 Service Unavailable.
+// Writes of the abandoned handler fail with http.ErrHandlerTimeout.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	detail := "request processing exceeded " + timeout.String()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{header: w.Header().Clone()}
+			done := make(chan struct{})
+			panicCh := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicCh <- p
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicCh:
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				// Buffered header started as a copy of the response header, so it replaces the header as a whole.
+				dst := w.Header()
+				for k := range dst {
+					if _, ok := tw.header[k]; !ok {
+						delete(dst, k)
+					}
+				}
+				for k, v := range tw.header {
+					dst[k] = v
+				}
+				w.WriteHeader(tw.status())
+				_, _ = w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					problem.Write(w, r, problem.New(http.StatusGatewayTimeout, detail))
+					return
+				}
+				problem.Write(w, r, problem.New(http.StatusServiceUnavailable, "request canceled"))
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers response of the handler limited by Timeout middleware.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) status() int {
+	if tw.code == 0 {
+		return http.StatusOK
+	}
+	return tw.code
+}
@@ -0,0 +1,114 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout_FastHandler_ShouldCopyResponse(t *testing.T) {
+	h := middleware.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		w.Header().Set("Location", "/tasks/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "created")
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/tasks/1", rec.Header().Get("Location"))
+	assert.Equal(t, "created", rec.Body.String())
+}
+
+func TestTimeout_FastHandler_ShouldKeepOuterHeaders(t *testing.T) {
+	h := middleware.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec := httptest.NewRecorder()
+	rec.Header().Add("Vary", "Origin")
+	rec.Header().Set("Content-Type", "text/plain")
+	rec.Header().Set("X-Request-ID", "r1")
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"Origin", "Accept"}, rec.Header().Values("Vary"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "r1", rec.Header().Get("X-Request-ID"))
+}
+
+func TestTimeout_BehindCORSAndCompress_ShouldKeepVary(t *testing.T) {
+	handler := middleware.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"title":"task"}`)
+	}))
+	handler = middleware.Compress(middleware.CompressOptions{
+		Encodings:    []string{"gzip"},
+		ContentTypes: []string{"application/json"},
+	})(handler)
+	handler = middleware.CORS(middleware.CORSOptions{AllowedOrigins: []string{"*"}})(handler)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Origin", "https://board.example.com")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, []string{"Origin", "Accept-Encoding", "Accept"}, rec.Header().Values("Vary"))
+}
+
+func TestTimeout_SlowHandler_ShouldGatewayTimeout(t *testing.T) {
+	writeErr := make(chan error, 1)
+	h := middleware.Timeout(time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+		_, err := io.WriteString(w, "late")
+		writeErr <- err
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+}
+
+func TestTimeout_CanceledRequest_ShouldServiceUnavailable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := middleware.Timeout(time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestTimeout_PanickingHandler_ShouldPropagatePanic(t *testing.T) {
+	errPanic := errors.New("boom")
+	h := middleware.Timeout(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(errPanic)
+	}))
+
+	require.PanicsWithValue(t, errPanic, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is an instrumentation scope name of the package spans.
+const tracerName = "github.com/asmazovec/team-agile/internal/middleware"
+
+// Tracing middleware starts a server span for each request continuing
+// a remote trace from the request headers.
+// Span is named by the chi route pattern once the request has been routed.
+func Tracing(tp trace.TracerProvider, p propagation.TextMapPropagator) func(http.Handler) http.Handler {
+	tracer := tp.Tracer(tracerName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := p.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			if pattern := routePattern(r); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(semconv.HTTPRoute(pattern))
+			}
+		})
+	}
+}
+
+// TraceIDLog logger extension for log trace id of the request span.
+func TraceIDLog(r *http.Request) (slog.Attr, bool) {
+	sc := trace.SpanContextFromContext(r.Context())
+	if !sc.HasTraceID() {
+		return slog.Attr{}, false
+	}
+	return slog.String("trace-id", sc.TraceID().String()), true
+}
+
+// SpanIDLog logger extension for log span id of the request span.
+func SpanIDLog(r *http.Request) (slog.Attr, bool) {
+	sc := trace.SpanContextFromContext(r.Context())
+	if !sc.HasSpanID() {
+		return slog.Attr{}, false
+	}
+	return slog.String("span-id", sc.SpanID().String()), true
+}
+
+// TracingTransport is an http.RoundTripper emitting trace context
+// of the outgoing request context into the traceparent and tracestate headers.
+// Zero value uses http.DefaultTransport and does nothing with headers.
+type TracingTransport struct {
+	Base       http.RoundTripper
+	Propagator propagation.TextMapPropagator
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *TracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Propagator == nil || !trace.SpanContextFromContext(r.Context()).IsValid() {
+		return base.RoundTrip(r)
+	}
+
+	r = r.Clone(r.Context())
+	t.Propagator.Inject(r.Context(), propagation.HeaderCarrier(r.Header))
+	return base.RoundTrip(r)
+}
+
+// routePattern returns chi route pattern of the routed request.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
@@ -0,0 +1,131 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newTracedRouter(h http.HandlerFunc) (*tracetest.SpanRecorder, http.Handler) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	router := chi.NewRouter()
+	router.Use(middleware.Tracing(tp, tracing.NewPropagator()))
+	router.Get("/tasks/{id}", h)
+	return sr, router
+}
+
+func TestTracing_ServeHTTP_ShouldNameSpanByRoutePattern(t *testing.T) {
+	sr, router := newTracedRouter(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/42", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /tasks/{id}", spans[0].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+}
+
+func TestTracing_ServeHTTP_ShouldContinueRemoteTrace(t *testing.T) {
+	sr, router := newTracedRouter(func(_ http.ResponseWriter, _ *http.Request) {})
+	req := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+	req.Header.Set("Traceparent", traceparent)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+func TestTracing_ServeHTTP_ServerError_ShouldSetErrorStatus(t *testing.T) {
+	sr, router := newTracedRouter(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/42", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestTracing_ServeHTTP_ShouldInjectSpanInContext(t *testing.T) {
+	_, router := newTracedRouter(func(_ http.ResponseWriter, r *http.Request) {
+		assert.True(t, trace.SpanContextFromContext(r.Context()).IsValid())
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/42", nil))
+}
+
+func TestTraceIDLog_ShouldLogTraceAndSpanID(t *testing.T) {
+	_, router := newTracedRouter(func(_ http.ResponseWriter, r *http.Request) {
+		sc := trace.SpanContextFromContext(r.Context())
+
+		traceArg, traceOk := middleware.TraceIDLog(r)
+		spanArg, spanOk := middleware.SpanIDLog(r)
+
+		assert.True(t, traceOk)
+		assert.Equal(t, "trace-id", traceArg.Key)
+		assert.Equal(t, sc.TraceID().String(), traceArg.Value.String())
+		assert.True(t, spanOk)
+		assert.Equal(t, "span-id", spanArg.Key)
+		assert.Equal(t, sc.SpanID().String(), spanArg.Value.String())
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/42", nil))
+}
+
+func TestTraceIDLog_WithNoSpan_ShouldNotOk(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, traceOk := middleware.TraceIDLog(req)
+	_, spanOk := middleware.SpanIDLog(req)
+
+	assert.False(t, traceOk)
+	assert.False(t, spanOk)
+}
+
+func TestTracingTransport_ShouldEmitTraceparent(t *testing.T) {
+	base := &RoundTripperMock{}
+	p := tracing.NewPropagator()
+	tr := &middleware.TracingTransport{Base: base, Propagator: p}
+	in := http.Header{}
+	in.Set("Traceparent", traceparent)
+	ctx := p.Extract(context.Background(), propagation.HeaderCarrier(in))
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Equal(t, traceparent, base.req.Header.Get("Traceparent"))
+	assert.Empty(t, req.Header.Get("Traceparent"), "Should not mutate original request")
+}
+
+func TestTracingTransport_NoSpan_ShouldPassThrough(t *testing.T) {
+	base := &RoundTripperMock{}
+	tr := &middleware.TracingTransport{Base: base, Propagator: tracing.NewPropagator()}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	res, err := tr.RoundTrip(req)
+	_ = res.Body.Close()
+
+	require.NoError(t, err)
+	assert.Same(t, req, base.req)
+}
@@ -0,0 +1,82 @@
+package problem
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+)
+
+// ContentType is a media type of problem details responses.
+const ContentType = "application/problem+json"
+
+// Details is a problem details response described by RFC 9457.
+type Details struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// New creates problem details of the status with "about:blank" type.
+func New(status int, detail string) *Details {
+	return &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// With adds extension member to the problem details.
+func (d *Details) With(key string, val any) *Details {
+	if d.Extensions == nil {
+		d.Extensions = make(map[string]any)
+	}
+	d.Extensions[key] = val
+	return d
+}
+
+// Error implements error interface.
+func (d *Details) Error() string {
+	if d.Detail == "" {
+		return d.Title
+	}
+	return d.Title + ": " + d.Detail
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// Extension members are placed next to the standard ones, never overriding them.
+func (d *Details) MarshalJSON() ([]byte, error) {
+	m := maps.Clone(d.Extensions)
+	if m == nil {
+		m = make(map[string]any, 5) //nolint:mnd // number of standard members
+	}
+	m["type"] = d.Type
+	m["title"] = d.Title
+	m["status"] = d.Status
+	if d.Detail != "" {
+		m["detail"] = d.Detail
+	} else {
+		delete(m, "detail")
+	}
+	if d.Instance != "" {
+		m["instance"] = d.Instance
+	} else {
+		delete(m, "instance")
+	}
+	return json.Marshal(m)
+}
+
+// Write writes problem details response.
+// Instance defaults to the request path.
+func Write(w http.ResponseWriter, r *http.Request, d *Details) {
+	if d.Instance == "" && r != nil {
+		d.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(d.Status)
+	_ = json.NewEncoder(w).Encode(d)
+}
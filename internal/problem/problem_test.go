@@ -0,0 +1,47 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_ShouldUseStatusTitle(t *testing.T) {
+	d := problem.New(http.StatusTooManyRequests, "slow down")
+
+	assert.Equal(t, "about:blank", d.Type)
+	assert.Equal(t, "Too Many Requests", d.Title)
+	assert.Equal(t, http.StatusTooManyRequests, d.Status)
+	assert.Equal(t, "Too Many Requests: slow down", d.Error())
+}
+
+func TestDetails_MarshalJSON_ShouldMergeExtensions(t *testing.T) {
+	d := problem.New(http.StatusConflict, "").With("allowed", []string{"done"}).With("status", 0)
+
+	b, err := json.Marshal(d)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Conflict","status":409,"allowed":["done"]}`, string(b))
+}
+
+func TestWrite_ShouldWriteProblemResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+
+	problem.Write(rec, req, problem.New(http.StatusNotFound, "task not found"))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t,
+		`{"type":"about:blank","title":"Not Found","status":404,"detail":"task not found","instance":"/tasks/1"}`,
+		rec.Body.String())
+}
+
+func TestDetails_Error_NoDetail_ShouldTitle(t *testing.T) {
+	assert.Equal(t, "Not Found", problem.New(http.StatusNotFound, "").Error())
+}
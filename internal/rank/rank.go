@@ -0,0 +1,143 @@
+// Package rank generates lexicographic ranks ordering items without renumbering neighbours.
+//
+// A rank is a string of base62 digits ordered by byte comparison. A new rank between any two
+// ranks always exists, so moving an item changes the rank of this item only.
+// Ranks never end with the lowest digit to keep room before them.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	// ErrInvalid means rank contains unknown digits or ends with the lowest digit.
+	ErrInvalid = errors.New("invalid rank")
+	// ErrOrder means lower bound is not less than upper bound.
+	ErrOrder = errors.New("rank bounds are out of order")
+)
+
+// Valid reports whether the rank can be used as a bound.
+func Valid(rank string) bool {
+	if rank == "" || rank[len(rank)-1] == digits[0] {
+		return false
+	}
+	for i := range len(rank) {
+		if strings.IndexByte(digits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a rank strictly between a and b.
+// Empty a means no lower bound, empty b means no upper bound,
+// so Between("", "") returns an initial rank.
+// Ranks after the last or before the first rank are the closest short ones
+// to keep ranks short on repeated appends to an end.
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", ErrInvalid
+	}
+	switch {
+	case a != "" && b != "":
+		if a >= b {
+			return "", ErrOrder
+		}
+	case a != "":
+		return increment(a), nil
+	case b != "":
+		return decrement(b), nil
+	}
+	return midpoint(a, b), nil
+}
+
+// Spread returns n ascending ranks between a and b with the same bounds semantics as Between.
+// Ranks are generated by bisection, so their length grows with the logarithm of n
+// rather than with n as on repeated insertions next to the same rank.
+func Spread(a, b string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	mid, err := Between(a, b)
+	if err != nil {
+		return nil, err
+	}
+	left, err := Spread(a, mid, n/2)
+	if err != nil {
+		return nil, err
+	}
+	right, err := Spread(mid, b, n-n/2-1)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(left, mid), right...), nil
+}
+
+// increment returns the shortest rank after a by incrementing its first digit below the highest.
+func increment(a string) string {
+	for i := range len(a) {
+		if d := strings.IndexByte(digits, a[i]); d < len(digits)-1 {
+			return a[:i] + string(digits[d+1])
+		}
+	}
+	return midpoint(a, "")
+}
+
+// decrement returns the shortest rank before b by decrementing its first digit above the lowest two.
+func decrement(b string) string {
+	for i := range len(b) {
+		if d := strings.IndexByte(digits, b[i]); d > 1 {
+			return b[:i] + string(digits[d-1])
+		}
+	}
+	return midpoint("", b)
+}
+
+// midpoint returns a rank between valid ordered bounds.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Skip the common prefix, the lower bound is padded with the lowest digits.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	hi := len(digits)
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+	// Adjacent leading digits: the first digit of the longer upper bound is already between.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[lo]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
@@ -0,0 +1,109 @@
+package rank_test
+
+import (
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/rank"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"", "", "V"},
+		{"V", "", "W"},
+		{"", "V", "U"},
+		{"A", "C", "B"},
+		{"A", "B", "AV"},
+		{"", "1", "0V"},
+		{"z", "", "zV"},
+		{"zy", "", "zz"},
+		{"1V", "", "2"},
+		{"", "1V", "1U"},
+		{"AV", "B", "Ak"},
+		{"A1", "A2", "A1V"},
+		{"A", "A01", "A00V"},
+	}
+	for _, tt := range tests {
+		got, err := rank.Between(tt.a, tt.b)
+
+		require.NoError(t, err, "%q..%q", tt.a, tt.b)
+		assert.Equal(t, tt.want, got, "%q..%q", tt.a, tt.b)
+		assert.True(t, rank.Valid(got))
+	}
+}
+
+func TestBetween_Invalid_ShouldError(t *testing.T) {
+	_, err := rank.Between("B", "A")
+	assert.ErrorIs(t, err, rank.ErrOrder)
+
+	_, err = rank.Between("A", "A")
+	assert.ErrorIs(t, err, rank.ErrOrder)
+
+	_, err = rank.Between("A0", "")
+	assert.ErrorIs(t, err, rank.ErrInvalid)
+
+	_, err = rank.Between("", "a-b")
+	assert.ErrorIs(t, err, rank.ErrInvalid)
+}
+
+func TestBetween_RepeatedInserts_ShouldKeepOrder(t *testing.T) {
+	lo, hi := "", ""
+	ranks := []string{}
+	for i := range 200 {
+		r, err := rank.Between(lo, hi)
+		require.NoError(t, err)
+		ranks = append(ranks, r)
+		// Alternate inserting right after the lower bound and right before the upper bound.
+		if i%2 == 0 {
+			hi = r
+		} else {
+			lo = r
+		}
+	}
+	for i := 1; i < len(ranks); i++ {
+		assert.NotEqual(t, ranks[i-1], ranks[i])
+	}
+	assert.Less(t, lo, hi)
+}
+
+func TestBetween_AppendToEnd_ShouldGrowSlowly(t *testing.T) {
+	last := ""
+	for range 1000 {
+		r, err := rank.Between(last, "")
+		require.NoError(t, err)
+		require.Greater(t, r, last)
+		last = r
+	}
+	assert.LessOrEqual(t, len(last), 40)
+}
+
+func TestSpread_ShouldReturnShortAscendingRanks(t *testing.T) {
+	for _, bounds := range [][2]string{{"", ""}, {"A", "B"}, {"V", ""}, {"", "1"}} {
+		ranks, err := rank.Spread(bounds[0], bounds[1], 1000)
+		require.NoError(t, err)
+
+		require.Len(t, ranks, 1000)
+		prev := bounds[0]
+		for _, r := range ranks {
+			require.Greater(t, r, prev)
+			require.True(t, rank.Valid(r))
+			assert.LessOrEqual(t, len(r), 6)
+			prev = r
+		}
+		if bounds[1] != "" {
+			assert.Less(t, prev, bounds[1])
+		}
+	}
+}
+
+func TestSpread_Invalid_ShouldError(t *testing.T) {
+	_, err := rank.Spread("B", "A", 2)
+	empty, none := rank.Spread("A", "B", 0)
+
+	assert.ErrorIs(t, err, rank.ErrOrder)
+	assert.Empty(t, empty)
+	assert.NoError(t, none)
+}
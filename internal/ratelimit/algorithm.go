@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Algorithm names supported by AlgorithmByName.
+const (
+	NameTokenBucket   = "token-bucket"
+	NameSlidingWindow = "sliding-window"
+)
+
+const stateLen = 16
+
+// AlgorithmByName returns algorithm registered under the name.
+func AlgorithmByName(name string) (Algorithm, error) {
+	switch name {
+	case NameTokenBucket:
+		return TokenBucket, nil
+	case NameSlidingWindow:
+		return SlidingWindow, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit algorithm %q", name)
+	}
+}
+
+// TokenBucket allows bursts up to the rule limit refilling the limit of tokens each window.
+// State is the number of tokens and the last refill time.
+func TokenBucket(state []byte, rule Rule, now time.Time) ([]byte, Result) {
+	capacity := float64(rule.Limit)
+	perNano := capacity / float64(rule.Window)
+
+	tokens, last := capacity, now.UnixNano()
+	if len(state) == stateLen {
+		tokens = math.Float64frombits(binary.BigEndian.Uint64(state[:8]))
+		last = int64(binary.BigEndian.Uint64(state[8:])) //nolint:gosec // encoded from int64
+	}
+	if elapsed := now.UnixNano() - last; elapsed > 0 {
+		tokens = math.Min(capacity, tokens+float64(elapsed)*perNano)
+	}
+
+	res := Result{Limit: rule.Limit}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - tokens) / perNano))
+	}
+	res.Remaining = int(tokens)
+	res.Reset = time.Duration(math.Ceil((capacity - tokens) / perNano))
+
+	return encodeState(math.Float64bits(tokens), uint64(now.UnixNano())), res //nolint:gosec // decoded as int64
+}
+
+// SlidingWindow approximates number of requests in the sliding window
+// weighting the previous fixed window count by its overlap with the sliding window.
+// State is the current fixed window start, the previous and the current window counts.
+func SlidingWindow(state []byte, rule Rule, now time.Time) ([]byte, Result) {
+	window := rule.Window
+	start := now.Truncate(window)
+
+	var prev, curr uint64
+	if len(state) == stateLen {
+		stored := time.Unix(0, int64(binary.BigEndian.Uint64(state[:8]))) //nolint:gosec // encoded from int64
+		counts := binary.BigEndian.Uint64(state[8:])
+		switch {
+		case stored.Equal(start):
+			prev, curr = counts>>32, counts&math.MaxUint32
+		case stored.Equal(start.Add(-window)):
+			prev = counts & math.MaxUint32
+		}
+	}
+
+	elapsed := float64(now.Sub(start)) / float64(window)
+	estimated := float64(prev)*(1-elapsed) + float64(curr)
+	limit := float64(rule.Limit)
+
+	res := Result{Limit: rule.Limit, Reset: start.Add(window).Sub(now)}
+	if estimated+1 <= limit {
+		curr++
+		res.Allowed = true
+		res.Remaining = int(limit - estimated - 1)
+	} else {
+		res.RetryAfter = slidingRetryAfter(prev, curr, limit, elapsed, window)
+	}
+
+	counts := prev<<32 | curr&math.MaxUint32
+	return encodeState(uint64(start.UnixNano()), counts), res //nolint:gosec // decoded as int64
+}
+
+// slidingRetryAfter finds the earliest moment the estimated count allows one more request.
+func slidingRetryAfter(prev, curr uint64, limit, elapsed float64, window time.Duration) time.Duration {
+	if float64(curr) <= limit-1 && prev > 0 {
+		// Previous window weight decays enough within the current window.
+		x := 1 - (limit-1-float64(curr))/float64(prev)
+		return time.Duration(math.Ceil((x - elapsed) * float64(window)))
+	}
+	// Wait for the next window where the current count becomes the previous one.
+	x := 0.0
+	if curr > 0 {
+		x = math.Max(0, 1-(limit-1)/float64(curr))
+	}
+	return time.Duration(math.Ceil((1 - elapsed + x) * float64(window)))
+}
+
+func encodeState(a, b uint64) []byte {
+	state := make([]byte, stateLen)
+	binary.BigEndian.PutUint64(state[:8], a)
+	binary.BigEndian.PutUint64(state[8:], b)
+	return state
+}
@@ -0,0 +1,106 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgorithmByName_Known_ShouldReturn(t *testing.T) {
+	for _, name := range []string{ratelimit.NameTokenBucket, ratelimit.NameSlidingWindow} {
+		alg, err := ratelimit.AlgorithmByName(name)
+
+		require.NoError(t, err)
+		assert.NotNil(t, alg)
+	}
+}
+
+func TestAlgorithmByName_Unknown_ShouldError(t *testing.T) {
+	_, err := ratelimit.AlgorithmByName("leaky")
+
+	assert.Error(t, err)
+}
+
+func TestTokenBucket_ShouldAllowBurstThenDeny(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 3, Window: 3 * time.Second}
+	now := time.Now()
+	var (
+		state []byte
+		res   ratelimit.Result
+	)
+
+	for i := range 3 {
+		state, res = ratelimit.TokenBucket(state, rule, now)
+		require.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+	_, res = ratelimit.TokenBucket(state, rule, now)
+
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+	assert.Equal(t, 3, res.Limit)
+}
+
+func TestTokenBucket_ShouldRefillOverTime(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 2, Window: 2 * time.Second}
+	now := time.Now()
+	state, _ := ratelimit.TokenBucket(nil, rule, now)
+	state, _ = ratelimit.TokenBucket(state, rule, now)
+
+	_, denied := ratelimit.TokenBucket(state, rule, now.Add(500*time.Millisecond))
+	_, allowed := ratelimit.TokenBucket(state, rule, now.Add(time.Second))
+
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+	assert.True(t, allowed.Allowed)
+}
+
+func TestSlidingWindow_ShouldLimitInWindow(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 2, Window: time.Minute}
+	start := time.Now().Truncate(time.Minute)
+	var (
+		state []byte
+		res   ratelimit.Result
+	)
+
+	state, res = ratelimit.SlidingWindow(state, rule, start)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	state, res = ratelimit.SlidingWindow(state, rule, start.Add(10*time.Second))
+	assert.True(t, res.Allowed)
+	_, res = ratelimit.SlidingWindow(state, rule, start.Add(20*time.Second))
+
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 70*time.Second, res.RetryAfter)
+	assert.Equal(t, 40*time.Second, res.Reset)
+}
+
+func TestSlidingWindow_ShouldWeightPreviousWindow(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 2, Window: time.Minute}
+	start := time.Now().Truncate(time.Minute)
+	state, _ := ratelimit.SlidingWindow(nil, rule, start)
+	state, _ = ratelimit.SlidingWindow(state, rule, start)
+
+	// Previous window count 2 weighted by 3/4 exceeds limit of a single more request.
+	_, denied := ratelimit.SlidingWindow(state, rule, start.Add(75*time.Second))
+	// Previous window count 2 weighted by 1/4 allows one more request.
+	_, allowed := ratelimit.SlidingWindow(state, rule, start.Add(105*time.Second))
+
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 15*time.Second, denied.RetryAfter)
+	assert.True(t, allowed.Allowed)
+}
+
+func TestSlidingWindow_AfterTwoWindows_ShouldReset(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 1, Window: time.Minute}
+	start := time.Now().Truncate(time.Minute)
+	state, _ := ratelimit.SlidingWindow(nil, rule, start)
+
+	_, res := ratelimit.SlidingWindow(state, rule, start.Add(2*time.Minute))
+
+	assert.True(t, res.Allowed)
+}
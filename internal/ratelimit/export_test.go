@@ -0,0 +1,13 @@
+package ratelimit
+
+import "time"
+
+// SetClock replaces clock of the store for testing purposes.
+func (s *MemoryStore) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// SetClock replaces clock of the limiter for testing purposes.
+func (l *Limiter) SetClock(now func() time.Time) {
+	l.now = now
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is a number of writes between expired entries sweeps.
+const sweepEvery = 1024
+
+type entry struct {
+	state   []byte
+	version uint64
+	expires time.Time
+}
+
+// MemoryStore is an in-memory Store for a single service instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryStore creates empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]entry), now: time.Now}
+}
+
+// Get implements Store interface.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, 0, nil
+	}
+	if !s.now().Before(e.expires) {
+		return nil, e.version, nil
+	}
+	return e.state, e.version, nil
+}
+
+// CompareAndSwap implements Store interface.
+func (s *MemoryStore) CompareAndSwap(
+	_ context.Context, key string, version uint64, state []byte, ttl time.Duration,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e := s.entries[key]; e.version != version {
+		return false, nil
+	}
+	now := s.now()
+	s.entries[key] = entry{state: state, version: version + 1, expires: now.Add(ttl)}
+
+	s.writes++
+	if s.writes%sweepEvery == 0 {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	return true, nil
+}
+
+// Len returns number of stored entries including expired ones not swept yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
@@ -0,0 +1,76 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Get_Absent_ShouldNil(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+
+	state, version, err := s.Get(context.Background(), "a")
+
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	assert.Zero(t, version)
+}
+
+func TestMemoryStore_CompareAndSwap_ShouldStore(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+
+	ok, err := s.CompareAndSwap(ctx, "a", 0, []byte("x"), time.Minute)
+	state, version, _ := s.Get(ctx, "a")
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("x"), state)
+	assert.Equal(t, uint64(1), version)
+}
+
+func TestMemoryStore_CompareAndSwap_StaleVersion_ShouldNotStore(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	_, _ = s.CompareAndSwap(ctx, "a", 0, []byte("x"), time.Minute)
+
+	ok, err := s.CompareAndSwap(ctx, "a", 0, []byte("y"), time.Minute)
+	state, _, _ := s.Get(ctx, "a")
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []byte("x"), state)
+}
+
+func TestMemoryStore_Get_Expired_ShouldNil(t *testing.T) {
+	now := time.Now()
+	s := ratelimit.NewMemoryStore()
+	s.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+	_, _ = s.CompareAndSwap(ctx, "a", 0, []byte("x"), time.Minute)
+
+	now = now.Add(time.Minute)
+	state, version, _ := s.Get(ctx, "a")
+
+	assert.Nil(t, state)
+	assert.Equal(t, uint64(1), version)
+}
+
+func TestMemoryStore_CompareAndSwap_ShouldSweepExpired(t *testing.T) {
+	now := time.Now()
+	s := ratelimit.NewMemoryStore()
+	s.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+	_, _ = s.CompareAndSwap(ctx, "expired", 0, []byte("x"), time.Second)
+
+	now = now.Add(time.Minute)
+	for i := range 1023 {
+		_, _ = s.CompareAndSwap(ctx, "key", uint64(i), []byte("x"), time.Hour)
+	}
+
+	assert.Equal(t, 1, s.Len())
+}
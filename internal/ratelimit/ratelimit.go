@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrConflict means state was concurrently modified too many times.
+var ErrConflict = errors.New("rate limit state conflict")
+
+// maxAttempts limits optimistic update retries of a single decision.
+const maxAttempts = 8
+
+// Rule limits number of requests in a window.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParseRule parses rule in "<limit>/<window>" format, e.g. "100/1m".
+func ParseRule(s string) (Rule, error) {
+	limit, window, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("rate limit rule %q should be in \"limit/window\" format", s)
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return Rule{}, fmt.Errorf("rate limit rule %q: limit should be positive integer", s)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return Rule{}, fmt.Errorf("rate limit rule %q: window should be positive duration", s)
+	}
+	return Rule{Limit: n, Window: d}, nil
+}
+
+// String implements fmt.Stringer interface.
+func (r Rule) String() string {
+	return strconv.Itoa(r.Limit) + "/" + r.Window.String()
+}
+
+// Result is a rate limiting decision.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the quota is restored
+	RetryAfter time.Duration // until the next request is allowed, if denied
+}
+
+// Algorithm decides on a single request with the stored state.
+// Returns the new state to be stored and the decision.
+// Nil state means the key has not been seen yet.
+type Algorithm func(state []byte, rule Rule, now time.Time) ([]byte, Result)
+
+// Store keeps limiter states shared between service instances.
+// A Redis-compatible store implements it with WATCH/MULTI transactions or a script.
+type Store interface {
+	// Get returns state stored under the key and its version.
+	// Returns nil state if the key is absent or expired.
+	Get(ctx context.Context, key string) ([]byte, uint64, error)
+	// CompareAndSwap stores state under the key with ttl if stored version is still equal to the version.
+	// Reports false if the state was concurrently modified.
+	CompareAndSwap(ctx context.Context, key string, version uint64, state []byte, ttl time.Duration) (bool, error)
+}
+
+// Limiter limits requests by keys with the algorithm.
+type Limiter struct {
+	store Store
+	alg   Algorithm
+	rule  Rule
+	now   func() time.Time
+}
+
+// NewLimiter creates limiter of the rule sharing states in the store.
+func NewLimiter(store Store, alg Algorithm, rule Rule) *Limiter {
+	return &Limiter{store: store, alg: alg, rule: rule, now: time.Now}
+}
+
+// Rule returns the limiter rule.
+func (l *Limiter) Rule() Rule {
+	return l.rule
+}
+
+// Take decides on a single request of the key.
+func (l *Limiter) Take(ctx context.Context, key string) (Result, error) {
+	for range maxAttempts {
+		state, version, err := l.store.Get(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+		state, res := l.alg(state, l.rule, l.now())
+		ok, err := l.store.CompareAndSwap(ctx, key, version, state, 2*l.rule.Window)
+		if err != nil {
+			return Result{}, err
+		}
+		if ok {
+			return res, nil
+		}
+	}
+	return Result{}, ErrConflict
+}
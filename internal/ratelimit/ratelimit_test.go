@@ -0,0 +1,118 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule_ShouldParse(t *testing.T) {
+	rule, err := ratelimit.ParseRule("100/1m")
+
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Rule{Limit: 100, Window: time.Minute}, rule)
+	assert.Equal(t, "100/1m0s", rule.String())
+}
+
+func TestParseRule_Malformed_ShouldError(t *testing.T) {
+	for _, s := range []string{"", "100", "x/1m", "0/1m", "10/x", "10/-1s"} {
+		_, err := ratelimit.ParseRule(s)
+
+		assert.Error(t, err, s)
+	}
+}
+
+func TestLimiter_Take_ShouldLimitPerKey(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.TokenBucket, ratelimit.Rule{Limit: 2, Window: time.Hour})
+	ctx := context.Background()
+
+	first, _ := l.Take(ctx, "a")
+	second, _ := l.Take(ctx, "a")
+	third, _ := l.Take(ctx, "a")
+	other, err := l.Take(ctx, "b")
+
+	require.NoError(t, err)
+	assert.True(t, first.Allowed)
+	assert.True(t, second.Allowed)
+	assert.False(t, third.Allowed)
+	assert.True(t, other.Allowed)
+}
+
+func TestLimiter_Take_Concurrent_ShouldNotExceedLimit(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.SlidingWindow, ratelimit.Rule{Limit: 50, Window: time.Hour})
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := l.Take(context.Background(), "a")
+			if err == nil && res.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, allowed, 50)
+}
+
+type StoreMock struct {
+	err      error
+	conflict bool
+}
+
+func (s *StoreMock) Get(context.Context, string) ([]byte, uint64, error) {
+	return nil, 0, s.err
+}
+
+func (s *StoreMock) CompareAndSwap(context.Context, string, uint64, []byte, time.Duration) (bool, error) {
+	return !s.conflict, nil
+}
+
+func TestLimiter_Take_StoreError_ShouldError(t *testing.T) {
+	errExpected := errors.New("unavailable")
+	l := ratelimit.NewLimiter(&StoreMock{err: errExpected}, ratelimit.TokenBucket, ratelimit.Rule{Limit: 1, Window: time.Second})
+
+	_, err := l.Take(context.Background(), "a")
+
+	assert.ErrorIs(t, err, errExpected)
+}
+
+func TestLimiter_Take_PersistentConflict_ShouldError(t *testing.T) {
+	l := ratelimit.NewLimiter(&StoreMock{conflict: true}, ratelimit.TokenBucket, ratelimit.Rule{Limit: 1, Window: time.Second})
+
+	_, err := l.Take(context.Background(), "a")
+
+	assert.ErrorIs(t, err, ratelimit.ErrConflict)
+}
+
+func TestLimiter_Take_AfterWindow_ShouldRestore(t *testing.T) {
+	now := time.Now()
+	store := ratelimit.NewMemoryStore()
+	store.SetClock(func() time.Time { return now })
+	l := ratelimit.NewLimiter(store, ratelimit.TokenBucket, ratelimit.Rule{Limit: 1, Window: time.Second})
+	l.SetClock(func() time.Time { return now })
+
+	first, _ := l.Take(context.Background(), "a")
+	denied, _ := l.Take(context.Background(), "a")
+	now = now.Add(time.Second)
+	restored, _ := l.Take(context.Background(), "a")
+
+	assert.True(t, first.Allowed)
+	assert.False(t, denied.Allowed)
+	assert.True(t, restored.Allowed)
+	assert.Equal(t, ratelimit.Rule{Limit: 1, Window: time.Second}, l.Rule())
+}
@@ -0,0 +1,275 @@
+package sprint
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/etag"
+	"github.com/asmazovec/team-agile/internal/idgen"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Options of the sprint REST API.
+type Options struct {
+	// NewID generates ids of created sprints.
+	NewID idgen.Generator
+	// RequireIfMatch rejects changes of sprints without If-Match header with 428 Precondition Required.
+	RequireIfMatch bool
+}
+
+// sprintInput is user editable fields of a created sprint.
+type sprintInput struct {
+	BoardID   string     `json:"board_id"`
+	Name      string     `json:"name"`
+	Goal      string     `json:"goal"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+func (in sprintInput) sprint() Sprint {
+	return Sprint{BoardID: in.BoardID, Name: in.Name, Goal: in.Goal, StartDate: in.StartDate, EndDate: in.EndDate}
+}
+
+// Validate implements decode.Validator.
+func (in sprintInput) Validate() []decode.FieldError {
+	return in.sprint().Validate()
+}
+
+// sprintPatch is a partial update of a sprint.
+// Nil fields are kept unchanged.
+type sprintPatch struct {
+	Name      *string    `json:"name"`
+	Goal      *string    `json:"goal"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// completion chooses the sprint unfinished tasks are carried over to, the backlog without a sprint.
+type completion struct {
+	NextSprint string `json:"next_sprint"`
+}
+
+// Router routes REST API of the sprints:
+// POST / creates a planned sprint of a board, GET / lists sprints filtered by board_id and state query parameters,
+// GET /{id} responds with the sprint, PATCH /{id} partially updates the open sprint
+// and DELETE /{id} deletes the planned sprint.
+// POST /{id}/start starts the planned sprint, POST /{id}/complete completes the active sprint
+// carrying unfinished tasks over to the next sprint of an optional body.
+// PUT /{id}/tasks/{task} adds the task to and DELETE /{id}/tasks/{task} removes it from the open sprint.
+// Changes of sprints are conditional on If-Match header of the sprint version.
+func Router(s *Service, opts Options) http.Handler {
+	h := &handler{s: s, opts: opts}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Patch("/", h.patch)
+		r.Delete("/", h.delete)
+		r.Post("/start", h.start)
+		r.Post("/complete", h.complete)
+		r.Put("/tasks/{task}", h.addTask)
+		r.Delete("/tasks/{task}", h.removeTask)
+	})
+	return r
+}
+
+type handler struct {
+	s    *Service
+	opts Options
+}
+
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	var in sprintInput
+	if err := decode.JSON(r, &in); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	sp := in.sprint()
+	sp.ID = h.opts.NewID()
+	sp, err := h.s.Create(r.Context(), sp)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Sprint created", "sprint-id", sp.ID, "board-id", sp.BoardID)
+	}
+	w.Header().Set("Location", r.URL.JoinPath(sp.ID).Path)
+	w.Header().Set("ETag", etag.FromVersion(sp.Version))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, sp)
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := Filter{BoardID: q.Get("board_id"), State: State(q.Get("state"))}
+	switch f.State {
+	case "", StatePlanned, StateActive, StateClosed:
+	default:
+		problem.Write(w, r, decode.Problem(&decode.Error{
+			Status: http.StatusBadRequest,
+			Detail: "query parameters are invalid",
+			Fields: []decode.FieldError{{Field: "state", Message: "must be one of planned, active, closed"}},
+		}))
+		return
+	}
+	sprints, err := h.s.sprints.List(r.Context(), f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	render.JSON(w, r, sprints)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	sp, err := h.s.sprints.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if etag.NotModified(w, r, etag.FromVersion(sp.Version)) {
+		return
+	}
+	render.JSON(w, r, sp)
+}
+
+func (h *handler) patch(w http.ResponseWriter, r *http.Request) {
+	var p sprintPatch
+	if err := decode.JSON(r, &p); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.change(w, r, func(sp Sprint) (Sprint, error) {
+		if sp.State == StateClosed {
+			return sp, ErrState
+		}
+		if p.Name != nil {
+			sp.Name = *p.Name
+		}
+		if p.Goal != nil {
+			sp.Goal = *p.Goal
+		}
+		if p.StartDate != nil {
+			sp.StartDate = p.StartDate
+		}
+		if p.EndDate != nil {
+			sp.EndDate = p.EndDate
+		}
+		if fields := sp.Validate(); len(fields) > 0 {
+			return sp, &decode.Error{Status: http.StatusUnprocessableEntity, Detail: "request body is invalid", Fields: fields}
+		}
+		sp.UpdatedAt = h.s.now()
+		return h.s.sprints.Update(r.Context(), sp)
+	}, "Sprint updated")
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	sp, err := h.s.sprints.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(sp.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	if err := h.s.Delete(r.Context(), sp); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Sprint deleted", "sprint-id", sp.ID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) start(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, func(sp Sprint) (Sprint, error) {
+		return h.s.Start(r.Context(), sp)
+	}, "Sprint started")
+}
+
+func (h *handler) complete(w http.ResponseWriter, r *http.Request) {
+	var c completion
+	// Empty body of any length signalling, e.g. chunked, completes the sprint to the backlog.
+	if err := decode.JSON(r, &c); err != nil && !errors.Is(err, io.EOF) {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.change(w, r, func(sp Sprint) (Sprint, error) {
+		return h.s.Complete(r.Context(), sp, c.NextSprint)
+	}, "Sprint completed")
+}
+
+// change changes the current sprint conditionally on If-Match header and logs the message.
+func (h *handler) change(w http.ResponseWriter, r *http.Request, change func(Sprint) (Sprint, error), msg string) {
+	sp, err := h.s.sprints.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(sp.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	sp, err = change(sp)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), msg, "sprint-id", sp.ID, "version", sp.Version)
+	}
+	w.Header().Set("ETag", etag.FromVersion(sp.Version))
+	render.JSON(w, r, sp)
+}
+
+func (h *handler) addTask(w http.ResponseWriter, r *http.Request) {
+	t, err := h.s.AddTask(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "task"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag.FromVersion(t.Version))
+	render.JSON(w, r, t)
+}
+
+func (h *handler) removeTask(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.s.RemoveTask(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "task")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError writes service error as a problem.
+// Concurrent modification of a sprint is reported as a failed precondition,
+// concurrent modification of a planned task as a conflict.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var decErr *decode.Error
+	switch {
+	case errors.As(err, &decErr):
+		problem.Write(w, r, decode.Problem(decErr))
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrTaskNotInSprint), errors.Is(err, task.ErrNotFound):
+		problem.Write(w, r, problem.New(http.StatusNotFound, err.Error()))
+	case errors.Is(err, ErrExists), errors.Is(err, ErrState), errors.Is(err, ErrActiveExists):
+		problem.Write(w, r, problem.New(http.StatusConflict, err.Error()))
+	case errors.Is(err, ErrBoardNotFound), errors.Is(err, ErrTaskNotOnBoard), errors.Is(err, ErrCarryOverTarget):
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, err.Error()))
+	case errors.Is(err, ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusPreconditionFailed, "entity was modified"))
+	case errors.Is(err, task.ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusConflict, "task was modified concurrently, retry the request"))
+	default:
+		if lg := mw.LoggerFrom(r.Context()); lg != nil {
+			lg.ErrorContext(r.Context(), "Sprint storage failed: "+err.Error())
+		}
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "sprint storage failed"))
+	}
+}
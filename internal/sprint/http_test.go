@@ -0,0 +1,157 @@
+package sprint_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSprintAPI(t *testing.T, statuses ...task.Status) http.Handler {
+	f := newFixture(t, statuses...)
+	n := 0
+	r := chi.NewRouter()
+	r.Mount("/sprints", sprint.Router(f.s, sprint.Options{NewID: func() string {
+		n++
+		return "s" + strconv.Itoa(n)
+	}}))
+	return r
+}
+
+func serveSprints(h http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeSprint(t *testing.T, rec *httptest.ResponseRecorder) sprint.Sprint {
+	t.Helper()
+	var sp sprint.Sprint
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sp))
+	return sp
+}
+
+func TestRouter_Create_ShouldCreatePlanned(t *testing.T) {
+	h := newSprintAPI(t)
+
+	rec := serveSprints(h, http.MethodPost, "/sprints",
+		`{"board_id":"b1","name":"Sprint 1","goal":"Ship it","end_date":"2024-05-14T00:00:00Z"}`)
+	unknown := serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b2","name":"Sprint 2"}`)
+	invalid := serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1"}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	sp := decodeSprint(t, rec)
+	assert.Equal(t, "/sprints/s1", rec.Header().Get("Location"))
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	assert.Equal(t, sprint.StatePlanned, sp.State)
+	assert.Equal(t, "Ship it", sp.Goal)
+	assert.Equal(t, http.StatusUnprocessableEntity, unknown.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, invalid.Code)
+}
+
+func TestRouter_Lifecycle_ShouldStartAndComplete(t *testing.T) {
+	h := newSprintAPI(t, task.StatusTodo, task.StatusDone)
+	serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1","name":"Sprint 1"}`)
+	serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1","name":"Sprint 2"}`)
+	added := serveSprints(h, http.MethodPut, "/sprints/s1/tasks/todo", "")
+	serveSprints(h, http.MethodPut, "/sprints/s1/tasks/done", "")
+
+	started := serveSprints(h, http.MethodPost, "/sprints/s1/start", "", "If-Match", `"1"`)
+	second := serveSprints(h, http.MethodPost, "/sprints/s2/start", "")
+	completed := serveSprints(h, http.MethodPost, "/sprints/s1/complete", `{"next_sprint":"s2"}`)
+	active := serveSprints(h, http.MethodGet, "/sprints?board_id=b1&state=active", "")
+
+	require.Equal(t, http.StatusOK, added.Code)
+	assert.Contains(t, added.Body.String(), `"sprint_id":"s1"`)
+	require.Equal(t, http.StatusOK, started.Code)
+	assert.Equal(t, `"2"`, started.Header().Get("ETag"))
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Contains(t, second.Body.String(), `"detail":"board already has an active sprint"`)
+	require.Equal(t, http.StatusOK, completed.Code)
+	report := decodeSprint(t, completed).Report
+	require.NotNil(t, report)
+	assert.Equal(t, sprint.Scope{Tasks: []string{"todo", "done"}, Estimate: 2}, report.Committed)
+	assert.Equal(t, []string{"done"}, report.Completed.Tasks)
+	assert.Equal(t, []string{"todo"}, report.CarriedOver.Tasks)
+	assert.JSONEq(t, `[]`, active.Body.String())
+}
+
+func TestRouter_Complete_WithoutBody_ShouldCarryOverToBacklog(t *testing.T) {
+	h := newSprintAPI(t, task.StatusTodo)
+	serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1","name":"Sprint 1"}`)
+	serveSprints(h, http.MethodPut, "/sprints/s1/tasks/todo", "")
+	serveSprints(h, http.MethodPost, "/sprints/s1/start", "")
+
+	rec := serveSprints(h, http.MethodPost, "/sprints/s1/complete", "")
+	closed := serveSprints(h, http.MethodPut, "/sprints/s1/tasks/todo", "")
+	patched := serveSprints(h, http.MethodPatch, "/sprints/s1", `{"name":"Renamed"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"carried_over":{"tasks":["todo"],"estimate":1}`)
+	assert.NotContains(t, rec.Body.String(), `"next_sprint"`)
+	assert.Equal(t, http.StatusConflict, closed.Code)
+	assert.Equal(t, http.StatusConflict, patched.Code)
+}
+
+func TestRouter_Complete_ChunkedEmptyBody_ShouldCarryOverToBacklog(t *testing.T) {
+	h := newSprintAPI(t, task.StatusTodo)
+	serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1","name":"Sprint 1"}`)
+	serveSprints(h, http.MethodPut, "/sprints/s1/tasks/todo", "")
+	serveSprints(h, http.MethodPost, "/sprints/s1/start", "")
+	req := httptest.NewRequest(http.MethodPost, "/sprints/s1/complete", io.NopCloser(strings.NewReader("")))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	malformed := serveSprints(h, http.MethodPost, "/sprints/s1/complete", `{"next_sprint":`)
+
+	require.Equal(t, int64(-1), req.ContentLength)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"carried_over":{"tasks":["todo"],"estimate":1}`)
+	assert.Equal(t, http.StatusBadRequest, malformed.Code)
+}
+
+func TestRouter_PatchAndDelete_ShouldChangePlannedSprint(t *testing.T) {
+	h := newSprintAPI(t, task.StatusTodo)
+	serveSprints(h, http.MethodPost, "/sprints", `{"board_id":"b1","name":"Sprint 1"}`)
+	serveSprints(h, http.MethodPut, "/sprints/s1/tasks/todo", "")
+
+	patched := serveSprints(h, http.MethodPatch, "/sprints/s1", `{"goal":"Refine"}`)
+	invalid := serveSprints(h, http.MethodPatch, "/sprints/s1", `{"name":""}`)
+	stale := serveSprints(h, http.MethodDelete, "/sprints/s1", "", "If-Match", `"1"`)
+	deleted := serveSprints(h, http.MethodDelete, "/sprints/s1", "")
+	missing := serveSprints(h, http.MethodGet, "/sprints/s1", "")
+
+	require.Equal(t, http.StatusOK, patched.Code)
+	assert.Equal(t, "Refine", decodeSprint(t, patched).Goal)
+	assert.Equal(t, http.StatusUnprocessableEntity, invalid.Code)
+	assert.Contains(t, invalid.Body.String(), `"field":"name"`)
+	assert.Equal(t, http.StatusPreconditionFailed, stale.Code)
+	assert.Equal(t, http.StatusNoContent, deleted.Code)
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestRouter_List_InvalidState_ShouldBadRequest(t *testing.T) {
+	h := newSprintAPI(t)
+
+	rec := serveSprints(h, http.MethodGet, "/sprints?state=open", "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"state"`)
+}
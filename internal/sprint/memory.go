@@ -0,0 +1,145 @@
+package sprint
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryRepository is a Repository keeping sprints in the process memory.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	sprints map[string]Sprint
+	order   []string
+}
+
+// NewMemoryRepository creates empty in-memory repository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{sprints: make(map[string]Sprint)}
+}
+
+// Create implements Repository.
+func (m *MemoryRepository) Create(_ context.Context, s Sprint) (Sprint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sprints[s.ID]; ok {
+		return Sprint{}, ErrExists
+	}
+	s.Version = 1
+	m.sprints[s.ID] = clone(s)
+	m.order = append(m.order, s.ID)
+	return s, nil
+}
+
+// Get implements Repository.
+func (m *MemoryRepository) Get(_ context.Context, id string) (Sprint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sprints[id]
+	if !ok {
+		return Sprint{}, ErrNotFound
+	}
+	return clone(s), nil
+}
+
+// List implements Repository.
+func (m *MemoryRepository) List(_ context.Context, f Filter) ([]Sprint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sprints := []Sprint{}
+	for _, id := range m.order {
+		if s := m.sprints[id]; f.Match(s) {
+			sprints = append(sprints, clone(s))
+		}
+	}
+	return sprints, nil
+}
+
+// Update implements Repository.
+func (m *MemoryRepository) Update(_ context.Context, s Sprint) (Sprint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.sprints[s.ID]
+	if !ok {
+		return Sprint{}, ErrNotFound
+	}
+	if stored.Version != s.Version {
+		return Sprint{}, ErrVersionConflict
+	}
+	s.Version++
+	m.sprints[s.ID] = clone(s)
+	return s, nil
+}
+
+// Activate implements Repository.
+func (m *MemoryRepository) Activate(_ context.Context, s Sprint) (Sprint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.sprints[s.ID]
+	if !ok {
+		return Sprint{}, ErrNotFound
+	}
+	if stored.Version != s.Version {
+		return Sprint{}, ErrVersionConflict
+	}
+	for _, other := range m.sprints {
+		if other.ID != s.ID && other.BoardID == s.BoardID && other.State == StateActive {
+			return Sprint{}, ErrActiveExists
+		}
+	}
+	s.State = StateActive
+	s.Version++
+	m.sprints[s.ID] = clone(s)
+	return s, nil
+}
+
+// Delete implements Repository.
+func (m *MemoryRepository) Delete(_ context.Context, id string, version uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.sprints[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	delete(m.sprints, id)
+	m.order = slices.DeleteFunc(m.order, func(s string) bool { return s == id })
+	return nil
+}
+
+// clone copies the sprint deep enough to never share mutable state with callers.
+func clone(s Sprint) Sprint {
+	s.StartDate = cloneTime(s.StartDate)
+	s.EndDate = cloneTime(s.EndDate)
+	s.StartedAt = cloneTime(s.StartedAt)
+	s.ClosedAt = cloneTime(s.ClosedAt)
+	if s.Committed != nil {
+		c := cloneScope(*s.Committed)
+		s.Committed = &c
+	}
+	if s.Report != nil {
+		r := *s.Report
+		r.Committed = cloneScope(r.Committed)
+		r.Added = cloneScope(r.Added)
+		r.Completed = cloneScope(r.Completed)
+		r.CarriedOver = cloneScope(r.CarriedOver)
+		s.Report = &r
+	}
+	return s
+}
+
+func cloneScope(s Scope) Scope {
+	s.Tasks = slices.Clone(s.Tasks)
+	return s
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
@@ -0,0 +1,113 @@
+package sprint_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_ShouldStoreWithVersions(t *testing.T) {
+	repo := sprint.NewMemoryRepository()
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, sprint.Sprint{ID: "1", BoardID: "b1", Name: "Sprint 1"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, sprint.Sprint{ID: "1"})
+	assert.ErrorIs(t, err, sprint.ErrExists)
+
+	created.Name = "Sprint one"
+	updated, err := repo.Update(ctx, created)
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, created)
+	assert.ErrorIs(t, err, sprint.ErrVersionConflict)
+
+	got, err := repo.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), updated.Version)
+	assert.Equal(t, "Sprint one", got.Name)
+
+	assert.ErrorIs(t, repo.Delete(ctx, "1", 1), sprint.ErrVersionConflict)
+	require.NoError(t, repo.Delete(ctx, "1", 2))
+	_, err = repo.Get(ctx, "1")
+	assert.ErrorIs(t, err, sprint.ErrNotFound)
+}
+
+func TestMemoryRepository_Activate_ShouldAllowSingleActivePerBoard(t *testing.T) {
+	repo := sprint.NewMemoryRepository()
+	ctx := context.Background()
+	const n = 8
+	for i := range n {
+		_, err := repo.Create(ctx, sprint.Sprint{ID: strconv.Itoa(i), BoardID: "b1", Name: "Sprint"})
+		require.NoError(t, err)
+	}
+	other, err := repo.Create(ctx, sprint.Sprint{ID: "other", BoardID: "b2", Name: "Sprint"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var started atomic.Int32
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sp, err := repo.Get(ctx, strconv.Itoa(i))
+			if err != nil {
+				return
+			}
+			_, err = repo.Activate(ctx, sp)
+			if err == nil {
+				started.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, sprint.ErrActiveExists)
+		}()
+	}
+	wg.Wait()
+	activated, err := repo.Activate(ctx, other)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), started.Load())
+	assert.Equal(t, sprint.StateActive, activated.State)
+	active, _ := repo.List(ctx, sprint.Filter{BoardID: "b1", State: sprint.StateActive})
+	assert.Len(t, active, 1)
+}
+
+func TestMemoryRepository_List_ShouldFilter(t *testing.T) {
+	repo := sprint.NewMemoryRepository()
+	ctx := context.Background()
+	for _, s := range []sprint.Sprint{
+		{ID: "1", BoardID: "b1", State: sprint.StateClosed},
+		{ID: "2", BoardID: "b1", State: sprint.StateActive},
+		{ID: "3", BoardID: "b2", State: sprint.StateActive},
+	} {
+		_, _ = repo.Create(ctx, s)
+	}
+
+	active, err := repo.List(ctx, sprint.Filter{BoardID: "b1", State: sprint.StateActive})
+	require.NoError(t, err)
+	all, _ := repo.List(ctx, sprint.Filter{})
+	none, _ := repo.List(ctx, sprint.Filter{BoardID: "b3"})
+
+	require.Len(t, active, 1)
+	assert.Equal(t, "2", active[0].ID)
+	assert.Len(t, all, 3)
+	assert.Empty(t, none)
+}
+
+func TestMemoryRepository_ShouldNotShareReports(t *testing.T) {
+	repo := sprint.NewMemoryRepository()
+	ctx := context.Background()
+	committed := &sprint.Scope{Tasks: []string{"a"}}
+	_, _ = repo.Create(ctx, sprint.Sprint{ID: "1", Committed: committed})
+
+	committed.Tasks[0] = "b"
+	got, err := repo.Get(ctx, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a"}, got.Committed.Tasks)
+}
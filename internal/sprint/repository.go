@@ -0,0 +1,42 @@
+package sprint
+
+import "context"
+
+// Filter selects sprints. Zero fields match any sprint.
+type Filter struct {
+	BoardID string
+	State   State
+}
+
+// Match reports whether the sprint is selected by the filter.
+func (f Filter) Match(s Sprint) bool {
+	if f.BoardID != "" && s.BoardID != f.BoardID {
+		return false
+	}
+	if f.State != "" && s.State != f.State {
+		return false
+	}
+	return true
+}
+
+// Repository stores sprints.
+// Sprints are listed in the creation order.
+type Repository interface {
+	// Create stores the new sprint with version 1.
+	// Returns ErrExists if the sprint id is taken.
+	Create(ctx context.Context, s Sprint) (Sprint, error)
+	// Get returns the sprint or ErrNotFound.
+	Get(ctx context.Context, id string) (Sprint, error)
+	// List returns sprints selected by the filter.
+	List(ctx context.Context, f Filter) ([]Sprint, error)
+	// Update replaces the stored sprint of the same version and returns it with the next version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Update(ctx context.Context, s Sprint) (Sprint, error)
+	// Activate replaces the stored sprint of the same version by the active sprint like Update
+	// unless another sprint of the board is active, checked atomically with the replacement.
+	// Returns ErrNotFound, ErrVersionConflict or ErrActiveExists.
+	Activate(ctx context.Context, s Sprint) (Sprint, error)
+	// Delete removes the sprint of the version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Delete(ctx context.Context, id string, version uint64) error
+}
@@ -0,0 +1,250 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/task"
+)
+
+// Service plans tasks of boards in sprints, starts and completes the sprints.
+type Service struct {
+	sprints Repository
+	boards  board.Repository
+	tasks   task.Repository
+	now     func() time.Time
+}
+
+// NewService creates service of the sprints planning tasks of the boards.
+func NewService(sprints Repository, boards board.Repository, tasks task.Repository) *Service {
+	return &Service{sprints: sprints, boards: boards, tasks: tasks, now: time.Now}
+}
+
+// Create stores the new planned sprint of the existing board.
+func (s *Service) Create(ctx context.Context, sp Sprint) (Sprint, error) {
+	if _, err := s.board(ctx, sp.BoardID); err != nil {
+		return Sprint{}, err
+	}
+	sp.State = StatePlanned
+	sp.CreatedAt = s.now()
+	sp.UpdatedAt = sp.CreatedAt
+	return s.sprints.Create(ctx, sp)
+}
+
+// Delete removes the planned sprint returning its tasks to the backlog.
+// Tasks are returned atomically with the removal of the sprint.
+func (s *Service) Delete(ctx context.Context, sp Sprint) error {
+	if sp.State != StatePlanned {
+		return ErrState
+	}
+	tasks, err := s.sprintTasks(ctx, sp.ID)
+	if err != nil {
+		return err
+	}
+	return s.commit(ctx, sp, tasks, "", func() error {
+		return s.sprints.Delete(ctx, sp.ID, sp.Version)
+	})
+}
+
+// AddTask plans the task of the sprint board in the planned or active sprint.
+// Task planned in another sprint is moved to this one.
+func (s *Service) AddTask(ctx context.Context, sprintID, taskID string) (task.Task, error) {
+	sp, err := s.sprints.Get(ctx, sprintID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if sp.State == StateClosed {
+		return task.Task{}, ErrState
+	}
+	t, err := s.tasks.Get(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if t.BoardID != sp.BoardID {
+		return task.Task{}, ErrTaskNotOnBoard
+	}
+	if t.SprintID == sp.ID {
+		return t, nil
+	}
+	return s.plan(ctx, t, sp.ID)
+}
+
+// RemoveTask returns the task of the planned or active sprint to the backlog.
+func (s *Service) RemoveTask(ctx context.Context, sprintID, taskID string) (task.Task, error) {
+	sp, err := s.sprints.Get(ctx, sprintID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if sp.State == StateClosed {
+		return task.Task{}, ErrState
+	}
+	t, err := s.tasks.Get(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if t.SprintID != sp.ID {
+		return task.Task{}, ErrTaskNotInSprint
+	}
+	return s.plan(ctx, t, "")
+}
+
+// Start activates the planned sprint recording its tasks as the committed scope.
+// Start date defaults to the current time. Board may have a single active sprint only.
+func (s *Service) Start(ctx context.Context, sp Sprint) (Sprint, error) {
+	if sp.State != StatePlanned {
+		return Sprint{}, ErrState
+	}
+	tasks, err := s.sprintTasks(ctx, sp.ID)
+	if err != nil {
+		return Sprint{}, err
+	}
+	committed := Scope{Tasks: []string{}}
+	for _, t := range tasks {
+		committed.add(t.ID, t.Estimate)
+	}
+
+	now := s.now()
+	sp.State = StateActive
+	sp.Committed = &committed
+	sp.StartedAt = &now
+	if sp.StartDate == nil {
+		sp.StartDate = &now
+	}
+	sp.UpdatedAt = now
+	return s.sprints.Activate(ctx, sp)
+}
+
+// Complete closes the active sprint recording the report of its scope.
+// Tasks in the last column of the board are completed, the rest are carried over
+// to the planned next sprint of the board or to the backlog if the next sprint is empty.
+// Tasks are carried over atomically with the closing of the sprint.
+func (s *Service) Complete(ctx context.Context, sp Sprint, next string) (Sprint, error) {
+	if sp.State != StateActive {
+		return Sprint{}, ErrState
+	}
+	b, err := s.board(ctx, sp.BoardID)
+	if err != nil {
+		return Sprint{}, err
+	}
+	if next != "" {
+		target, err := s.sprints.Get(ctx, next)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return Sprint{}, ErrCarryOverTarget
+		case err != nil:
+			return Sprint{}, err
+		case target.BoardID != sp.BoardID || target.State != StatePlanned:
+			return Sprint{}, ErrCarryOverTarget
+		}
+	}
+	tasks, err := s.sprintTasks(ctx, sp.ID)
+	if err != nil {
+		return Sprint{}, err
+	}
+
+	var done task.Status
+	if len(b.Columns) > 0 {
+		done = b.Columns[len(b.Columns)-1].Status
+	}
+	report := Report{
+		Committed:   Scope{Tasks: []string{}},
+		Added:       Scope{Tasks: []string{}},
+		Completed:   Scope{Tasks: []string{}},
+		CarriedOver: Scope{Tasks: []string{}},
+		NextSprint:  next,
+	}
+	if sp.Committed != nil {
+		report.Committed = *sp.Committed
+	}
+	var carried []task.Task
+	for _, t := range tasks {
+		if !slices.Contains(report.Committed.Tasks, t.ID) {
+			report.Added.add(t.ID, t.Estimate)
+		}
+		if t.BoardID == b.ID && t.Status == done {
+			report.Completed.add(t.ID, t.Estimate)
+			continue
+		}
+		carried = append(carried, t)
+		report.CarriedOver.add(t.ID, t.Estimate)
+	}
+
+	now := s.now()
+	sp.State = StateClosed
+	sp.Report = &report
+	sp.ClosedAt = &now
+	sp.UpdatedAt = now
+	var closed Sprint
+	err = s.commit(ctx, sp, carried, next, func() error {
+		closed, err = s.sprints.Update(ctx, sp)
+		return err
+	})
+	return closed, err
+}
+
+// commit moves the tasks of the sprint to another sprint, empty sprint is the backlog, and stores the change
+// of the sprint. Version of the sprint is checked first and the tasks are moved atomically,
+// the tasks are moved back if the change fails anyway.
+func (s *Service) commit(ctx context.Context, sp Sprint, tasks []task.Task, sprintID string, change func() error) error {
+	stored, err := s.sprints.Get(ctx, sp.ID)
+	if err != nil {
+		return err
+	}
+	if stored.Version != sp.Version {
+		return ErrVersionConflict
+	}
+	moved, err := s.planMany(ctx, tasks, sprintID)
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		if _, rollbackErr := s.planMany(ctx, moved, sp.ID); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// board returns the board of the sprint or ErrBoardNotFound.
+func (s *Service) board(ctx context.Context, id string) (board.Board, error) {
+	b, err := s.boards.Get(ctx, id)
+	if errors.Is(err, board.ErrNotFound) {
+		return board.Board{}, ErrBoardNotFound
+	}
+	return b, err
+}
+
+// sprintTasks returns all tasks planned in the sprint.
+func (s *Service) sprintTasks(ctx context.Context, id string) ([]task.Task, error) {
+	page, err := s.tasks.List(ctx, task.Filter{SprintID: id})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// plan moves the task to the sprint, empty sprint is the backlog.
+func (s *Service) plan(ctx context.Context, t task.Task, sprintID string) (task.Task, error) {
+	t.SprintID = sprintID
+	t.UpdatedAt = s.now()
+	return s.tasks.Update(ctx, t)
+}
+
+// planMany moves the tasks to the sprint atomically, empty sprint is the backlog.
+func (s *Service) planMany(ctx context.Context, tasks []task.Task, sprintID string) ([]task.Task, error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	planned := make([]task.Task, len(tasks))
+	now := s.now()
+	for i, t := range tasks {
+		t.SprintID = sprintID
+		t.UpdatedAt = now
+		planned[i] = t
+	}
+	return s.tasks.UpdateMany(ctx, planned)
+}
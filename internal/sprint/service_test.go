@@ -0,0 +1,284 @@
+package sprint_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/board"
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	s       *sprint.Service
+	boards  *board.MemoryRepository
+	sprints *sprint.MemoryRepository
+	tasks   *task.MemoryRepository
+}
+
+// newFixture creates board b1 of the default columns 1..4 with tasks of the statuses,
+// the tasks have ids of their statuses and estimate of 1.
+func newFixture(t *testing.T, statuses ...task.Status) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	boards := board.NewMemoryRepository()
+	w := workflow.Default()
+	n := 0
+	_, err := boards.Create(ctx, board.Board{
+		ID:   "b1",
+		Name: "Team",
+		Columns: board.DefaultColumns(w, func() string {
+			n++
+			return strconv.Itoa(n)
+		}),
+		Workflow: w,
+	})
+	require.NoError(t, err)
+	tasks := task.NewMemoryRepository()
+	for _, st := range statuses {
+		_, err := tasks.Create(ctx, task.Task{ID: string(st), Title: string(st), Status: st, BoardID: "b1", Estimate: 1})
+		require.NoError(t, err)
+	}
+	sprints := sprint.NewMemoryRepository()
+	s := sprint.NewService(sprints, boards, tasks)
+	s.SetClock(func() time.Time { return testNow })
+	return &fixture{s: s, boards: boards, sprints: sprints, tasks: tasks}
+}
+
+// racingTasks modifies a task concurrently right after the sprint tasks are listed.
+type racingTasks struct {
+	task.Repository
+	race func()
+}
+
+func (r *racingTasks) List(ctx context.Context, f task.Filter) (task.Page, error) {
+	page, err := r.Repository.List(ctx, f)
+	if r.race != nil {
+		r.race()
+		r.race = nil
+	}
+	return page, err
+}
+
+func (f *fixture) create(t *testing.T, id string) sprint.Sprint {
+	t.Helper()
+	sp, err := f.s.Create(context.Background(), sprint.Sprint{ID: id, BoardID: "b1", Name: "Sprint " + id})
+	require.NoError(t, err)
+	return sp
+}
+
+func (f *fixture) sprintOf(t *testing.T, taskID string) string {
+	t.Helper()
+	tk, err := f.tasks.Get(context.Background(), taskID)
+	require.NoError(t, err)
+	return tk.SprintID
+}
+
+func TestService_Create_UnknownBoard_ShouldError(t *testing.T) {
+	f := newFixture(t)
+
+	_, err := f.s.Create(context.Background(), sprint.Sprint{ID: "s1", BoardID: "b2", Name: "Sprint"})
+
+	assert.ErrorIs(t, err, sprint.ErrBoardNotFound)
+}
+
+func TestService_AddTask_ShouldPlanTasksOfBoard(t *testing.T) {
+	f := newFixture(t, task.StatusTodo)
+	ctx := context.Background()
+	f.create(t, "s1")
+	f.create(t, "s2")
+	_, _ = f.tasks.Create(ctx, task.Task{ID: "off", Title: "off", Status: task.StatusTodo})
+
+	_, err := f.s.AddTask(ctx, "s1", "todo")
+	require.NoError(t, err)
+	_, err = f.s.AddTask(ctx, "s2", "todo")
+	require.NoError(t, err)
+	_, offBoard := f.s.AddTask(ctx, "s1", "off")
+	_, notInSprint := f.s.RemoveTask(ctx, "s1", "todo")
+
+	assert.Equal(t, "s2", f.sprintOf(t, "todo"))
+	assert.ErrorIs(t, offBoard, sprint.ErrTaskNotOnBoard)
+	assert.ErrorIs(t, notInSprint, sprint.ErrTaskNotInSprint)
+}
+
+func TestService_Start_ShouldCommitScopeAndAllowSingleActive(t *testing.T) {
+	f := newFixture(t, task.StatusTodo, task.StatusReview)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	s2 := f.create(t, "s2")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+	_, _ = f.s.AddTask(ctx, "s1", "review")
+
+	started, err := f.s.Start(ctx, s1)
+	require.NoError(t, err)
+	_, again := f.s.Start(ctx, started)
+	_, second := f.s.Start(ctx, s2)
+
+	assert.Equal(t, sprint.StateActive, started.State)
+	assert.Equal(t, &sprint.Scope{Tasks: []string{"todo", "review"}, Estimate: 2}, started.Committed)
+	assert.Equal(t, testNow, *started.StartDate)
+	assert.ErrorIs(t, again, sprint.ErrState)
+	assert.ErrorIs(t, second, sprint.ErrActiveExists)
+}
+
+func TestService_Start_Concurrent_ShouldStartSingleSprint(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	planned := []sprint.Sprint{f.create(t, "s1"), f.create(t, "s2"), f.create(t, "s3"), f.create(t, "s4")}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(planned))
+	for i, sp := range planned {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.s.Start(ctx, sp)
+		}()
+	}
+	wg.Wait()
+
+	started := 0
+	for _, err := range errs {
+		if err == nil {
+			started++
+			continue
+		}
+		assert.ErrorIs(t, err, sprint.ErrActiveExists)
+	}
+	assert.Equal(t, 1, started)
+}
+
+func TestService_Complete_ShouldReportAndCarryOver(t *testing.T) {
+	f := newFixture(t, task.StatusTodo, task.StatusReview, task.StatusDone)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	f.create(t, "s2")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+	_, _ = f.s.AddTask(ctx, "s1", "review")
+	s1, err := f.s.Start(ctx, s1)
+	require.NoError(t, err)
+	_, _ = f.s.AddTask(ctx, "s1", "done")
+
+	closed, err := f.s.Complete(ctx, s1, "s2")
+	require.NoError(t, err)
+
+	assert.Equal(t, sprint.StateClosed, closed.State)
+	assert.Equal(t, &sprint.Report{
+		Committed:   sprint.Scope{Tasks: []string{"todo", "review"}, Estimate: 2},
+		Added:       sprint.Scope{Tasks: []string{"done"}, Estimate: 1},
+		Completed:   sprint.Scope{Tasks: []string{"done"}, Estimate: 1},
+		CarriedOver: sprint.Scope{Tasks: []string{"todo", "review"}, Estimate: 2},
+		NextSprint:  "s2",
+	}, closed.Report)
+	assert.Equal(t, testNow, *closed.ClosedAt)
+	assert.Equal(t, "s2", f.sprintOf(t, "todo"))
+	assert.Equal(t, "s1", f.sprintOf(t, "done"))
+}
+
+func TestService_Complete_ToBacklog_ShouldClearSprint(t *testing.T) {
+	f := newFixture(t, task.StatusTodo)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+	s1, _ = f.s.Start(ctx, s1)
+
+	closed, err := f.s.Complete(ctx, s1, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"todo"}, closed.Report.CarriedOver.Tasks)
+	assert.Empty(t, f.sprintOf(t, "todo"))
+}
+
+func TestService_Complete_TaskConflict_ShouldKeepSprintAndTasks(t *testing.T) {
+	f := newFixture(t, task.StatusTodo, task.StatusInProgress, task.StatusReview)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	for _, id := range []string{"todo", "in-progress", "review"} {
+		_, _ = f.s.AddTask(ctx, "s1", id)
+	}
+	s1, _ = f.s.Start(ctx, s1)
+	tasks := &racingTasks{Repository: f.tasks, race: func() {
+		tk, _ := f.tasks.Get(ctx, "in-progress")
+		tk.Title = "Changed"
+		_, err := f.tasks.Update(ctx, tk)
+		require.NoError(t, err)
+	}}
+	s := sprint.NewService(f.sprints, f.boards, tasks)
+
+	_, err := s.Complete(ctx, s1, "")
+
+	assert.ErrorIs(t, err, task.ErrVersionConflict)
+	stored, _ := f.sprints.Get(ctx, "s1")
+	assert.Equal(t, sprint.StateActive, stored.State)
+	assert.Equal(t, s1.Version, stored.Version)
+	for _, id := range []string{"todo", "in-progress", "review"} {
+		assert.Equal(t, "s1", f.sprintOf(t, id))
+	}
+}
+
+func TestService_Complete_StaleSprint_ShouldKeepTasks(t *testing.T) {
+	f := newFixture(t, task.StatusTodo)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+	s1, _ = f.s.Start(ctx, s1)
+	s1.Goal = "Changed"
+	_, err := f.sprints.Update(ctx, s1)
+	require.NoError(t, err)
+
+	_, err = f.s.Complete(ctx, s1, "")
+
+	assert.ErrorIs(t, err, sprint.ErrVersionConflict)
+	assert.Equal(t, "s1", f.sprintOf(t, "todo"))
+}
+
+func TestService_Complete_InvalidTarget_ShouldError(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	s1, _ := f.s.Start(ctx, f.create(t, "s1"))
+
+	_, unknown := f.s.Complete(ctx, s1, "s9")
+	_, self := f.s.Complete(ctx, s1, "s1")
+	_, planned := f.s.Complete(ctx, f.create(t, "s2"), "")
+
+	assert.ErrorIs(t, unknown, sprint.ErrCarryOverTarget)
+	assert.ErrorIs(t, self, sprint.ErrCarryOverTarget)
+	assert.ErrorIs(t, planned, sprint.ErrState)
+}
+
+func TestService_Delete_ShouldReturnTasksToBacklog(t *testing.T) {
+	f := newFixture(t, task.StatusTodo)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+
+	require.NoError(t, f.s.Delete(ctx, s1))
+
+	assert.Empty(t, f.sprintOf(t, "todo"))
+	_, err := f.sprints.Get(ctx, "s1")
+	assert.ErrorIs(t, err, sprint.ErrNotFound)
+}
+
+func TestService_Delete_StaleSprint_ShouldKeepTasks(t *testing.T) {
+	f := newFixture(t, task.StatusTodo)
+	ctx := context.Background()
+	s1 := f.create(t, "s1")
+	_, _ = f.s.AddTask(ctx, "s1", "todo")
+	changed := s1
+	changed.Goal = "Changed"
+	_, err := f.sprints.Update(ctx, changed)
+	require.NoError(t, err)
+
+	err = f.s.Delete(ctx, s1)
+
+	assert.ErrorIs(t, err, sprint.ErrVersionConflict)
+	assert.Equal(t, "s1", f.sprintOf(t, "todo"))
+}
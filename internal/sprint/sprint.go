@@ -0,0 +1,116 @@
+// Package sprint provides time boxed sprints of board tasks, their storage, lifecycle and REST API.
+package sprint
+
+import (
+	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+)
+
+// Limits of sprint fields.
+const (
+	MaxNameLength = 100
+	MaxGoalLength = 1000
+)
+
+var (
+	// ErrNotFound means sprint does not exist.
+	ErrNotFound = errors.New("sprint not found")
+	// ErrExists means sprint with the same id already exists.
+	ErrExists = errors.New("sprint already exists")
+	// ErrVersionConflict means sprint was modified since the expected version.
+	ErrVersionConflict = errors.New("sprint version conflict")
+	// ErrState means the sprint state does not allow the operation.
+	ErrState = errors.New("sprint state does not allow the operation")
+	// ErrActiveExists means the board already has an active sprint.
+	ErrActiveExists = errors.New("board already has an active sprint")
+	// ErrBoardNotFound means the board of the sprint does not exist.
+	ErrBoardNotFound = errors.New("board of the sprint not found")
+	// ErrTaskNotOnBoard means the task is not on the board of the sprint.
+	ErrTaskNotOnBoard = errors.New("task is not on the board of the sprint")
+	// ErrTaskNotInSprint means the task is not planned in the sprint.
+	ErrTaskNotInSprint = errors.New("task is not in the sprint")
+	// ErrCarryOverTarget means unfinished tasks can not be carried over to the sprint.
+	ErrCarryOverTarget = errors.New("tasks can only be carried over to another planned sprint of the board")
+)
+
+// State of a sprint.
+type State string
+
+// Sprint states, sprints are planned, then active and finally closed.
+const (
+	StatePlanned State = "planned"
+	StateActive  State = "active"
+	StateClosed  State = "closed"
+)
+
+// Scope is a set of sprint tasks with their total estimate.
+type Scope struct {
+	Tasks    []string `json:"tasks"`
+	Estimate int      `json:"estimate"`
+}
+
+func (s *Scope) add(id string, estimate int) {
+	s.Tasks = append(s.Tasks, id)
+	s.Estimate += estimate
+}
+
+// Report is a snapshot of the sprint scope taken on completion.
+// Committed scope is the one of the sprint start, added scope is planned in the sprint after the start.
+// Completed tasks are in the last column of the board, the rest are carried over
+// to the next sprint or to the backlog if the next sprint is empty.
+type Report struct {
+	Committed   Scope  `json:"committed"`
+	Added       Scope  `json:"added"`
+	Completed   Scope  `json:"completed"`
+	CarriedOver Scope  `json:"carried_over"`
+	NextSprint  string `json:"next_sprint,omitempty"`
+}
+
+// Sprint is a time box of board tasks.
+// Committed scope is recorded on the start and the report on the completion of the sprint.
+// Version is incremented on each change of the sprint and identifies its state for optimistic locking.
+type Sprint struct {
+	ID        string     `json:"id"`
+	BoardID   string     `json:"board_id"`
+	Name      string     `json:"name"`
+	Goal      string     `json:"goal,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	State     State      `json:"state"`
+	Committed *Scope     `json:"committed,omitempty"`
+	Report    *Report    `json:"report,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   uint64     `json:"version"`
+}
+
+// Validate reports invalid user editable fields of the sprint.
+func (s Sprint) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, decode.FieldError{Field: field, Message: message})
+	}
+
+	if s.BoardID == "" {
+		invalid("board_id", "must not be empty")
+	}
+	switch n := utf8.RuneCountInString(s.Name); {
+	case n == 0:
+		invalid("name", "must not be empty")
+	case n > MaxNameLength:
+		invalid("name", "must be at most "+strconv.Itoa(MaxNameLength)+" characters")
+	}
+	if utf8.RuneCountInString(s.Goal) > MaxGoalLength {
+		invalid("goal", "must be at most "+strconv.Itoa(MaxGoalLength)+" characters")
+	}
+	if s.StartDate != nil && s.EndDate != nil && s.EndDate.Before(*s.StartDate) {
+		invalid("end_date", "must not be before start date")
+	}
+	return fields
+}
@@ -0,0 +1,34 @@
+package sprint_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/sprint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprint_Validate_Valid_ShouldReportNothing(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+
+	s := sprint.Sprint{BoardID: "b1", Name: "Sprint 1", StartDate: &start, EndDate: &end}
+
+	assert.Empty(t, s.Validate())
+}
+
+func TestSprint_Validate_Invalid_ShouldReportFields(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, -1)
+
+	s := sprint.Sprint{Goal: strings.Repeat("g", sprint.MaxGoalLength+1), StartDate: &start, EndDate: &end}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "board_id", Message: "must not be empty"},
+		{Field: "name", Message: "must not be empty"},
+		{Field: "goal", Message: "must be at most 1000 characters"},
+		{Field: "end_date", Message: "must not be before start date"},
+	}, s.Validate())
+}
@@ -0,0 +1,11 @@
+package task
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewRouterAt creates REST API of the tasks with the clock for testing purposes.
+func NewRouterAt(repo Repository, opts Options, now func() time.Time) http.Handler {
+	return newRouter(repo, opts, now)
+}
@@ -0,0 +1,392 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/etag"
+	"github.com/asmazovec/team-agile/internal/idgen"
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Page sizes of the task list.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Options of the task REST API.
+type Options struct {
+	// NewID generates ids of created tasks.
+	NewID idgen.Generator
+	// RequireIfMatch rejects changes of tasks without If-Match header with 428 Precondition Required.
+	RequireIfMatch bool
+	// Save checks status changes of tasks by their workflow, applies actions of the transitions and stores the tasks,
+	// creation of a task is a change from the zero task. Returns *TransitionError if the change is not allowed
+	// or an error with Problem() *problem.Details method describing other rejections.
+	// Optional, tasks are stored in the repository of the router as is by default.
+	Save func(ctx context.Context, before, after Task) (Task, error)
+}
+
+// taskInput is user editable fields of created or replaced task.
+// Empty status and priority default to todo and medium, empty team keeps the team of the task.
+type taskInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      Status     `json:"status"`
+	Assignee    string     `json:"assignee"`
+	Reporter    string     `json:"reporter"`
+	TeamID      string     `json:"team_id"`
+	EpicID      string     `json:"epic_id"`
+	Priority    Priority   `json:"priority"`
+	Estimate    int        `json:"estimate"`
+	Labels      []string   `json:"labels"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+func (in taskInput) apply(t Task) Task {
+	t.Title = in.Title
+	t.Description = in.Description
+	t.Status = in.Status
+	if t.Status == "" {
+		t.Status = StatusTodo
+	}
+	t.Assignee = in.Assignee
+	if in.Reporter != "" {
+		t.Reporter = in.Reporter
+	}
+	if in.TeamID != "" {
+		t.TeamID = in.TeamID
+	}
+	t.EpicID = in.EpicID
+	t.Priority = in.Priority
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
+	t.Estimate = in.Estimate
+	t.Labels = in.Labels
+	t.DueDate = in.DueDate
+	return t
+}
+
+// Validate implements decode.Validator.
+func (in taskInput) Validate() []decode.FieldError {
+	return in.apply(Task{}).Validate()
+}
+
+// taskPatch is a partial update of a task.
+// Nil fields are kept unchanged, empty assignee unassigns the task and null due date clears it.
+type taskPatch struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Status      *Status   `json:"status"`
+	Assignee    *string   `json:"assignee"`
+	Reporter    *string   `json:"reporter"`
+	TeamID      *string   `json:"team_id"`
+	EpicID      *string   `json:"epic_id"`
+	Priority    *Priority `json:"priority"`
+	Estimate    *int      `json:"estimate"`
+	Labels      *[]string `json:"labels"`
+	DueDate     dueDate   `json:"due_date"`
+}
+
+// dueDate is a patched due date distinguishing explicit null clearing the date from the missing field.
+type dueDate struct {
+	Set  bool
+	Time *time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it is called for present fields only.
+func (d *dueDate) UnmarshalJSON(data []byte) error {
+	d.Set = true
+	err := json.Unmarshal(data, &d.Time)
+	// Errors of unmarshalers lack the field the decoder reports for other fields.
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field == "" {
+		typeErr.Field = "due_date"
+	}
+	return err
+}
+
+func (p taskPatch) apply(t Task) Task {
+	if p.Title != nil {
+		t.Title = *p.Title
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.Status != nil {
+		t.Status = *p.Status
+	}
+	if p.Assignee != nil {
+		t.Assignee = *p.Assignee
+	}
+	if p.Reporter != nil {
+		t.Reporter = *p.Reporter
+	}
+	if p.TeamID != nil {
+		t.TeamID = *p.TeamID
+	}
+	if p.EpicID != nil {
+		t.EpicID = *p.EpicID
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.Estimate != nil {
+		t.Estimate = *p.Estimate
+	}
+	if p.Labels != nil {
+		t.Labels = *p.Labels
+	}
+	if p.DueDate.Set {
+		t.DueDate = p.DueDate.Time
+	}
+	return t
+}
+
+// Router routes REST API of the tasks:
+// POST / creates a task of the user team, GET / lists tasks filtered by board_id, sprint_id, team_id, epic_id,
+// status, assignee and label query parameters and paginated by limit and offset, GET /{id} responds with the task,
+// PUT /{id} replaces, PATCH /{id} partially updates and DELETE /{id} deletes the task.
+// Responses of a task carry ETag of its version, changes are conditional on If-Match header.
+func Router(repo Repository, opts Options) http.Handler {
+	return newRouter(repo, opts, time.Now)
+}
+
+func newRouter(repo Repository, opts Options, now func() time.Time) http.Handler {
+	h := &handler{repo: repo, opts: opts, now: now}
+	r := chi.NewRouter()
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Get("/{id}", h.get)
+	r.Put("/{id}", h.replace)
+	r.Patch("/{id}", h.patch)
+	r.Delete("/{id}", h.delete)
+	return r
+}
+
+type handler struct {
+	repo Repository
+	opts Options
+	now  func() time.Time
+}
+
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	var in taskInput
+	if err := decode.JSON(r, &in); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	ts := h.now()
+	t := in.apply(Task{
+		ID:        h.opts.NewID(),
+		Reporter:  mw.UserIDFrom(r.Context()),
+		TeamID:    mw.TeamIDFrom(r.Context()),
+		CreatedAt: ts,
+		UpdatedAt: ts,
+	})
+	t, err := h.save(r.Context(), Task{}, t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Task created", "task-id", t.ID)
+	}
+	w.Header().Set("Location", r.URL.JoinPath(t.ID).Path)
+	w.Header().Set("ETag", etag.FromVersion(t.Version))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, t)
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := Filter{
+		BoardID:  q.Get("board_id"),
+		SprintID: q.Get("sprint_id"),
+		TeamID:   q.Get("team_id"),
+		EpicID:   q.Get("epic_id"),
+		Status:   Status(q.Get("status")),
+		Assignee: q.Get("assignee"),
+		Label:    q.Get("label"),
+		Limit:    DefaultPageSize,
+	}
+	var fields []decode.FieldError
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxPageSize {
+			fields = append(fields, decode.FieldError{
+				Field:   "limit",
+				Message: "must be between 1 and " + strconv.Itoa(MaxPageSize),
+			})
+		}
+		f.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			fields = append(fields, decode.FieldError{Field: "offset", Message: "must be a non-negative integer"})
+		}
+		f.Offset = offset
+	}
+	if len(fields) > 0 {
+		problem.Write(w, r, decode.Problem(&decode.Error{
+			Status: http.StatusBadRequest,
+			Detail: "query parameters are invalid",
+			Fields: fields,
+		}))
+		return
+	}
+
+	page, err := h.repo.List(r.Context(), f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	render.JSON(w, r, page)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	t, err := h.repo.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if etag.NotModified(w, r, etag.FromVersion(t.Version)) {
+		return
+	}
+	render.JSON(w, r, t)
+}
+
+func (h *handler) replace(w http.ResponseWriter, r *http.Request) {
+	var in taskInput
+	if err := decode.JSON(r, &in); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.update(w, r, func(t Task) (Task, []decode.FieldError) {
+		return in.apply(t), nil
+	})
+}
+
+func (h *handler) patch(w http.ResponseWriter, r *http.Request) {
+	var p taskPatch
+	if err := decode.JSON(r, &p); err != nil {
+		problem.Write(w, r, decode.Problem(err))
+		return
+	}
+	h.update(w, r, func(t Task) (Task, []decode.FieldError) {
+		t = p.apply(t)
+		return t, t.Validate()
+	})
+}
+
+// update changes the current task conditionally on If-Match header.
+func (h *handler) update(w http.ResponseWriter, r *http.Request, change func(Task) (Task, []decode.FieldError)) {
+	t, err := h.repo.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(t.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	changed, fields := change(t)
+	if len(fields) > 0 {
+		problem.Write(w, r, decode.Problem(&decode.Error{
+			Status: http.StatusUnprocessableEntity,
+			Detail: "request body is invalid",
+			Fields: fields,
+		}))
+		return
+	}
+	changed.UpdatedAt = h.now()
+	t, err = h.save(r.Context(), t, changed)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Task updated", "task-id", t.ID, "version", t.Version)
+	}
+	w.Header().Set("ETag", etag.FromVersion(t.Version))
+	render.JSON(w, r, t)
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	t, err := h.repo.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !etag.Precondition(w, r, etag.FromVersion(t.Version), h.opts.RequireIfMatch) {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), t.ID, t.Version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if lg := mw.LoggerFrom(r.Context()); lg != nil {
+		lg.InfoContext(r.Context(), "Task deleted", "task-id", t.ID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) save(ctx context.Context, before, after Task) (Task, error) {
+	if h.opts.Save == nil {
+		return Save(ctx, h.repo, before, after)
+	}
+	return h.opts.Save(ctx, before, after)
+}
+
+// writeError writes repository error as a problem.
+// Concurrent modification of the task is reported as a failed precondition.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var transErr *TransitionError
+	var probErr problemError
+	switch {
+	case errors.As(err, &transErr):
+		problem.Write(w, r, TransitionProblem(transErr))
+	case errors.As(err, &probErr):
+		problem.Write(w, r, probErr.Problem())
+	case errors.Is(err, ErrNotFound):
+		problem.Write(w, r, problem.New(http.StatusNotFound, err.Error()))
+	case errors.Is(err, ErrExists):
+		problem.Write(w, r, problem.New(http.StatusConflict, err.Error()))
+	case errors.Is(err, ErrVersionConflict):
+		problem.Write(w, r, problem.New(http.StatusPreconditionFailed, "entity was modified"))
+	default:
+		if lg := mw.LoggerFrom(r.Context()); lg != nil {
+			lg.ErrorContext(r.Context(), "Task storage failed: "+err.Error())
+		}
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "task storage failed"))
+	}
+}
+
+// problemError is a rejection of the Save option described by problem details.
+type problemError interface {
+	error
+	Problem() *problem.Details
+}
+
+// TransitionProblem describes not allowed status change as 409 Conflict problem
+// with allowed statuses extension.
+func TransitionProblem(err *TransitionError) *problem.Details {
+	allowed := err.Allowed
+	if allowed == nil {
+		allowed = []Status{}
+	}
+	return problem.New(http.StatusConflict, err.Error()).
+		With("from", err.From).
+		With("to", err.To).
+		With("allowed", allowed)
+}
@@ -0,0 +1,305 @@
+package task_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mw "github.com/asmazovec/team-agile/internal/middleware"
+	"github.com/asmazovec/team-agile/internal/problem"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type api struct {
+	h   http.Handler
+	ids int
+}
+
+func newAPI(requireIfMatch bool) *api {
+	a := &api{}
+	r := chi.NewRouter()
+	r.Mount("/tasks", task.NewRouterAt(task.NewMemoryRepository(), task.Options{
+		NewID: func() string {
+			a.ids++
+			return strconv.Itoa(a.ids)
+		},
+		RequireIfMatch: requireIfMatch,
+	}, func() time.Time { return testNow }))
+	a.h = r
+	return a
+}
+
+func (a *api) serve(method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	a.h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeTask(t *testing.T, rec *httptest.ResponseRecorder) task.Task {
+	t.Helper()
+	var tk task.Task
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tk))
+	return tk
+}
+
+func TestRouter_Create_ShouldCreateWithDefaults(t *testing.T) {
+	a := newAPI(false)
+
+	rec := a.serve(http.MethodPost, "/tasks", `{"title":"Write docs","labels":["docs"]}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/tasks/1", rec.Header().Get("Location"))
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	assert.Equal(t, task.Task{
+		ID:        "1",
+		Title:     "Write docs",
+		Status:    task.StatusTodo,
+		Priority:  task.PriorityMedium,
+		Labels:    []string{"docs"},
+		CreatedAt: testNow,
+		UpdatedAt: testNow,
+		Version:   1,
+	}, decodeTask(t, rec))
+}
+
+func TestRouter_Create_Invalid_ShouldUnprocessable(t *testing.T) {
+	a := newAPI(false)
+
+	rec := a.serve(http.MethodPost, "/tasks", `{"title":"","status":"Blocked"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `{"field":"title","message":"must not be empty"}`)
+	assert.Contains(t, rec.Body.String(), `"field":"status"`)
+}
+
+func TestRouter_Get_ShouldServeWithETag(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodGet, "/tasks/1", "")
+	notModified := a.serve(http.MethodGet, "/tasks/1", "", "If-None-Match", `"1"`)
+	missing := a.serve(http.MethodGet, "/tasks/2", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	assert.Equal(t, "Write docs", decodeTask(t, rec).Title)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestRouter_List_ShouldFilterAndPaginate(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"First","labels":["api"]}`)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Second","status":"done","labels":["api"]}`)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Third","labels":["api"]}`)
+
+	rec := a.serve(http.MethodGet, "/tasks?label=api&status=todo&limit=1&offset=1", "")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page task.Page
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Third", page.Items[0].Title)
+}
+
+func TestRouter_List_InvalidPagination_ShouldBadRequest(t *testing.T) {
+	a := newAPI(false)
+
+	rec := a.serve(http.MethodGet, "/tasks?limit=1000&offset=-1", "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"limit"`)
+	assert.Contains(t, rec.Body.String(), `"field":"offset"`)
+}
+
+func TestRouter_Replace_ShouldReplaceAndBumpVersion(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs","reporter":"bob","labels":["docs"]}`)
+
+	rec := a.serve(http.MethodPut, "/tasks/1", `{"title":"Write API docs","priority":"high"}`, "If-Match", `"1"`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	tk := decodeTask(t, rec)
+	assert.Equal(t, "Write API docs", tk.Title)
+	assert.Equal(t, task.PriorityHigh, tk.Priority)
+	assert.Equal(t, "bob", tk.Reporter)
+	assert.Empty(t, tk.Labels)
+	assert.Equal(t, uint64(2), tk.Version)
+}
+
+func TestRouter_Patch_ShouldUpdatePresentFields(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs","labels":["docs"]}`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"status":"in-progress","assignee":"alice"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	tk := decodeTask(t, rec)
+	assert.Equal(t, "Write docs", tk.Title)
+	assert.Equal(t, task.StatusInProgress, tk.Status)
+	assert.Equal(t, "alice", tk.Assignee)
+	assert.Equal(t, []string{"docs"}, tk.Labels)
+}
+
+func TestRouter_Patch_NullDueDate_ShouldClear(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs","due_date":"2024-05-01T00:00:00Z"}`)
+
+	kept := decodeTask(t, a.serve(http.MethodPatch, "/tasks/1", `{"title":"Write API docs"}`))
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"due_date":null}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, kept.DueDate)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), kept.DueDate.UTC())
+	assert.Nil(t, decodeTask(t, rec).DueDate)
+}
+
+func TestRouter_Patch_MistypedDueDate_ShouldReportField(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"due_date":5}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"due_date"`)
+}
+
+func TestRouter_Patch_Invalid_ShouldUnprocessable(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"estimate":-3}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"estimate"`)
+}
+
+func TestRouter_Update_StaleIfMatch_ShouldPreconditionFailed(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+	a.serve(http.MethodPatch, "/tasks/1", `{"title":"Write API docs"}`, "If-Match", `"1"`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"title":"Write user docs"}`, "If-Match", `"1"`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+}
+
+func TestRouter_Update_RequiredIfMatch_ShouldPreconditionRequired(t *testing.T) {
+	a := newAPI(true)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	patch := a.serve(http.MethodPatch, "/tasks/1", `{"title":"Write API docs"}`)
+	del := a.serve(http.MethodDelete, "/tasks/1", "")
+
+	assert.Equal(t, http.StatusPreconditionRequired, patch.Code)
+	assert.Equal(t, http.StatusPreconditionRequired, del.Code)
+}
+
+func TestRouter_Delete_ShouldDelete(t *testing.T) {
+	a := newAPI(false)
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodDelete, "/tasks/1", "", "If-Match", `"1"`)
+	missing := a.serve(http.MethodGet, "/tasks/1", "")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestRouter_Patch_NotAllowedTransition_ShouldConflictWithAllowed(t *testing.T) {
+	a := newAPI(false)
+	repo := task.NewMemoryRepository()
+	r := chi.NewRouter()
+	r.Mount("/tasks", task.NewRouterAt(repo, task.Options{
+		NewID: func() string { return "1" },
+		Save: func(ctx context.Context, before, after task.Task) (task.Task, error) {
+			after, _, err := workflow.Default().Apply(before, after, testNow)
+			if err != nil {
+				return task.Task{}, err
+			}
+			return task.Save(ctx, repo, before, after)
+		},
+	}, func() time.Time { return testNow }))
+	a.h = r
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"status":"done"}`)
+	unknown := a.serve(http.MethodPost, "/tasks", `{"title":"Write docs","status":"blocked"}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{
+		"type":"about:blank","title":"Conflict","status":409,
+		"detail":"transition from todo to done is not allowed",
+		"instance":"/tasks/1","from":"todo","to":"done","allowed":["in-progress"]
+	}`, rec.Body.String())
+	assert.Equal(t, http.StatusConflict, unknown.Code)
+	assert.Contains(t, unknown.Body.String(), `"allowed":["todo","in-progress","review","done"]`)
+}
+
+type rejection struct{}
+
+func (rejection) Error() string { return "rejected" }
+
+func (rejection) Problem() *problem.Details {
+	return problem.New(http.StatusConflict, "rejected").With("reason", "limit")
+}
+
+func TestRouter_Patch_TransitionProblem_ShouldWriteIt(t *testing.T) {
+	a := newAPI(false)
+	repo := task.NewMemoryRepository()
+	r := chi.NewRouter()
+	r.Mount("/tasks", task.NewRouterAt(repo, task.Options{
+		NewID: func() string { return "1" },
+		Save: func(ctx context.Context, before, after task.Task) (task.Task, error) {
+			if before.Status == "" {
+				return task.Save(ctx, repo, before, after)
+			}
+			return task.Task{}, rejection{}
+		},
+	}, func() time.Time { return testNow }))
+	a.h = r
+	a.serve(http.MethodPost, "/tasks", `{"title":"Write docs"}`)
+
+	rec := a.serve(http.MethodPatch, "/tasks/1", `{"assignee":"alice"}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"limit"`)
+}
+
+func TestRouter_Create_ShouldOwnByUserTeam(t *testing.T) {
+	a := newAPI(false)
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title":"Write docs","epic_id":"e1"}`))
+	req = req.WithContext(mw.WithTeamID(req.Context(), "core"))
+	rec := httptest.NewRecorder()
+	a.h.ServeHTTP(rec, req)
+
+	moved := a.serve(http.MethodPatch, "/tasks/1", `{"team_id":"platform"}`)
+	byEpic := a.serve(http.MethodGet, "/tasks?team_id=platform&epic_id=e1", "")
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "core", decodeTask(t, rec).TeamID)
+	assert.Equal(t, "platform", decodeTask(t, moved).TeamID)
+	assert.Contains(t, byEpic.Body.String(), `"total":1`)
+}
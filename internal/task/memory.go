@@ -0,0 +1,162 @@
+package task
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryRepository is a Repository keeping tasks in the process memory.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	tasks map[string]Task
+	order []string
+}
+
+// NewMemoryRepository creates empty in-memory repository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{tasks: make(map[string]Task)}
+}
+
+// Create implements Repository.
+func (m *MemoryRepository) Create(_ context.Context, t Task) (Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tasks[t.ID]; ok {
+		return Task{}, ErrExists
+	}
+	t.Version = 1
+	m.tasks[t.ID] = clone(t)
+	m.order = append(m.order, t.ID)
+	return t, nil
+}
+
+// Get implements Repository.
+func (m *MemoryRepository) Get(_ context.Context, id string) (Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tasks[id]
+	if !ok {
+		return Task{}, ErrNotFound
+	}
+	return clone(t), nil
+}
+
+// List implements Repository.
+func (m *MemoryRepository) List(_ context.Context, f Filter) (Page, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	page := Page{Items: []Task{}}
+	for _, id := range m.order {
+		t := m.tasks[id]
+		if !f.Match(t) {
+			continue
+		}
+		if page.Total >= f.Offset && (f.Limit == 0 || len(page.Items) < f.Limit) {
+			page.Items = append(page.Items, clone(t))
+		}
+		page.Total++
+	}
+	return page, nil
+}
+
+// Update implements Repository.
+func (m *MemoryRepository) Update(_ context.Context, t Task) (Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.tasks[t.ID]
+	if !ok {
+		return Task{}, ErrNotFound
+	}
+	if stored.Version != t.Version {
+		return Task{}, ErrVersionConflict
+	}
+	t.Version++
+	m.tasks[t.ID] = clone(t)
+	return t, nil
+}
+
+// UpdateMany implements Repository.
+func (m *MemoryRepository) UpdateMany(_ context.Context, tasks []Task) ([]Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, t := range tasks {
+		if slices.ContainsFunc(tasks[:i], func(other Task) bool { return other.ID == t.ID }) {
+			return nil, ErrDuplicate
+		}
+		stored, ok := m.tasks[t.ID]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		if stored.Version != t.Version {
+			return nil, ErrVersionConflict
+		}
+	}
+	updated := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		t.Version++
+		m.tasks[t.ID] = clone(t)
+		updated = append(updated, t)
+	}
+	return updated, nil
+}
+
+// SaveChecked implements Repository.
+func (m *MemoryRepository) SaveChecked(_ context.Context, t Task, f Filter, check func([]Task) error) (Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.tasks[t.ID]
+	switch {
+	case t.Version == 0 && ok:
+		return Task{}, ErrExists
+	case t.Version != 0 && !ok:
+		return Task{}, ErrNotFound
+	case ok && stored.Version != t.Version:
+		return Task{}, ErrVersionConflict
+	}
+	var selected []Task
+	for _, id := range m.order {
+		if other := m.tasks[id]; id != t.ID && f.Match(other) {
+			selected = append(selected, clone(other))
+		}
+	}
+	if err := check(selected); err != nil {
+		return Task{}, err
+	}
+	if !ok {
+		m.order = append(m.order, t.ID)
+	}
+	t.Version++
+	m.tasks[t.ID] = clone(t)
+	return t, nil
+}
+
+// Delete implements Repository.
+func (m *MemoryRepository) Delete(_ context.Context, id string, version uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.tasks[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	delete(m.tasks, id)
+	m.order = slices.DeleteFunc(m.order, func(s string) bool { return s == id })
+	return nil
+}
+
+// clone copies the task deep enough to never share mutable state with callers.
+func clone(t Task) Task {
+	t.Labels = slices.Clone(t.Labels)
+	if t.DueDate != nil {
+		due := *t.DueDate
+		t.DueDate = &due
+	}
+	if t.ResolvedAt != nil {
+		resolved := *t.ResolvedAt
+		t.ResolvedAt = &resolved
+	}
+	return t
+}
@@ -0,0 +1,152 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Create_ShouldStartVersion(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, task.Task{ID: "1", Title: "Write docs"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, task.Task{ID: "1"})
+
+	assert.Equal(t, uint64(1), created.Version)
+	assert.ErrorIs(t, err, task.ErrExists)
+}
+
+func TestMemoryRepository_Get_ShouldNotShareState(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	_, _ = repo.Create(ctx, task.Task{ID: "1", Labels: []string{"docs"}})
+
+	got, err := repo.Get(ctx, "1")
+	require.NoError(t, err)
+	got.Labels[0] = "changed"
+	again, _ := repo.Get(ctx, "1")
+
+	assert.Equal(t, []string{"docs"}, again.Labels)
+	_, err = repo.Get(ctx, "2")
+	assert.ErrorIs(t, err, task.ErrNotFound)
+}
+
+func TestMemoryRepository_Update_ShouldCompareVersion(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	created, _ := repo.Create(ctx, task.Task{ID: "1", Title: "Write docs"})
+
+	created.Title = "Write API docs"
+	updated, err := repo.Update(ctx, created)
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, created)
+
+	assert.Equal(t, uint64(2), updated.Version)
+	assert.ErrorIs(t, err, task.ErrVersionConflict)
+	_, err = repo.Update(ctx, task.Task{ID: "2"})
+	assert.ErrorIs(t, err, task.ErrNotFound)
+}
+
+func TestMemoryRepository_Delete_ShouldCompareVersion(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	_, _ = repo.Create(ctx, task.Task{ID: "1"})
+
+	assert.ErrorIs(t, repo.Delete(ctx, "1", 2), task.ErrVersionConflict)
+	require.NoError(t, repo.Delete(ctx, "1", 1))
+	assert.ErrorIs(t, repo.Delete(ctx, "1", 1), task.ErrNotFound)
+}
+
+func TestMemoryRepository_List_ShouldFilterAndPaginate(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	for _, tk := range []task.Task{
+		{ID: "1", Status: task.StatusTodo, Labels: []string{"api"}},
+		{ID: "2", Status: task.StatusDone, Labels: []string{"api"}},
+		{ID: "3", Status: task.StatusTodo, Assignee: "alice", SprintID: "s1"},
+		{ID: "4", Status: task.StatusTodo, Labels: []string{"api"}, Assignee: "alice", BoardID: "b1"},
+	} {
+		_, _ = repo.Create(ctx, tk)
+	}
+
+	page, err := repo.List(ctx, task.Filter{Status: task.StatusTodo, Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	byLabel, _ := repo.List(ctx, task.Filter{Label: "api", Assignee: "alice"})
+	byBoard, _ := repo.List(ctx, task.Filter{BoardID: "b1"})
+	bySprint, _ := repo.List(ctx, task.Filter{SprintID: "s1"})
+
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "3", page.Items[0].ID)
+	assert.Equal(t, 1, byLabel.Total)
+	assert.Equal(t, "4", byLabel.Items[0].ID)
+	assert.Equal(t, 1, byBoard.Total)
+	assert.Equal(t, "3", bySprint.Items[0].ID)
+}
+
+func TestMemoryRepository_UpdateMany_ShouldUpdateAllOrNone(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	first, _ := repo.Create(ctx, task.Task{ID: "1", Rank: "A"})
+	second, _ := repo.Create(ctx, task.Task{ID: "2", Rank: "B"})
+
+	first.Rank, second.Rank = "C", "D"
+	stale := second
+	stale.Version = 5
+	_, conflict := repo.UpdateMany(ctx, []task.Task{first, stale})
+	unchanged, _ := repo.Get(ctx, "1")
+	updated, err := repo.UpdateMany(ctx, []task.Task{first, second})
+	require.NoError(t, err)
+	_, missing := repo.UpdateMany(ctx, []task.Task{{ID: "3"}})
+	_, duplicate := repo.UpdateMany(ctx, []task.Task{updated[0], updated[1], updated[0]})
+	unique, _ := repo.Get(ctx, "1")
+
+	assert.ErrorIs(t, conflict, task.ErrVersionConflict)
+	assert.Equal(t, "A", unchanged.Rank)
+	require.Len(t, updated, 2)
+	assert.Equal(t, uint64(2), updated[1].Version)
+	assert.ErrorIs(t, missing, task.ErrNotFound)
+	assert.ErrorIs(t, duplicate, task.ErrDuplicate)
+	assert.Equal(t, uint64(2), unique.Version)
+}
+
+func TestMemoryRepository_SaveChecked_ShouldCheckSelectedTasks(t *testing.T) {
+	repo := task.NewMemoryRepository()
+	ctx := context.Background()
+	moved, _ := repo.Create(ctx, task.Task{ID: "1", Status: task.StatusTodo})
+	_, _ = repo.Create(ctx, task.Task{ID: "2", Status: task.StatusInProgress})
+	_, _ = repo.Create(ctx, task.Task{ID: "3", Status: task.StatusDone})
+	full := errors.New("column is full")
+	var selected []task.Task
+
+	moved.Status = task.StatusInProgress
+	_, rejected := repo.SaveChecked(ctx, moved, task.Filter{Status: task.StatusInProgress}, func(tasks []task.Task) error {
+		selected = tasks
+		return full
+	})
+	unchanged, _ := repo.Get(ctx, "1")
+	saved, err := repo.SaveChecked(ctx, moved, task.Filter{Status: task.StatusInProgress}, func([]task.Task) error {
+		return nil
+	})
+	require.NoError(t, err)
+	created, err := repo.SaveChecked(ctx, task.Task{ID: "4"}, task.Filter{}, func(tasks []task.Task) error {
+		assert.Len(t, tasks, 3)
+		return nil
+	})
+	require.NoError(t, err)
+	_, conflict := repo.SaveChecked(ctx, moved, task.Filter{}, func([]task.Task) error { return nil })
+
+	assert.ErrorIs(t, rejected, full)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "2", selected[0].ID)
+	assert.Equal(t, task.StatusTodo, unchanged.Status)
+	assert.Equal(t, uint64(2), saved.Version)
+	assert.Equal(t, uint64(1), created.Version)
+	assert.ErrorIs(t, conflict, task.ErrVersionConflict)
+}
@@ -0,0 +1,87 @@
+package task
+
+import (
+	"context"
+	"slices"
+)
+
+// Filter selects tasks. Zero fields match any task.
+type Filter struct {
+	BoardID  string
+	SprintID string
+	TeamID   string
+	EpicID   string
+	Status   Status
+	Assignee string
+	Label    string
+	// Offset skips the first tasks of the selection.
+	Offset int
+	// Limit is the maximum number of tasks in the page, zero means no limit.
+	Limit int
+}
+
+// Match reports whether the task is selected by the filter.
+func (f Filter) Match(t Task) bool {
+	if f.BoardID != "" && t.BoardID != f.BoardID {
+		return false
+	}
+	if f.SprintID != "" && t.SprintID != f.SprintID {
+		return false
+	}
+	if f.TeamID != "" && t.TeamID != f.TeamID {
+		return false
+	}
+	if f.EpicID != "" && t.EpicID != f.EpicID {
+		return false
+	}
+	if f.Status != "" && t.Status != f.Status {
+		return false
+	}
+	if f.Assignee != "" && t.Assignee != f.Assignee {
+		return false
+	}
+	if f.Label != "" && !slices.Contains(t.Labels, f.Label) {
+		return false
+	}
+	return true
+}
+
+// Page of tasks. Total is the number of tasks matching the filter over all pages.
+type Page struct {
+	Items []Task `json:"items"`
+	Total int    `json:"total"`
+}
+
+// Repository stores tasks.
+// Tasks are listed in the creation order.
+type Repository interface {
+	// Create stores the new task with version 1.
+	// Returns ErrExists if the task id is taken.
+	Create(ctx context.Context, t Task) (Task, error)
+	// Get returns the task or ErrNotFound.
+	Get(ctx context.Context, id string) (Task, error)
+	// List returns the page of tasks selected by the filter.
+	List(ctx context.Context, f Filter) (Page, error)
+	// Update replaces the stored task of the same version and returns it with the next version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Update(ctx context.Context, t Task) (Task, error)
+	// UpdateMany replaces the stored tasks of the same versions atomically and returns them with the next versions,
+	// none of the tasks is replaced on error. Returns ErrDuplicate if a task is repeated,
+	// ErrNotFound or ErrVersionConflict if a stored version differs.
+	UpdateMany(ctx context.Context, tasks []Task) ([]Task, error)
+	// SaveChecked creates the task of the zero version like Create or updates the task like Update
+	// if check accepts the stored tasks selected by the filter, the saved task aside.
+	// The check and the save are atomic, the error of the check is returned as is.
+	SaveChecked(ctx context.Context, t Task, f Filter, check func(selected []Task) error) (Task, error)
+	// Delete removes the task of the version.
+	// Returns ErrNotFound or ErrVersionConflict if the stored version differs.
+	Delete(ctx context.Context, id string, version uint64) error
+}
+
+// Save creates the task changed from the zero task or updates the changed task in the repository.
+func Save(ctx context.Context, repo Repository, before, after Task) (Task, error) {
+	if before.ID == "" {
+		return repo.Create(ctx, after)
+	}
+	return repo.Update(ctx, after)
+}
@@ -0,0 +1,187 @@
+// Package task provides the team task domain model, its storage and REST API.
+package task
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+)
+
+// Limits of task fields.
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 10000
+	MaxLabels            = 20
+	MaxLabelLength       = 50
+	MaxEstimate          = 1000
+	MaxStatusLength      = 30
+)
+
+var (
+	// ErrNotFound means task does not exist.
+	ErrNotFound = errors.New("task not found")
+	// ErrExists means task with the same id already exists.
+	ErrExists = errors.New("task already exists")
+	// ErrVersionConflict means task was modified since the expected version.
+	ErrVersionConflict = errors.New("task version conflict")
+	// ErrDuplicate means task is repeated in a batch of updated tasks.
+	ErrDuplicate = errors.New("task is repeated in the batch")
+)
+
+// Status of the task.
+// Statuses are defined by workflows, the known statuses form the default workflow.
+type Status string
+
+// Known task statuses.
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in-progress"
+	StatusReview     Status = "review"
+	StatusDone       Status = "done"
+)
+
+// Statuses lists known task statuses in the workflow order.
+func Statuses() []Status {
+	return []Status{StatusTodo, StatusInProgress, StatusReview, StatusDone}
+}
+
+// Valid reports whether the status is a lowercase identifier of letters, digits and dashes
+// starting with a letter.
+func (s Status) Valid() bool {
+	if len(s) == 0 || len(s) > MaxStatusLength || s[0] < 'a' || s[0] > 'z' {
+		return false
+	}
+	for _, c := range []byte(s) {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// TransitionError means status change of the task is not allowed by the workflow.
+// Allowed lists statuses the task can be switched to, Reason explains a failed transition guard.
+type TransitionError struct {
+	From    Status
+	To      Status
+	Allowed []Status
+	Reason  string
+}
+
+// Error implements error interface.
+func (e *TransitionError) Error() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	if e.From == "" {
+		return "status " + string(e.To) + " is not allowed"
+	}
+	return "transition from " + string(e.From) + " to " + string(e.To) + " is not allowed"
+}
+
+// Priority of the task.
+type Priority string
+
+// Known task priorities.
+const (
+	PriorityLow      Priority = "low"
+	PriorityMedium   Priority = "medium"
+	PriorityHigh     Priority = "high"
+	PriorityCritical Priority = "critical"
+)
+
+// Priorities lists known task priorities from the lowest.
+func Priorities() []Priority {
+	return []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical}
+}
+
+// Valid reports whether the priority is known.
+func (p Priority) Valid() bool {
+	return slices.Contains(Priorities(), p)
+}
+
+// Task is a unit of team work.
+// Version is incremented on each change of the task and identifies its state for optimistic locking.
+// Board and rank place the task on a board, the task is shown in the column of its status ordered by rank.
+// Team owns the task in its backlog ordered by backlog rank independently of the board, epic groups related tasks.
+// Sprint of the task is the board sprint the task is planned in.
+// Resolved time is set by actions of the workflow transitions.
+type Task struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      Status     `json:"status"`
+	Assignee    string     `json:"assignee,omitempty"`
+	Reporter    string     `json:"reporter,omitempty"`
+	TeamID      string     `json:"team_id,omitempty"`
+	EpicID      string     `json:"epic_id,omitempty"`
+	Priority    Priority   `json:"priority"`
+	Estimate    int        `json:"estimate,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	BoardID     string     `json:"board_id,omitempty"`
+	Rank        string     `json:"rank,omitempty"`
+	SprintID    string     `json:"sprint_id,omitempty"`
+	BacklogRank string     `json:"backlog_rank,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     uint64     `json:"version"`
+}
+
+// Validate reports invalid user editable fields of the task.
+func (t Task) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, decode.FieldError{Field: field, Message: message})
+	}
+
+	switch n := utf8.RuneCountInString(t.Title); {
+	case n == 0:
+		invalid("title", "must not be empty")
+	case n > MaxTitleLength:
+		invalid("title", "must be at most "+strconv.Itoa(MaxTitleLength)+" characters")
+	}
+	if utf8.RuneCountInString(t.Description) > MaxDescriptionLength {
+		invalid("description", "must be at most "+strconv.Itoa(MaxDescriptionLength)+" characters")
+	}
+	if !t.Status.Valid() {
+		invalid("status", "must be a lowercase identifier of at most "+strconv.Itoa(MaxStatusLength)+" characters")
+	}
+	if !t.Priority.Valid() {
+		invalid("priority", "must be one of "+join(Priorities()))
+	}
+	if t.Estimate < 0 || t.Estimate > MaxEstimate {
+		invalid("estimate", "must be between 0 and "+strconv.Itoa(MaxEstimate))
+	}
+	if len(t.Labels) > MaxLabels {
+		invalid("labels", "must contain at most "+strconv.Itoa(MaxLabels)+" labels")
+	}
+	for i, label := range t.Labels {
+		field := "labels." + strconv.Itoa(i)
+		switch n := utf8.RuneCountInString(label); {
+		case n == 0:
+			invalid(field, "must not be empty")
+		case n > MaxLabelLength:
+			invalid(field, "must be at most "+strconv.Itoa(MaxLabelLength)+" characters")
+		case slices.Contains(t.Labels[:i], label):
+			invalid(field, "must be unique")
+		}
+	}
+	return fields
+}
+
+func join[T ~string](values []T) string {
+	var s string
+	for i, v := range values {
+		if i > 0 {
+			s += ", "
+		}
+		s += string(v)
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package task_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func validTask() task.Task {
+	return task.Task{Title: "Write docs", Status: task.StatusTodo, Priority: task.PriorityMedium}
+}
+
+func TestTask_Validate_Valid_ShouldPass(t *testing.T) {
+	tk := validTask()
+	tk.Labels = []string{"docs", "api"}
+	tk.Estimate = 5
+
+	assert.Empty(t, tk.Validate())
+}
+
+func TestTask_Validate_Invalid_ShouldReportFields(t *testing.T) {
+	tk := task.Task{
+		Title:    strings.Repeat("a", task.MaxTitleLength+1),
+		Status:   "Blocked",
+		Priority: "urgent",
+		Estimate: -1,
+		Labels:   []string{"docs", "", "docs"},
+	}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "title", Message: "must be at most 200 characters"},
+		{Field: "status", Message: "must be a lowercase identifier of at most 30 characters"},
+		{Field: "priority", Message: "must be one of low, medium, high, critical"},
+		{Field: "estimate", Message: "must be between 0 and 1000"},
+		{Field: "labels.1", Message: "must not be empty"},
+		{Field: "labels.2", Message: "must be unique"},
+	}, tk.Validate())
+}
+
+func TestTask_Validate_EmptyTitle_ShouldReport(t *testing.T) {
+	tk := validTask()
+	tk.Title = ""
+
+	assert.Equal(t, []decode.FieldError{{Field: "title", Message: "must not be empty"}}, tk.Validate())
+}
+
+func TestStatus_Valid(t *testing.T) {
+	for status, valid := range map[task.Status]bool{
+		"todo":                               true,
+		"waiting-for-review2":                true,
+		"":                                   false,
+		"1st":                                false,
+		"In progress":                        false,
+		"-done":                              false,
+		task.Status(strings.Repeat("a", 31)): false,
+	} {
+		assert.Equal(t, valid, status.Valid(), status)
+	}
+}
+
+func TestTransitionError_Error(t *testing.T) {
+	assert.EqualError(t, &task.TransitionError{From: "todo", To: "done"}, "transition from todo to done is not allowed")
+	assert.EqualError(t, &task.TransitionError{To: "done"}, "status done is not allowed")
+	assert.EqualError(t, &task.TransitionError{From: "todo", To: "done", Reason: "assignee is required"},
+		"assignee is required")
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Exporter names supported by NewExporter.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// ExporterConfig describes where spans are exported.
+type ExporterConfig struct {
+	Name     string
+	Endpoint string
+	Insecure bool
+	Writer   io.Writer
+}
+
+// NewExporter creates span exporter by name.
+// Returns nil exporter for ExporterNone, spans are sampled but never exported.
+func NewExporter(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Name {
+	case ExporterNone, "":
+		return nil, nil //nolint:nilnil // no exporter is a valid configuration
+	case ExporterStdout:
+		if cfg.Writer == nil {
+			return stdouttrace.New()
+		}
+		return stdouttrace.New(stdouttrace.WithWriter(cfg.Writer))
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Name)
+	}
+}
+
+// NewProvider creates tracer provider for the service batching spans to the exporter.
+// Provider should be shut down to flush remaining spans.
+func NewProvider(exp sdktrace.SpanExporter, service string, ratio float64) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	}
+	if exp != nil {
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	}
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// NewPropagator creates W3C Trace Context and Baggage propagator.
+func NewPropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
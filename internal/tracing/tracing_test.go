@@ -0,0 +1,90 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewExporter_None_ShouldNil(t *testing.T) {
+	exp, err := tracing.NewExporter(context.Background(), tracing.ExporterConfig{Name: tracing.ExporterNone})
+
+	require.NoError(t, err)
+	assert.Nil(t, exp)
+}
+
+func TestNewExporter_Unknown_ShouldError(t *testing.T) {
+	_, err := tracing.NewExporter(context.Background(), tracing.ExporterConfig{Name: "unknown"})
+
+	assert.Error(t, err)
+}
+
+func TestNewExporter_OTLP_ShouldCreate(t *testing.T) {
+	exp, err := tracing.NewExporter(context.Background(), tracing.ExporterConfig{
+		Name:     tracing.ExporterOTLP,
+		Endpoint: "localhost:4318",
+		Insecure: true,
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, exp)
+}
+
+func TestNewExporter_Stdout_ShouldWriteSpans(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := tracing.NewExporter(context.Background(), tracing.ExporterConfig{
+		Name:   tracing.ExporterStdout,
+		Writer: buf,
+	})
+	require.NoError(t, err)
+	tp := tracing.NewProvider(exp, "test", 1)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name":"span"`)
+}
+
+func TestNewProvider_ShouldExportOnFlush(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(exp, "test", 1)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	assert.Len(t, exp.GetSpans(), 1)
+}
+
+func TestNewProvider_ZeroRatio_ShouldNotSample(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(exp, "test", 0)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	assert.Empty(t, exp.GetSpans())
+}
+
+func TestNewPropagator_ShouldExtractTraceparent(t *testing.T) {
+	h := http.Header{}
+	h.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Set("Tracestate", "vendor=value")
+	p := tracing.NewPropagator()
+
+	ctx := p.Extract(context.Background(), propagation.HeaderCarrier(h))
+	out := http.Header{}
+	p.Inject(ctx, propagation.HeaderCarrier(out))
+
+	assert.Equal(t, h.Get("Traceparent"), out.Get("Traceparent"))
+	assert.Equal(t, h.Get("Tracestate"), out.Get("Tracestate"))
+}
@@ -0,0 +1,88 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"gopkg.in/yaml.v3"
+)
+
+// Format of workflow definitions.
+type Format string
+
+// Supported formats.
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ErrUnknownFormat means format of workflow definition is not supported.
+var ErrUnknownFormat = errors.New("unknown workflow format")
+
+// FormatOf returns format of the media type or the file extension,
+// e.g. "application/yaml" or ".yml".
+func FormatOf(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "application/json", ".json", "json":
+		return FormatJSON, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml", ".yaml", ".yml", "yaml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownFormat, s)
+	}
+}
+
+// Parse strictly decodes the workflow definition in the format and validates it.
+// Unknown fields are rejected.
+func Parse(data []byte, format Format) (Workflow, error) {
+	var w Workflow
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&w); err != nil {
+			return Workflow{}, fmt.Errorf("decode workflow: %w", err)
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&w); err != nil && !errors.Is(err, io.EOF) {
+			return Workflow{}, fmt.Errorf("decode workflow: %w", err)
+		}
+	default:
+		return Workflow{}, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	if fields := w.Validate(); len(fields) > 0 {
+		return Workflow{}, &decode.Error{
+			Status: http.StatusUnprocessableEntity,
+			Detail: "workflow is invalid",
+			Fields: fields,
+		}
+	}
+	return w, nil
+}
+
+// LoadFile reads and parses the workflow definition file of format by its extension.
+func LoadFile(path string) (Workflow, error) {
+	format, err := FormatOf(filepath.Ext(path))
+	if err != nil {
+		return Workflow{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Workflow{}, err
+	}
+	w, err := Parse(data, format)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("workflow file %s: %w", path, err)
+	}
+	return w, nil
+}
@@ -0,0 +1,88 @@
+package workflow_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlWorkflow = `
+states: [todo, doing, done]
+transitions:
+  - from: todo
+    to: doing
+    guards: [assignee-required]
+  - from: doing
+    to: done
+    actions: [set-resolved]
+`
+
+func TestParse_YAML_ShouldDecode(t *testing.T) {
+	w, err := workflow.Parse([]byte(yamlWorkflow), workflow.FormatYAML)
+
+	require.NoError(t, err)
+	assert.Equal(t, workflow.Workflow{
+		States: []task.Status{"todo", "doing", "done"},
+		Transitions: []workflow.Transition{
+			{From: "todo", To: "doing", Guards: []workflow.Guard{workflow.GuardAssignee}},
+			{From: "doing", To: "done", Actions: []workflow.Action{workflow.ActionSetResolved}},
+		},
+	}, w)
+}
+
+func TestParse_JSON_ShouldDecode(t *testing.T) {
+	w, err := workflow.Parse([]byte(`{"states":["todo","done"],"transitions":[{"from":"todo","to":"done"}]}`),
+		workflow.FormatJSON)
+
+	require.NoError(t, err)
+	assert.Equal(t, []task.Status{"todo", "done"}, w.States)
+}
+
+func TestParse_UnknownField_ShouldError(t *testing.T) {
+	_, yamlErr := workflow.Parse([]byte("states: [todo]\nstatus: [done]\n"), workflow.FormatYAML)
+	_, jsonErr := workflow.Parse([]byte(`{"states":["todo"],"status":["done"]}`), workflow.FormatJSON)
+
+	assert.Error(t, yamlErr)
+	assert.Error(t, jsonErr)
+}
+
+func TestParse_Invalid_ShouldReportFields(t *testing.T) {
+	_, err := workflow.Parse([]byte("states: []\n"), workflow.FormatYAML)
+
+	var decErr *decode.Error
+	require.ErrorAs(t, err, &decErr)
+	assert.Equal(t, []decode.FieldError{{Field: "states", Message: "must not be empty"}}, decErr.Fields)
+}
+
+func TestFormatOf(t *testing.T) {
+	for s, want := range map[string]workflow.Format{
+		"application/json":   workflow.FormatJSON,
+		".json":              workflow.FormatJSON,
+		"application/yaml":   workflow.FormatYAML,
+		"application/x-yaml": workflow.FormatYAML,
+		".yml":               workflow.FormatYAML,
+	} {
+		got, err := workflow.FormatOf(s)
+
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+	_, err := workflow.FormatOf(".toml")
+	assert.ErrorIs(t, err, workflow.ErrUnknownFormat)
+}
+
+func TestLoadFile_ShouldParseByExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yamlWorkflow), 0o600))
+
+	w, err := workflow.LoadFile(path)
+
+	require.NoError(t, err)
+	assert.Len(t, w.Transitions, 2)
+}
@@ -0,0 +1,331 @@
+// Package workflow defines allowed task statuses and transitions between them
+// with guards checked before and actions applied on a transition.
+package workflow
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+)
+
+// Limits of workflow definitions.
+const (
+	MaxStates      = 30
+	MaxTransitions = 200
+)
+
+// Any matches any source status of a transition.
+const Any task.Status = "*"
+
+// Guard is a condition on the task required for a transition.
+type Guard string
+
+// Known guards.
+const (
+	GuardAssignee Guard = "assignee-required"
+	GuardEstimate Guard = "estimate-required"
+)
+
+// check reports the reason the task does not satisfy the guard.
+func (g Guard) check(t task.Task) (string, bool) {
+	switch g {
+	case GuardAssignee:
+		return "assignee is required", t.Assignee != ""
+	case GuardEstimate:
+		return "estimate is required", t.Estimate > 0
+	default:
+		return "unknown guard " + string(g), false
+	}
+}
+
+// Action is applied to the task after a transition.
+type Action string
+
+// Known actions.
+const (
+	ActionSetResolved   Action = "set-resolved"
+	ActionClearResolved Action = "clear-resolved"
+	ActionNotify        Action = "notify"
+)
+
+func knownAction(a Action) bool {
+	return a == ActionSetResolved || a == ActionClearResolved || a == ActionNotify
+}
+
+// Notifier is notified of tasks switched by transitions with the notify action.
+type Notifier func(ctx context.Context, t task.Task, from task.Status)
+
+// Transition allows switching a task from one status to another.
+type Transition struct {
+	From    task.Status `json:"from" yaml:"from"`
+	To      task.Status `json:"to" yaml:"to"`
+	Guards  []Guard     `json:"guards,omitempty" yaml:"guards,omitempty"`
+	Actions []Action    `json:"actions,omitempty" yaml:"actions,omitempty"`
+}
+
+// Workflow is a set of task statuses and transitions between them.
+// Tasks enter the workflow in the initial first state or in a state reachable from it
+// by transitions the task satisfies guards of.
+type Workflow struct {
+	States      []task.Status `json:"states" yaml:"states"`
+	Transitions []Transition  `json:"transitions" yaml:"transitions"`
+}
+
+// Default returns the workflow of known task statuses:
+// todo <-> in-progress <-> review -> done -> in-progress.
+// Done tasks are resolved and reopened tasks are unresolved.
+func Default() Workflow {
+	return Workflow{
+		States: task.Statuses(),
+		Transitions: []Transition{
+			{From: task.StatusTodo, To: task.StatusInProgress},
+			{From: task.StatusInProgress, To: task.StatusTodo},
+			{From: task.StatusInProgress, To: task.StatusReview},
+			{From: task.StatusReview, To: task.StatusInProgress},
+			{From: task.StatusReview, To: task.StatusDone, Actions: []Action{ActionSetResolved, ActionNotify}},
+			{From: task.StatusDone, To: task.StatusInProgress, Actions: []Action{ActionClearResolved}},
+		},
+	}
+}
+
+// Has reports whether the status is a state of the workflow.
+func (w Workflow) Has(s task.Status) bool {
+	return slices.Contains(w.States, s)
+}
+
+// Allowed lists statuses a task can be switched to from the status in the order of states.
+// Any status of the workflow is listed for a new task of empty status, entry guards are checked by Apply.
+func (w Workflow) Allowed(from task.Status) []task.Status {
+	if from == "" {
+		return slices.Clone(w.States)
+	}
+	allowed := []task.Status{}
+	for _, s := range w.States {
+		if _, ok := w.find(from, s); ok {
+			allowed = append(allowed, s)
+		}
+	}
+	return allowed
+}
+
+// find returns the transition, exact source status takes precedence over Any.
+func (w Workflow) find(from, to task.Status) (Transition, bool) {
+	if from == to {
+		return Transition{}, false
+	}
+	var found *Transition
+	for i, tr := range w.Transitions {
+		if tr.To != to {
+			continue
+		}
+		if tr.From == from {
+			return tr, true
+		}
+		if tr.From == Any && found == nil {
+			found = &w.Transitions[i]
+		}
+	}
+	if found == nil {
+		return Transition{}, false
+	}
+	return *found, true
+}
+
+// Apply checks the status change from the task before to the task after and applies task actions
+// of the transition to the task after at the moment. Creation of a task is a change from the zero task,
+// the task entering the workflow without a status is checked by guards of the transitions leading to its status
+// and gets resolution actions of the transitions into its status.
+// Returns the taken transition, zero transition if the status is unchanged,
+// and *task.TransitionError if the change is not allowed.
+func (w Workflow) Apply(before, after task.Task, now time.Time) (task.Task, Transition, error) {
+	if before.Status == after.Status && before.Status != "" {
+		return after, Transition{}, nil
+	}
+	if before.Status == "" {
+		if err := w.entry(after); err != nil {
+			return after, Transition{}, err
+		}
+		return w.enter(after, now), Transition{To: after.Status}, nil
+	}
+
+	tr, ok := w.find(before.Status, after.Status)
+	if !ok || !w.Has(after.Status) {
+		return after, Transition{}, &task.TransitionError{
+			From:    before.Status,
+			To:      after.Status,
+			Allowed: w.Allowed(before.Status),
+		}
+	}
+	if reason, ok := tr.check(after); !ok {
+		return after, Transition{}, &task.TransitionError{
+			From:    before.Status,
+			To:      after.Status,
+			Allowed: w.Allowed(before.Status),
+			Reason:  reason,
+		}
+	}
+	for _, a := range tr.Actions {
+		switch a {
+		case ActionSetResolved:
+			resolved := now
+			after.ResolvedAt = &resolved
+		case ActionClearResolved:
+			after.ResolvedAt = nil
+		}
+	}
+	return after, tr, nil
+}
+
+// check reports the reason the task does not satisfy guards of the transition.
+func (tr Transition) check(t task.Task) (string, bool) {
+	for _, g := range tr.Guards {
+		if reason, ok := g.check(t); !ok {
+			return reason, false
+		}
+	}
+	return "", true
+}
+
+// entry checks the task entering the workflow without a previous status.
+// Task enters the initial state or a state reachable from it by transitions the task satisfies guards of,
+// so entering tasks never skip guards of the transitions leading to their status.
+func (w Workflow) entry(t task.Task) error {
+	if !w.Has(t.Status) {
+		return &task.TransitionError{To: t.Status, Allowed: w.Allowed("")}
+	}
+	reached := map[task.Status]bool{w.States[0]: true}
+	queue := []task.Status{w.States[0]}
+	var reason, reasonTo string
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, s := range w.States {
+			if reached[s] {
+				continue
+			}
+			tr, ok := w.find(from, s)
+			if !ok {
+				continue
+			}
+			if r, ok := tr.check(t); !ok {
+				if reason == "" {
+					reason = r
+				}
+				if s == t.Status && reasonTo == "" {
+					reasonTo = r
+				}
+				continue
+			}
+			reached[s] = true
+			queue = append(queue, s)
+		}
+	}
+	if reached[t.Status] {
+		return nil
+	}
+	allowed := []task.Status{}
+	for _, s := range w.States {
+		if reached[s] {
+			allowed = append(allowed, s)
+		}
+	}
+	return &task.TransitionError{To: t.Status, Allowed: allowed, Reason: cmp.Or(reasonTo, reason)}
+}
+
+// enter applies resolution actions of the transitions into the status of the task entering the workflow.
+// Setting the resolution takes precedence over clearing it, resolved task keeps its resolution time.
+func (w Workflow) enter(t task.Task, now time.Time) task.Task {
+	var resolves, reopens bool
+	for _, tr := range w.Transitions {
+		if tr.To != t.Status {
+			continue
+		}
+		resolves = resolves || slices.Contains(tr.Actions, ActionSetResolved)
+		reopens = reopens || slices.Contains(tr.Actions, ActionClearResolved)
+	}
+	switch {
+	case resolves && t.ResolvedAt == nil:
+		resolved := now
+		t.ResolvedAt = &resolved
+	case reopens && !resolves:
+		t.ResolvedAt = nil
+	}
+	return t
+}
+
+// Notifies reports whether the transition has the notify action.
+func (tr Transition) Notifies() bool {
+	return slices.Contains(tr.Actions, ActionNotify)
+}
+
+// Validate implements decode.Validator.
+func (w Workflow) Validate() []decode.FieldError {
+	var fields []decode.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, decode.FieldError{Field: field, Message: message})
+	}
+
+	switch n := len(w.States); {
+	case n == 0:
+		invalid("states", "must not be empty")
+	case n > MaxStates:
+		invalid("states", "must contain at most "+strconv.Itoa(MaxStates)+" states")
+	}
+	for i, s := range w.States {
+		field := "states." + strconv.Itoa(i)
+		switch {
+		case !s.Valid():
+			invalid(field, "must be a lowercase identifier of at most "+strconv.Itoa(task.MaxStatusLength)+" characters")
+		case slices.Contains(w.States[:i], s):
+			invalid(field, "must be unique")
+		}
+	}
+
+	if len(w.Transitions) > MaxTransitions {
+		invalid("transitions", "must contain at most "+strconv.Itoa(MaxTransitions)+" transitions")
+	}
+	for i, tr := range w.Transitions {
+		field := "transitions." + strconv.Itoa(i)
+		if tr.From != Any && !w.Has(tr.From) {
+			invalid(field+".from", "must be a state of the workflow or *")
+		}
+		switch {
+		case !w.Has(tr.To):
+			invalid(field+".to", "must be a state of the workflow")
+		case tr.From == tr.To:
+			invalid(field+".to", "must differ from the source state")
+		case slices.ContainsFunc(w.Transitions[:i], func(prev Transition) bool {
+			return prev.From == tr.From && prev.To == tr.To
+		}):
+			invalid(field, "must be unique")
+		}
+		for j, g := range tr.Guards {
+			if g != GuardAssignee && g != GuardEstimate {
+				invalid(field+".guards."+strconv.Itoa(j), "must be one of "+string(GuardAssignee)+", "+string(GuardEstimate))
+			}
+		}
+		for j, a := range tr.Actions {
+			if !knownAction(a) {
+				invalid(field+".actions."+strconv.Itoa(j),
+					"must be one of "+string(ActionSetResolved)+", "+string(ActionClearResolved)+", "+string(ActionNotify))
+			}
+		}
+	}
+	return fields
+}
+
+// Clone copies the workflow deep enough to never share mutable state.
+func (w Workflow) Clone() Workflow {
+	w.States = slices.Clone(w.States)
+	w.Transitions = slices.Clone(w.Transitions)
+	for i, tr := range w.Transitions {
+		w.Transitions[i].Guards = slices.Clone(tr.Guards)
+		w.Transitions[i].Actions = slices.Clone(tr.Actions)
+	}
+	return w
+}
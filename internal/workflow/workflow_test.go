@@ -0,0 +1,170 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asmazovec/team-agile/internal/decode"
+	"github.com/asmazovec/team-agile/internal/task"
+	"github.com/asmazovec/team-agile/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func guarded() workflow.Workflow {
+	return workflow.Workflow{
+		States: []task.Status{"todo", "doing", "done", "wontfix"},
+		Transitions: []workflow.Transition{
+			{From: "todo", To: "doing", Guards: []workflow.Guard{workflow.GuardAssignee, workflow.GuardEstimate}},
+			{From: "doing", To: "done", Actions: []workflow.Action{workflow.ActionSetResolved, workflow.ActionNotify}},
+			{From: "done", To: "doing", Actions: []workflow.Action{workflow.ActionClearResolved}},
+			{From: workflow.Any, To: "wontfix"},
+		},
+	}
+}
+
+func TestDefault_ShouldBeValid(t *testing.T) {
+	w := workflow.Default()
+
+	assert.Empty(t, w.Validate())
+	assert.Equal(t, []task.Status{task.StatusTodo, task.StatusReview}, w.Allowed(task.StatusInProgress))
+	assert.Equal(t, task.Statuses(), w.Allowed(""))
+}
+
+func TestWorkflow_Allowed_ShouldIncludeAnySource(t *testing.T) {
+	w := guarded()
+
+	assert.Equal(t, []task.Status{"doing", "wontfix"}, w.Allowed("todo"))
+	assert.Equal(t, []task.Status{}, w.Allowed("wontfix"))
+}
+
+func TestWorkflow_Apply_Allowed_ShouldApplyActions(t *testing.T) {
+	w := guarded()
+
+	done, tr, err := w.Apply(task.Task{Status: "doing"}, task.Task{Status: "done"}, testNow)
+	require.NoError(t, err)
+	reopened, _, err := w.Apply(done, task.Task{Status: "doing", ResolvedAt: done.ResolvedAt}, testNow)
+	require.NoError(t, err)
+
+	require.NotNil(t, done.ResolvedAt)
+	assert.Equal(t, testNow, *done.ResolvedAt)
+	assert.True(t, tr.Notifies())
+	assert.Nil(t, reopened.ResolvedAt)
+}
+
+func TestWorkflow_Apply_NotAllowed_ShouldListAllowed(t *testing.T) {
+	w := guarded()
+
+	_, _, err := w.Apply(task.Task{Status: "todo"}, task.Task{Status: "done"}, testNow)
+
+	var transErr *task.TransitionError
+	require.ErrorAs(t, err, &transErr)
+	assert.Equal(t, &task.TransitionError{
+		From:    "todo",
+		To:      "done",
+		Allowed: []task.Status{"doing", "wontfix"},
+	}, transErr)
+}
+
+func TestWorkflow_Apply_FailedGuard_ShouldReportReason(t *testing.T) {
+	w := guarded()
+
+	_, _, noAssignee := w.Apply(task.Task{Status: "todo"}, task.Task{Status: "doing", Estimate: 3}, testNow)
+	_, _, noEstimate := w.Apply(task.Task{Status: "todo"}, task.Task{Status: "doing", Assignee: "alice"}, testNow)
+	_, _, passed := w.Apply(task.Task{Status: "todo"}, task.Task{Status: "doing", Assignee: "alice", Estimate: 3}, testNow)
+
+	assert.EqualError(t, noAssignee, "assignee is required")
+	assert.EqualError(t, noEstimate, "estimate is required")
+	assert.NoError(t, passed)
+}
+
+func TestWorkflow_Apply_Creation_ShouldApplyResolutionActions(t *testing.T) {
+	w := guarded()
+	earlier := testNow.Add(-time.Hour)
+
+	ready := task.Task{Assignee: "alice", Estimate: 3}
+	ready.Status = "done"
+	done, tr, err := w.Apply(task.Task{}, ready, testNow)
+	require.NoError(t, err)
+	ready.ResolvedAt = &earlier
+	kept, _, _ := w.Apply(task.Task{}, ready, testNow)
+	ready.Status = "doing"
+	reopened, _, _ := w.Apply(task.Task{}, ready, testNow)
+
+	require.NotNil(t, done.ResolvedAt)
+	assert.Equal(t, testNow, *done.ResolvedAt)
+	assert.False(t, tr.Notifies())
+	assert.Equal(t, earlier, *kept.ResolvedAt)
+	assert.Nil(t, reopened.ResolvedAt)
+}
+
+func TestWorkflow_Apply_Creation_ShouldAllowReachableStates(t *testing.T) {
+	w := guarded()
+
+	_, _, initial := w.Apply(task.Task{}, task.Task{Status: "todo"}, testNow)
+	_, _, anyFrom := w.Apply(task.Task{}, task.Task{Status: "wontfix"}, testNow)
+	_, _, ready := w.Apply(task.Task{}, task.Task{Status: "done", Assignee: "alice", Estimate: 3}, testNow)
+	_, _, unknown := w.Apply(task.Task{}, task.Task{Status: "review"}, testNow)
+
+	assert.NoError(t, initial)
+	assert.NoError(t, anyFrom)
+	assert.NoError(t, ready)
+	assert.EqualError(t, unknown, "status review is not allowed")
+}
+
+func TestWorkflow_Apply_Creation_ShouldCheckGuardsOnTheWay(t *testing.T) {
+	w := guarded()
+
+	_, _, err := w.Apply(task.Task{}, task.Task{Status: "done", Estimate: 3}, testNow)
+
+	var transErr *task.TransitionError
+	require.ErrorAs(t, err, &transErr)
+	assert.Equal(t, &task.TransitionError{
+		To:      "done",
+		Allowed: []task.Status{"todo", "wontfix"},
+		Reason:  "assignee is required",
+	}, transErr)
+}
+
+func TestWorkflow_Apply_Unchanged_ShouldPass(t *testing.T) {
+	_, tr, err := guarded().Apply(task.Task{Status: "todo"}, task.Task{Status: "todo", Title: "Changed"}, testNow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.Transition{}, tr)
+}
+
+func TestWorkflow_Validate_Invalid_ShouldReportFields(t *testing.T) {
+	w := workflow.Workflow{
+		States: []task.Status{"todo", "todo", "In progress"},
+		Transitions: []workflow.Transition{
+			{From: "todo", To: "todo"},
+			{From: "backlog", To: "done"},
+			{From: "*", To: "todo", Guards: []workflow.Guard{"owner"}, Actions: []workflow.Action{"email"}},
+			{From: "*", To: "todo"},
+		},
+	}
+
+	assert.Equal(t, []decode.FieldError{
+		{Field: "states.1", Message: "must be unique"},
+		{Field: "states.2", Message: "must be a lowercase identifier of at most 30 characters"},
+		{Field: "transitions.0.to", Message: "must differ from the source state"},
+		{Field: "transitions.1.from", Message: "must be a state of the workflow or *"},
+		{Field: "transitions.1.to", Message: "must be a state of the workflow"},
+		{Field: "transitions.2.guards.0", Message: "must be one of assignee-required, estimate-required"},
+		{Field: "transitions.2.actions.0", Message: "must be one of set-resolved, clear-resolved, notify"},
+		{Field: "transitions.3", Message: "must be unique"},
+	}, w.Validate())
+}
+
+func TestWorkflow_Clone_ShouldNotShareState(t *testing.T) {
+	w := guarded()
+
+	c := w.Clone()
+	c.States[0] = "backlog"
+	c.Transitions[0].Guards[0] = workflow.GuardEstimate
+
+	assert.Equal(t, task.Status("todo"), w.States[0])
+	assert.Equal(t, workflow.GuardAssignee, w.Transitions[0].Guards[0])
+}